@@ -13,11 +13,11 @@ Part B:
 */
 
 import (
-	"container/ring"
 	"flag"
 	"fmt"
-	"strconv"
-	"strings"
+	"os"
+
+	"aoc2017/day01"
 )
 
 var input = flag.String("input", "1122", "Digits to use")
@@ -26,34 +26,16 @@ var partB = flag.Bool("partB", false, "Perform part B solution?")
 func main() {
 	flag.Parse()
 
-	digits := ring.New(len(*input))
-	for _, d := range strings.Split(*input, "") {
-		digit, err := strconv.Atoi(d)
-		if err != nil {
-			fmt.Printf("Couldn't convert %d to a string: %v", d, err)
-			return
-		}
-		digits.Value = digit
-		digits = digits.Next()
+	digits, err := day01.Parse(*input)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
 	}
-	// Zip back to the "start"
-	digits = digits.Move(-1 * digits.Len())
-	sum := 0
-
-	for n := 0; n < digits.Len(); n++ {
-		if *partB {
-			// Part B logic, compare to position at len/2
-			if digits.Value == digits.Move(digits.Len()/2).Value.(int) {
-				sum += digits.Value.(int)
-			}
-		} else {
-			// Part A logic
-			if digits.Value == digits.Next().Value.(int) {
-				sum += digits.Value.(int)
-			}
-		}
-		digits = digits.Next()
+	// Part A compares to the next digit, part B to the one at len/2
+	offset := 1
+	if *partB {
+		offset = len(digits) / 2
 	}
 
-	fmt.Printf("Sum: %d\n", sum)
+	fmt.Printf("Sum: %d\n", day01.Sum(digits, offset))
 }
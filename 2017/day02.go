@@ -27,13 +27,11 @@ end
 */
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
-	"math"
 	"os"
-	"strconv"
-	"strings"
+
+	"aoc2017/day02"
 )
 
 var inputFile = flag.String("inputFile", "inputs/day02-example.txt", "Input file for Day 2")
@@ -44,53 +42,20 @@ func main() {
 
 	input, err := os.Open(*inputFile)
 	if err != nil {
-		fmt.Printf("Couldn't open %s for read: %v", inputFile, err)
+		fmt.Printf("Couldn't open %s for read: %v", *inputFile, err)
 		os.Exit(1)
 	}
+	defer input.Close()
 
-	sum := 0
-
-	lineReader := bufio.NewScanner(input)
-	for lineReader.Scan() {
-		var lowest = math.MaxInt32
-		var highest = -1
-		var lineNumbers []int
+	rows, err := day02.Parse(input)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
 
-		for _, d := range strings.Split(lineReader.Text(), "\t") {
-			// Parse numbers on the line
-			number, err := strconv.Atoi(d)
-			if err != nil {
-				fmt.Printf("Couldn't convert >%s< to a number: %v", d, err)
-				os.Exit(1)
-			}
-			if *partB {
-				// part B
-				lineNumbers = append(lineNumbers, number)
-			} else {
-				// part A
-				if number < lowest {
-					lowest = number
-				}
-				if number > highest {
-					highest = number
-				}
-			}
-		} // done processing numbers for this line, make checksum
-		if *partB {
-			for i := 0; i < len(lineNumbers); i++ {
-			inner:
-				for j := 0; j < len(lineNumbers); j++ {
-					if j == i {
-						continue inner
-					}
-					if math.Remainder(float64(lineNumbers[i]), float64(lineNumbers[j])) == 0 {
-						sum += lineNumbers[i] / lineNumbers[j]
-					}
-				}
-			}
-		} else {
-			sum += highest - lowest
-		}
+	rowSum := day02.Spread
+	if *partB {
+		rowSum = day02.Quotients
 	}
-	fmt.Printf("Table checksum: %d\n", sum)
+	fmt.Printf("Table checksum: %d\n", day02.Checksum(rows, rowSum))
 }
@@ -28,44 +28,16 @@ After each jump, if the offset was 3 or more, decrease it by 1, otherwise increm
 
 */
 import (
-	"bufio"
-	"bytes"
-	"container/ring"
 	"flag"
 	"fmt"
-	"io"
 	"os"
-	"strconv"
+
+	"aoc2017/day05"
 )
 
 var inputFile = flag.String("inputFile", "./inputs/day05-example.txt", "Instructions Input File")
 var partB = flag.Bool("partB", false, "Perform part B solution?")
 
-type Instruction struct {
-	Step int
-}
-
-// https://stackoverflow.com/questions/24562942/golang-how-do-i-determine-the-number-of-lines-in-a-file-efficiently
-// Quicker than iterating with bufio
-func countLines(r io.Reader) (int, error) {
-	buf := make([]byte, 32*1024)
-	count := 0
-	lineSep := []byte{'\n'}
-
-	for {
-		c, err := r.Read(buf)
-		count += bytes.Count(buf[:c], lineSep)
-
-		switch {
-		case err == io.EOF:
-			return count, nil
-
-		case err != nil:
-			return count, err
-		}
-	}
-}
-
 func main() {
 	flag.Parse()
 
@@ -75,59 +47,18 @@ func main() {
 		os.Exit(1)
 	}
 	defer input.Close()
-	lines, err := countLines(input)
+
+	offsets, err := day05.Parse(input)
 	if err != nil {
-		fmt.Printf("Couldn't count the lines in %s: %s\n", inputFile, err)
+		fmt.Printf("%s\n", err)
 		os.Exit(1)
 	}
-	input.Seek(0, 0)
+	fmt.Printf("Lines: %d\n", len(offsets))
 
-	lineReader := bufio.NewScanner(input)
-	fmt.Printf("Lines: %d\n", lines)
-
-	instructions := ring.New(lines)
-
-	// Build instruction set
-	for lineReader.Scan() {
-		instruction, err := strconv.Atoi(lineReader.Text())
-
-		if err != nil {
-			fmt.Printf("Couldn't convert to int: %s\n", err)
-			os.Exit(1)
-		}
-		instructions.Value = Instruction{Step: instruction}
-		instructions = instructions.Next()
+	// part A: Add 1, part B: add (stepAmount >= 3 ? -1 : 1)
+	update := day05.Increment
+	if *partB {
+		update = day05.Converge
 	}
-
-	outOfBounds := false
-	// We'll be zero-based in our reckoning even though the Ring is one-based.
-	instructionPosition := 0
-	jumps := 0
-	stepAmount := 0
-	instruction := instructions.Value
-	incrementOffset := 1
-
-	for !outOfBounds {
-		instruction = instructions.Value.(Instruction)
-		stepAmount = instruction.(Instruction).Step
-
-		if instructionPosition+stepAmount > instructions.Len()+1 {
-			outOfBounds = true
-		} else {
-			jumps += 1
-			instructionPosition += stepAmount
-
-			if *partB && stepAmount >= 3 {
-				incrementOffset = -1
-			} else {
-				incrementOffset = 1
-			}
-			// Store new "step" for next time we see this instruction
-			// part A: Add 1, part B: add (stepAmount >= 3 ? -1 : 1)
-			instructions.Value = Instruction{Step: stepAmount + incrementOffset}
-			instructions = instructions.Move(stepAmount)
-		}
-	}
-
-	fmt.Printf("Jumps needed: %d\n", jumps)
+	fmt.Printf("Jumps needed: %d\n", day05.Jumps(offsets, update))
 }
@@ -42,81 +42,20 @@ Exactly one node has the incorrect weight: Identify the unbalanced node and dete
 */
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
+
+	"aoc2017/day07"
 )
 
 var inputFile = flag.String("inputFile", "./inputs/day07-example.txt", "Input file")
 var partB = flag.Bool("partB", false, "Perform part B solution?")
+var report = flag.Bool("report", false, "Print every subtree's weight and every imbalance")
+var dotFile = flag.String("dot", "", "Write the tower to this file as Graphviz DOT")
 
-type Node struct {
-	Name       string
-	Children   []*Node
-	Weight     int
-	TreeWeight int
-	Parent     *Node
-}
-
-func NewNode(name string, weight int) *Node {
-	return &Node{
-		Name:       name,
-		Weight:     weight,
-		TreeWeight: weight,
-	}
-}
-
-/* Return both the parent and child because both need updating at the caller */
-func (n *Node) AddChild(c *Node) (*Node, *Node) {
-	n.Children = append(n.Children, c)
-	c.Parent = n
-	return n, c
-}
-
-// Compute the weights of all trees under n
-func (n *Node) ComputeTreeWeight() (*Node, int) {
-	var sum, subtreeSum int
-	sum = n.Weight // Start with the sum as the current node's weight
-	for _, node := range n.Children {
-		node, subtreeSum = node.ComputeTreeWeight()
-		sum += subtreeSum
-	}
-	n.TreeWeight = sum
-	return n, n.TreeWeight
-}
-
-// Return a node's sibling
-func (n *Node) GetSibling() *Node {
-	var ret *Node
-	for _, childNode := range n.Parent.Children {
-		if childNode != n {
-			ret = childNode
-		}
-	}
-	return ret
-}
-
-// Return true if the node is balanced
-// Return false if it is not along with the offending node causing unbalance and its offset relative to the correct weights
-func (n *Node) IsTreeBalanced() (bool, *Node, int) {
-	sum := 0
-	for _, childNode := range n.Children {
-		sum += childNode.TreeWeight
-	}
-	// Histogram will be weight => [Nodes with this weight]
-	weightHistogram := make(map[int][]*Node)
-	for _, child := range n.Children {
-		weightHistogram[child.TreeWeight] = append(weightHistogram[child.TreeWeight], child)
-	}
-	for _, weight := range weightHistogram {
-		if len(weight) == 1 {
-			return false, weight[0], weight[0].TreeWeight - weight[0].GetSibling().TreeWeight
-		}
-	}
-	return true, nil, 0
-}
 func main() {
 	flag.Parse()
 	input, err := os.Open(*inputFile)
@@ -126,74 +65,35 @@ func main() {
 	}
 	defer input.Close()
 
-	// Create a map of name -> Node for quicker access, especially when building
-	tower := make(map[string]*Node)
-
-	// Map of parents => children. Keys are names of nodes whose children are the values
-	children := make(map[string][]string)
-
-	lineReader := bufio.NewScanner(input)
-	for lineReader.Scan() {
-		// loop over tokens separated by spaces
-		line := lineReader.Text()
-		var nodeName string
-		var nodeWeight int
-
-		for n, token := range strings.Split(line, " ") {
-			switch {
-			case n == 0:
-				// name
-				nodeName = token
-			case n == 1:
-				// weight
-				fmt.Sscanf(token, "(%d)", &nodeWeight)
-			case n == 2:
-				// ->
-				continue
-			case n > 2:
-				// list of children whose name may end in ,
-				childName := strings.TrimSuffix(token, ",")
-				children[nodeName] = append(children[nodeName], childName)
-			} // end switch, which means we have all the fields we need to make a new node
-			tower[nodeName] = NewNode(nodeName, nodeWeight)
-		} //we've read every line
-	} // EOF
-	// Go through and turn references to names into pointers
-	for parentName, childNames := range children {
-		// name => list of name's children
-		for _, child := range childNames {
-			tower[parentName], tower[child] = tower[parentName].AddChild(tower[child])
-		}
+	rootNode, err := day07.Parse(input)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		os.Exit(1)
 	}
-	// at this point the tower has parentage, so, the only Node without a parent is the base.
-	var rootNode *Node
-	for _, node := range tower {
-		if node.Parent == nil {
-			rootNode = node
+
+	if *report {
+		rootNode.Report(os.Stdout, 0)
+		for _, imbalance := range rootNode.Imbalances() {
+			fmt.Printf("Unbalanced: %s\n", imbalance)
 		}
 	}
-
-	rootNode, _ = rootNode.ComputeTreeWeight()
-
-	if *partB {
-		balanced, offender, offset := rootNode.IsTreeBalanced()
-
-		var lastOffender *Node
-		var lastOffset int
-		workingNode := offender
-		for !balanced {
-			lastOffset = offset
-			lastOffender = offender
-			balanced, offender, offset = workingNode.IsTreeBalanced()
-			if !balanced {
-				workingNode = offender
-			}
+	fix, fixErr := rootNode.Diagnose()
+	if *dotFile != "" {
+		if err := ioutil.WriteFile(*dotFile, []byte(rootNode.DOT(fix)), 0644); err != nil {
+			fmt.Printf("Couldn't write %s: %s\n", *dotFile, err)
+			os.Exit(1)
 		}
-		if lastOffender != nil {
+	}
 
-			fmt.Printf("%s is not balanced! Adjust its weight by %d (should be %d)\n", lastOffender.Name, -1*lastOffset, lastOffender.Weight+(-1*lastOffset))
+	if *partB && fixErr != nil {
+		fmt.Printf("%s\n", fixErr)
+		os.Exit(1)
+	} else if *partB {
+		fmt.Printf("%s is not balanced! Adjust its weight by %d (should be %d)\n", fix.Node.Name, fix.To-fix.From, fix.To)
+		fmt.Printf("Path from the base: %s\n", strings.Join(fix.Path(), " -> "))
+		for _, step := range fix.Proof {
+			fmt.Printf("  %s\n", step)
 		}
-
 	} else {
 
 		fmt.Printf("%s is the base\n", rootNode.Name)
@@ -18,6 +18,8 @@ Modification Operands:
 inc
 dec
 
+The language lives in src/reglang, which also knows mul, set and jmp.
+
 ex:
 
 b inc 5 if a > 1
@@ -34,71 +36,25 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"strconv"
-	"strings"
+
+	"aoc2017/day08"
+	"reglang"
 )
 
 var inputFile = flag.String("inputFile", "./inputs/day08-example.txt", "Input file")
 var partB = flag.Bool("partB", false, "Perform part B solution")
 var debug = flag.Bool("debug", false, "Trace execution?")
 var step = flag.Bool("step", false, "Step execution?")
-
-type LineOfCode struct {
-	Register       string // 0
-	Operation      string // 1
-	ModifyBy       int    // 2
-	LogicRegister  string // 4
-	LogicOperation string // 5
-	LogicTest      int    // 6
-
-	RawLine string // the raw line from input
-}
-
-/* Returns if work was done and the (new) value, if any, of Register
-Give current value of 'Register' and 'LogicRegister' as parameters
-*/
-func (loc LineOfCode) Execute(valueOfRegister, valueOfLogicRegister int) (bool, int) {
-	ret := valueOfRegister
-	doWork := false
-	switch loc.LogicOperation {
-	case ">":
-		doWork = (valueOfLogicRegister > loc.LogicTest)
-	case "<":
-		doWork = (valueOfLogicRegister < loc.LogicTest)
-	case ">=":
-		doWork = (valueOfLogicRegister >= loc.LogicTest)
-	case "<=":
-		doWork = (valueOfLogicRegister <= loc.LogicTest)
-	case "!=":
-		doWork = (valueOfLogicRegister != loc.LogicTest)
-	case "==":
-		doWork = (valueOfLogicRegister == loc.LogicTest)
-	default:
-		fmt.Printf("Unknown operation %s. Aborting\n", loc.LogicOperation)
-		os.Exit(1)
-	}
-	if doWork {
-		switch loc.Operation {
-		case "inc":
-			ret = valueOfRegister + loc.ModifyBy
-		case "dec":
-			ret = valueOfRegister - loc.ModifyBy
-		default:
-			fmt.Printf("Unexpected operation %s. (Expected 'inc' or 'dec'.) Aborting\n", loc.Operation)
-			os.Exit(1)
-		}
-	}
-
-	return doWork, ret
-}
-
-func (loc LineOfCode) String() string {
-	return fmt.Sprintf("%s %s %d if %s %s %d", loc.Register, loc.Operation, loc.ModifyBy, loc.LogicRegister, loc.LogicOperation, loc.LogicTest)
-}
-
-func PrintRegisters(registers map[string]int) {
-	for register, value := range registers {
-		fmt.Printf("%s=%v\n", register, value)
+var limit = flag.Int("limit", 0, "Stop after this many steps, for programs that jmp (0 = no limit)")
+var csvFile = flag.String("csv", "", "Write every register change to this CSV file")
+var valueOf = flag.String("valueOf", "", "Report the value of this register after -after steps")
+var after = flag.Int("after", 0, "Step for -valueOf (step N is line N if nothing jumps)")
+var negative = flag.String("negative", "", "Report when this register first went negative")
+
+// PrintRegisters - every register and its value, by name
+func PrintRegisters(m *reglang.Machine) {
+	for _, register := range m.Names() {
+		fmt.Printf("%s=%v\n", register, m.Value(register))
 	}
 }
 
@@ -116,106 +72,99 @@ func main() {
 		fmt.Printf("Couldn't read file: %s\n", err)
 		os.Exit(1)
 	}
-	defer input.Close()
-	lineReader := bufio.NewScanner(input)
-
-	// Registers name => value
-	registers := make(map[string]int)
-	runtimeHighest := 0
-	runtimeHighestRegister := ""
-
-	for lineReader.Scan() {
-		// loop over tokens separated by spaces
-		line := lineReader.Text()
-		var lineOfCode LineOfCode
-		lineOfCode.RawLine = line
-		for n, token := range strings.Split(line, " ") {
-			switch n {
-			/*
-						   0: register
-						   1: operation
-						   2: modifyBy
-						   3: noop
-						   4: logicRegister
-						   5: logicOperation
-				       6: logicTest
-			*/
-			case 0:
-				lineOfCode.Register = token
-			case 1:
-				lineOfCode.Operation = token
-			case 2:
-				modifyBy, err := strconv.Atoi(token)
-				if err != nil {
-					fmt.Printf("Couldn't convert %s to a number: %s\n", token, err)
-					os.Exit(1)
-				}
-				lineOfCode.ModifyBy = modifyBy
-			case 3:
-				continue //noop
-			case 4:
-				lineOfCode.LogicRegister = token
-			case 5:
-				lineOfCode.LogicOperation = token
-			case 6:
-				logicTest, err := strconv.Atoi(token)
-				if err != nil {
-					fmt.Printf("Couldn't convert %s to a number: %s\n", token, err)
-					os.Exit(1)
-				}
-				lineOfCode.LogicTest = logicTest
-			}
-		} // Processed a line
+	program, err := day08.Load(input)
+	input.Close()
+	if err != nil {
+		fmt.Printf("%s: %s\n", *inputFile, err)
+		os.Exit(1)
+	}
+	machine := reglang.NewMachine(program)
+	var history *reglang.History
+	if *partB || *csvFile != "" || *valueOf != "" || *negative != "" {
+		history = machine.Record()
+	}
+
+	for !machine.Done() {
+		if *limit > 0 && machine.Steps >= *limit {
+			fmt.Printf("Stopped after %d steps\n", machine.Steps)
+			break
+		}
+		statement := program.Statements[machine.IP]
+		target := program.Targets[machine.IP]
 		if *step {
 			fmt.Printf("Registers before running\n")
-			PrintRegisters(registers)
+			PrintRegisters(machine)
 			Step()
 		}
 
 		if *debug {
-			fmt.Printf("Executing %s with %s=%d, %s=%d", lineOfCode, lineOfCode.Register, registers[lineOfCode.Register], lineOfCode.LogicRegister, registers[lineOfCode.LogicRegister])
-		}
-		didWork, newValue := lineOfCode.Execute(registers[lineOfCode.Register], registers[lineOfCode.LogicRegister])
-		if didWork {
-			if *debug {
-				fmt.Printf(" #=> %s = %d\n", lineOfCode.Register, newValue)
+			fmt.Printf("Executing %s with %s=%d", statement, statement.Register, machine.Registers[target])
+			if statement.Condition != nil && statement.Condition.Left.Register != "" {
+				fmt.Printf(", %s=%d", statement.Condition.Left.Register, machine.Value(statement.Condition.Left.Register))
 			}
-			registers[lineOfCode.Register] = newValue
-		} else {
-			if *debug {
+		}
+		before := machine.Registers[target]
+		machine.Step()
+		newValue := machine.Registers[target]
+		if *debug {
+			if newValue != before {
+				fmt.Printf(" #=> %s = %d\n", statement.Register, newValue)
+			} else {
 				fmt.Printf(" #=> No Change\n")
 			}
 		}
 		if *step {
 			fmt.Printf("Registers after running\n")
-			PrintRegisters(registers)
+			PrintRegisters(machine)
 			Step()
 		}
-		if *partB {
-			if newValue > runtimeHighest {
-				runtimeHighest = newValue
-				runtimeHighestRegister = lineOfCode.Register
-			}
-		}
-
 	} // EOF
-	highest := 0
-	highestRegister := ""
-	for register, value := range registers {
-		if *debug {
-			fmt.Printf("Final register %s=%d\n", register, value)
-			fmt.Printf("Comparing %s to %s: %d > %d? => %t\n", register, highestRegister, value, highest, value > highest)
-		}
-		if value > highest {
-			if *debug {
-				fmt.Printf("%s usurped %s as highest. Replacing %d with %d\n", register, highestRegister, highest, value)
-			}
-			highest = value
-			highestRegister = register
+	if *debug {
+		for _, register := range machine.Names() {
+			fmt.Printf("Final register %s=%d\n", register, machine.Value(register))
 		}
 	}
+	highestRegister, highest := day08.Highest(machine)
 	fmt.Printf("Highest register is %s, value of %d\n", highestRegister, highest)
 	if *partB {
-		fmt.Printf("During runtime the highest value was %d, stored in register %s\n", runtimeHighest, runtimeHighestRegister)
+		if max, ok := history.AllTimeMax(); ok {
+			fmt.Printf("During runtime the highest value was %d, stored in register %s\n", max.New, program.Registers[max.Register])
+			fmt.Printf("Set at %s\n", history.Describe(max))
+		} else {
+			fmt.Printf("No register ever went above 0, where they all started\n")
+		}
+	}
+	if *valueOf != "" {
+		value, err := history.ValueAfter(*valueOf, *after)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("After %d steps %s=%d\n", *after, *valueOf, value)
+	}
+	if *negative != "" {
+		change, found, err := history.FirstNegative(*negative)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		if found {
+			fmt.Printf("%s first went negative at %s\n", *negative, history.Describe(change))
+		} else {
+			fmt.Printf("%s never went negative\n", *negative)
+		}
+	}
+	if *csvFile != "" {
+		out, err := os.Create(*csvFile)
+		if err != nil {
+			fmt.Printf("Couldn't create %s: %s\n", *csvFile, err)
+			os.Exit(1)
+		}
+		defer out.Close()
+		if err := history.WriteCSV(out); err != nil {
+			fmt.Printf("Couldn't write %s: %s\n", *csvFile, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote %d changes to %s\n", len(history.Changes), *csvFile)
 	}
 }
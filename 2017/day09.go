@@ -31,7 +31,10 @@ neither does ! nor the character(s) being cancelled by !.
 import (
 	"flag"
 	"fmt"
-	"strings"
+	"io"
+	"os"
+
+	"aoc2017/day09"
 )
 
 var input = flag.String("input", "{}", "Input string for the program")
@@ -40,52 +43,11 @@ var debug = flag.Bool("debug", false, "Debug output")
 
 func main() {
 	flag.Parse()
-	// Loop over each character
-	characters := strings.Split(*input, "")
-	depth := 0             // Group depth; used with scoring
-	score := 0             // Total score
-	garbageCharacters := 0 // Cleaned up garbage characters
-	ignoreNext := false
-	ignoreGroups := false
-	for i, token := range characters {
-		if *debug {
-			fmt.Printf("[%d/%d] score: %d, depth: %d, ignoreNext: %t, ignoreGroups: %t, token %s\n", i, len(*input)-1, score, depth, ignoreNext, ignoreGroups, token)
-		}
-		if ignoreNext {
-			// Clear and skip this character
-			ignoreNext = false
-			continue
-		}
-		switch token {
-		case "{":
-			if !ignoreGroups {
-				depth += 1
-			} else {
-				garbageCharacters += 1
-			}
-		case "<":
-			if ignoreGroups {
-				// Already ignoring, so we need to bump garbageCharacters
-				garbageCharacters += 1
-			}
-			ignoreGroups = true // start of garbage
-		case ">":
-			ignoreGroups = false // end of garbage
-		case "}":
-			if !ignoreGroups {
-				score += depth
-				depth -= 1
-			} else {
-				garbageCharacters += 1
-			}
-		case "!":
-			ignoreNext = true
-		default:
-			if ignoreGroups {
-				garbageCharacters += 1
-			}
-		}
-	} // EOF
+	var trace io.Writer
+	if *debug {
+		trace = os.Stdout
+	}
+	score, garbageCharacters := day09.Process(*input, trace)
 
 	if *partB {
 		fmt.Printf("Garbage characters: %d\n", garbageCharacters)
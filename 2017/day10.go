@@ -54,263 +54,38 @@ AoC 2017 = 33efeb34ea91902bb2f59c9920caa6cd.
 */
 
 import (
-	"container/ring"
 	"flag"
 	"fmt"
 	"os"
-	"strconv"
-	"strings"
+
+	"aoc2017/day10"
+	"knothash"
 )
 
 var input = flag.String("input", "3,4,1,5", "Input for day 10")
 var listLen = flag.Int("listLen", 5, "Length of the list")
 var debug = flag.Bool("debug", false, "Debug output")
-var debugHashComputation = flag.Bool("debugHashComputation", false, "Debug hash computations")
 var partB = flag.Bool("partB", false, "Perform part B solution?")
 
-// Append this to the input after it has been converted to ASCII for part B.
-var partBSuffix = []int{17, 31, 73, 47, 23}
-
-// For part B:
-func ComputeDenseHash(ring *ring.Ring) []byte {
-	ret := make([]int, 16)
-	for chunk := 0; chunk < 16; chunk++ {
-		if *debugHashComputation {
-			fmt.Printf("ComputeDenseHash chunk %d\n", chunk)
-		}
-		// "Seed" the chunk bits with the first value of the 16 digits for ^=
-		ret[chunk] = ring.Value.(int)
-		if *debugHashComputation {
-			fmt.Printf(" * (digit=0) = %d\n", ring.Value.(int))
-		}
-
-		ring = ring.Next()
-		for digit := 1; digit < 16; digit++ {
-			// The digit-th digit in the dense hash
-			if *debugHashComputation {
-				fmt.Printf(" * (digit=%d) %d ^ %d == %d\n", digit, ret[chunk], ring.Value.(int), ret[chunk]^ring.Value.(int))
-			}
-			ret[chunk] ^= ring.Value.(int)
-			ring = ring.Next()
-		} // finsihed 16 digits
-		if *debugHashComputation {
-			fmt.Printf("Chunk %d: %d=%x\n", chunk, ret[chunk], ret[chunk])
-		}
-	} // done with the chunks
-
-	// coerce to []byte
-	byteRet := make([]byte, len(ret))
-	for i := 0; i < len(ret); i++ {
-		byteRet[i] = byte(ret[i])
-	}
-	return byteRet
-}
-
-// Print the ring, optionally highlighting the `highlight` value
-func PrintRing(r *ring.Ring, highlight int) {
-	for i := 0; i < r.Len(); i++ {
-		if r.Value == highlight {
-			fmt.Printf("(%d)", r.Value)
-		} else {
-			fmt.Printf("%d", r.Value)
-		}
-		if i < r.Len()-1 {
-			fmt.Printf("->")
-		} else {
-			fmt.Printf("\n")
-		}
-		r = r.Next()
-	}
-}
-
-func PrintRingFrom(r *ring.Ring, startAt int) {
-	// Find startAt in the ring and then print based off of it. Make a new Ring
-	// to keep caller safe.
-	if startAt < 0 {
-		PrintRing(r, -1)
-		return
-	}
-	tempRing := ring.New(r.Len())
-	for i := 0; i < r.Len(); i++ {
-		tempRing.Value = r.Value
-		tempRing = tempRing.Next()
-		r = r.Next()
-	}
-	// Both rings will do a full run through their lengths and end up back at
-	// "start"
-	i := 0
-	for tempRing.Value != startAt || i == tempRing.Len() {
-		tempRing = tempRing.Next()
-		i += 1 // just in case we loop around
-	} // Found the start
-	PrintRing(tempRing, -1)
-	return
-}
-
-func ReverseRingSlice(r *ring.Ring, sliceLen int) *ring.Ring {
-	if sliceLen <= 1 {
-		//nothing to do
-		return r
-	}
-	returnRing := ring.New(r.Len())
-	r = r.Move(sliceLen - 1)
-	newRing := ring.New(sliceLen)
-	for i := 0; i < sliceLen; i++ {
-		newRing.Value = r.Value
-		newRing = newRing.Next()
-		r = r.Prev()
-	}
-	if *debug {
-		fmt.Printf("Set up temporary ring (len=%d): ", newRing.Len())
-		PrintRing(newRing, -1)
-	}
-	// build from newRing until i > newRing.Len(), then use r.
-	// Make sure r is ready to be read in the right order, +1 to undo Prev() above
-	r = r.Move(sliceLen + 1)
-	for i := 0; i < returnRing.Len(); i++ {
-		if i < newRing.Len() {
-			returnRing.Value = newRing.Value
-			newRing = newRing.Next()
-		} else {
-			returnRing.Value = r.Value
-			r = r.Next()
-		}
-		returnRing = returnRing.Next()
-	}
-	for i := 0; i < returnRing.Len(); i++ {
-		returnRing = returnRing.Next()
-	}
-
-	return returnRing
-}
-
-/*
-return a slice of input numbers.
-For part A this will treat `input` as a comma-separated list.
-For part B this will treat each character as something to conver to its ASCII
-representation.
-*/
-func userInputToLengths(input *string) []int {
-	ret := make([]int, 0)
-	if *partB {
-		for _, char := range strings.Split(*input, "") {
-			ret = append(ret, int(char[0]))
-		}
-		for i := 0; i < len(partBSuffix); i++ {
-			ret = append(ret, partBSuffix[i])
-		}
-	} else {
-		for _, numberString := range strings.Split(*input, ",") {
-			number, err := strconv.Atoi(numberString)
-			if err != nil {
-				fmt.Printf("Couldn't convert %s to a number: %s\n", numberString, err)
-				os.Exit(1)
-			}
-			ret = append(ret, number)
-		}
-	}
-	return ret
-}
-
-/* Performs a single round */
-func doRound(inputLengths []int, skipSize, totalSkips *int, ring *ring.Ring) *ring.Ring {
-	if *debug {
-		fmt.Printf("doRound inputLengths: %v, skipSize=%d, totalSkips=%d\n", inputLengths, *skipSize, *totalSkips)
-		fmt.Printf("Input ring ")
-		PrintRingFrom(ring, -1)
-	}
-	for number := 0; number < len(inputLengths); number++ {
-		if *debug {
-			fmt.Printf(" Reversing %d digits\n", inputLengths[number])
-		}
-		ring = ReverseRingSlice(ring, inputLengths[number])
-		if *debug {
-			fmt.Printf(" Reversed portion : ")
-			PrintRingFrom(ring, -1)
-		}
-		// Current position moves forward by length + skipSize
-		if *debug {
-			fmt.Printf("Skipping main ring by length=%d+skipsize=%d total=%d\n", inputLengths[number], *skipSize, inputLengths[number]+*skipSize)
-		}
-		ring = ring.Move(inputLengths[number] + *skipSize)
-		if *debug {
-			fmt.Printf("Input (%d/%d) Skip ring: ", number+1, len(inputLengths))
-			PrintRingFrom(ring, -1)
-		}
-		// Save the total number of skips for later rewinding
-		*totalSkips += inputLengths[number] + *skipSize
-		// Then increase skipSize
-		*skipSize += 1
-	} // done with input
-	return ring
-}
-
 func main() {
 	flag.Parse()
-	skipSize := 0
-	totalSkips := 0
-	var rounds int
 
-	ring := ring.New(*listLen)
-	for i := 0; i < *listLen; i++ {
-		ring.Value = i
-		ring = ring.Next()
-	}
-	// Step 1
-	inputLengths := *input
 	if *partB {
-		rounds = 64
-		//		inputLengths += partBSuffix
+		fmt.Printf("Dense hash: %02x\n", knothash.Sum([]byte(*input)))
 	} else {
-		rounds = 1
-	}
-	if *debug {
-		fmt.Printf("rounds: %d\n", rounds)
-	}
-	numbers := userInputToLengths(&inputLengths)
-	if *debug {
-		fmt.Printf("Input lengths: %d, literal=%s\n", numbers, inputLengths)
-	}
-	//Step 1 complete
-
-	// Step 2 - Perform rounds
-	for i := 0; i < rounds; i++ {
-		if *debug {
-			fmt.Printf("Running round %d. skipSize=%d, totalSkips=%d, numbers=%v\n",
-				i+1, skipSize, totalSkips, numbers)
+		numbers, err := day10.Lengths(*input)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
 		}
-		ring = doRound(numbers, &skipSize, &totalSkips, ring)
-
-		if *debug {
-			fmt.Printf("Round %d is over. skipSize=%d, totalSkips=%d\n", i+1, skipSize, totalSkips)
-			fmt.Printf("Ring: ")
-			PrintRing(ring, -1)
-			fmt.Println()
+		list, err := knothash.Sparse(numbers, *listLen, 1)
+		if err != nil {
+			fmt.Printf("Couldn't hash: %s\n", err)
+			os.Exit(1)
 		}
-	}
-	//part A only?
-	if true || !*partB {
-		// Go back to the "beginning," undoing skipping from rounds.
 		if *debug {
-			fmt.Printf("Moving back %d spots to get back to the 'start' of the ring\n", -1*totalSkips)
+			fmt.Printf("Input lengths: %d, list after one round: %d\n", numbers, list)
 		}
-
-		ring = ring.Move(-1 * totalSkips)
-	}
-
-	if *debug {
-		fmt.Printf("Ring after rounds: ")
-		PrintRing(ring, -1)
-	}
-
-	// Steps 3-4
-	dense := ComputeDenseHash(ring)
-
-	// Step 5
-
-	if *partB {
-		fmt.Printf("Dense hash: %02x\n", dense)
-	} else {
-		fmt.Printf("Product of first two %d * %d = %d\n", ring.Value, ring.Next().Value, (ring.Value.(int))*(ring.Next().Value.(int)))
+		fmt.Printf("Product of first two %d * %d = %d\n", list[0], list[1], list[0]*list[1])
 	}
 }
@@ -28,43 +28,18 @@ Count the number of pipe groups. In the example the are 2:
 */
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
+	"io"
 	"os"
-	"strconv"
-	"strings"
+
+	"aoc2017/day12"
 )
 
 var inputFile = flag.String("inputFile", "./inputs/day12-example.txt", "Instructions Input File")
 var partB = flag.Bool("partB", false, "Perform part B solution?")
 var debug = flag.Bool("debug", false, "Debug")
-
-type PipeMap map[int][]int
-
-// How many pipes are in the group pid? ignore pid `ignore` (ie, 0)
-func (p PipeMap) ProgramsOf(pid int, seenPids map[int]bool) {
-	if *debug {
-		fmt.Printf("ProgramsOf: pid=%d map=%v\n", pid, seenPids)
-	}
-	if !seenPids[pid] {
-		seenPids[pid] = true
-		for _, pipePids := range p[pid] {
-			p.ProgramsOf(pipePids, seenPids)
-		}
-	}
-	// base case
-}
-
-func PartA(programs PipeMap, startAt int) map[int]bool {
-	seenPids := make(map[int]bool)
-	programs.ProgramsOf(startAt, seenPids)
-	if *debug {
-		fmt.Printf(" seenPids=%v\n", seenPids)
-	}
-	return seenPids
-
-}
+var groups = flag.Bool("groups", false, "List the members of every group")
 
 func main() {
 	flag.Parse()
@@ -74,58 +49,27 @@ func main() {
 		os.Exit(1)
 	}
 	defer input.Close()
-	lineReader := bufio.NewScanner(input)
-
-	programs := make(PipeMap)
-
-	for lineReader.Scan() {
-		line := lineReader.Text()
-		var program int
-		pipes := make([]int, 0)
-		for n, token := range strings.Split(line, " ") {
 
-			switch n {
-			case 0:
-				program, err = strconv.Atoi(token)
-				if err != nil {
-					fmt.Printf("Couldn't convert %s to a program id: %s\n", token, err)
-					os.Exit(1)
-				}
-			case 1:
-				continue // <->
-			default:
-				endpoint, err := strconv.Atoi(strings.TrimRight(token, ", "))
-				if err != nil {
-					fmt.Printf("Couldn't convert %s to a pipe endpoint: %s\n", token, err)
-					os.Exit(1)
-				}
-				pipes = append(pipes, endpoint)
-			}
-		} // EOL
-		programs[program] = pipes
-	} // EOF
+	var trace io.Writer
 	if *debug {
-		fmt.Printf("programs: %v\n", programs)
+		trace = os.Stdout
 	}
-	if *partB {
-		// Part B
-		/*
-		   For each key, follow its values until they are all mapped out.
-		   Once the mapping is complete, count++ and remove all the mapped items from the original map's keys
-		*/
-		count := 0
-		for pid, _ := range programs {
-			seenMap := PartA(programs, pid)
-			if *debug {
-				fmt.Printf("Part B Mapping for %d: %v\n", pid, seenMap)
-			}
-			for seenPid, _ := range seenMap {
-				delete(programs, seenPid)
-			}
-			count += 1
+	network, err := day12.Parse(input, trace)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		os.Exit(1)
+	}
+	if *debug {
+		fmt.Printf("programs: %v\n", network.Pipes)
+	}
+	if *groups {
+		for _, members := range network.Components() {
+			fmt.Printf("%d programs: %v\n", len(members), members)
 		}
-		fmt.Printf("Discrete groups: %d\n", count)
+	}
+	if *partB {
+		fmt.Printf("Discrete groups: %d\n", network.Groups())
 	} else {
-		fmt.Printf("Part A: %d\n", len(PartA(programs, 0)))
+		fmt.Printf("Part A: %d\n", network.GroupSize(0))
 	}
 }
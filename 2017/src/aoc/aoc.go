@@ -0,0 +1,138 @@
+// Package aoc - the registry every day's solution plugs into, so one command
+// can run any of them.
+//
+// Each day lives in a package of its own (aoc2017/day01, aoc2018/day09, ...)
+// that registers a Solver from an init function, the way database/sql
+// drivers do. The year packages (aoc2017, aoc2018, aoc2019) import all of
+// their days, so importing a year for its side effects registers the lot.
+//
+// 2018 and 2019 use this package too, through the symlinks 2018/src/aoc and
+// 2019/src/aoc.
+package aoc
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Part - one part of a puzzle: read the input from +r+, return the answer
+type Part func(r io.Reader) (string, error)
+
+// Solver - one day's puzzle
+type Solver interface {
+	Year() int
+	Day() int
+	PartA(r io.Reader) (string, error)
+	PartB(r io.Reader) (string, error)
+}
+
+// Defaulter - a Solver with an input built in, for puzzles whose input is a
+// short string rather than a file. It's only used when there's no input file.
+type Defaulter interface {
+	DefaultInput() string
+}
+
+// Pieced - a Solver whose input is saved as more than one file, read one
+// after another. It's only used when there's no whole input file.
+type Pieced interface {
+	InputPieces() []string
+}
+
+// solver - a Solver made of two Parts
+type solver struct {
+	year, day int
+	a, b      Part
+	input     string
+	pieces    []string
+}
+
+func (s *solver) Year() int                         { return s.year }
+func (s *solver) Day() int                          { return s.day }
+func (s *solver) PartA(r io.Reader) (string, error) { return s.a(r) }
+func (s *solver) PartB(r io.Reader) (string, error) { return s.b(r) }
+func (s *solver) DefaultInput() string              { return s.input }
+func (s *solver) InputPieces() []string             { return s.pieces }
+
+// New - a Solver for +day+ of +year+ from its two parts
+func New(year, day int, a, b Part) Solver {
+	return &solver{year: year, day: day, a: a, b: b}
+}
+
+// WithInput - a Solver for +day+ of +year+ whose input defaults to +input+
+func WithInput(year, day int, input string, a, b Part) Solver {
+	return &solver{year: year, day: day, a: a, b: b, input: input}
+}
+
+// WithPieces - a Solver for +day+ of +year+ whose input, when there's no
+// dayNN.txt, is the files +pieces+ in its inputs directory put together
+func WithPieces(year, day int, pieces []string, a, b Part) Solver {
+	return &solver{year: year, day: day, a: a, b: b, pieces: pieces}
+}
+
+// Name - "2018 day 9" for a solver of 2018 day 9
+func Name(s Solver) string {
+	return fmt.Sprintf("%d day %d", s.Year(), s.Day())
+}
+
+// Run - run +part+ ("a" or "b", either case) of +s+ on +r+
+func Run(s Solver, part string, r io.Reader) (string, error) {
+	switch strings.ToLower(part) {
+	case "a":
+		return s.PartA(r)
+	case "b":
+		return s.PartB(r)
+	}
+	return "", fmt.Errorf("unexpected part %q, expected a or b", part)
+}
+
+type key struct {
+	year, day int
+}
+
+var (
+	mu      sync.RWMutex
+	solvers = make(map[key]Solver)
+)
+
+// Register - make +s+ available to Lookup. Panics if its day is already
+// registered, since that means two packages claim the same puzzle.
+func Register(s Solver) {
+	mu.Lock()
+	defer mu.Unlock()
+	k := key{s.Year(), s.Day()}
+	if _, dup := solvers[k]; dup {
+		panic("aoc: Register called twice for " + Name(s))
+	}
+	solvers[k] = s
+}
+
+// Lookup - the solver for +day+ of +year+, if there is one
+func Lookup(year, day int) (Solver, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	s, ok := solvers[key{year, day}]
+	return s, ok
+}
+
+// Solvers - every registered solver, by year then day. A +year+ of 0 means
+// all years.
+func Solvers(year int) []Solver {
+	mu.RLock()
+	defer mu.RUnlock()
+	ret := make([]Solver, 0, len(solvers))
+	for k, s := range solvers {
+		if year == 0 || k.year == year {
+			ret = append(ret, s)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Year() != ret[j].Year() {
+			return ret[i].Year() < ret[j].Year()
+		}
+		return ret[i].Day() < ret[j].Day()
+	})
+	return ret
+}
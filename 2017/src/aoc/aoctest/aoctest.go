@@ -0,0 +1,37 @@
+// Package aoctest - testing the days registered with the aoc package. It's
+// apart from aoc so that nothing but tests has to import "testing".
+package aoctest
+
+import (
+	"testing"
+
+	"aoc"
+)
+
+// VerifyTest - check every answer file in +year+'s inputs, the same as aoc
+// verify -year +year+, with a subtest for each. The days have to be
+// registered, so call it from a package that imports them all.
+func VerifyTest(t *testing.T, year int) {
+	t.Helper()
+	root, err := aoc.FindRoot(".", year)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcomes, err := aoc.Verify(root, year, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcomes) == 0 {
+		t.Fatalf("no answer files in %s", aoc.InputDir(root, year))
+	}
+	for _, o := range outcomes {
+		t.Run(o.Name, func(t *testing.T) {
+			if o.Solver == nil {
+				t.Skipf("no solution for day %d", o.Day)
+			}
+			for _, m := range o.Mismatches {
+				t.Error(m)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package aoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/metrics"
+	"slices"
+	"time"
+)
+
+// Bench - how one part of a day performed over some runs
+type Bench struct {
+	Year   int    `json:"year"`
+	Day    int    `json:"day"`
+	Part   string `json:"part"`
+	Runs   int    `json:"runs"`
+	Answer string `json:"answer,omitempty"`
+	Err    string `json:"error,omitempty"`
+
+	Median time.Duration `json:"median_ns"`
+	Min    time.Duration `json:"min_ns"`
+	// per run, on average
+	Allocs     uint64 `json:"allocs"`
+	AllocBytes uint64 `json:"alloc_bytes"`
+	// PeakBytes - the most the heap grew by during any run
+	PeakBytes uint64 `json:"peak_bytes"`
+}
+
+// Name - "2018 day 9 part b"
+func (b *Bench) Name() string {
+	return fmt.Sprintf("%d day %d part %s", b.Year, b.Day, b.Part)
+}
+
+// BenchReport - a set of Benches and where they were run
+type BenchReport struct {
+	Time    time.Time `json:"time"`
+	Go      string    `json:"go"`
+	Arch    string    `json:"arch"`
+	Benches []*Bench  `json:"benches"`
+}
+
+// NewBenchReport - an empty BenchReport for this machine
+func NewBenchReport() *BenchReport {
+	return &BenchReport{Time: time.Now().UTC(), Go: runtime.Version(), Arch: runtime.GOOS + "/" + runtime.GOARCH}
+}
+
+// LoadBenchReport - the BenchReport saved as JSON at +path+
+func LoadBenchReport(path string) (*BenchReport, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &BenchReport{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Save - write the BenchReport as JSON to +path+
+func (r *BenchReport) Save(path string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(path, append(b, '\n'))
+}
+
+// Find - the Bench for +part+ of +day+ of +year+, or nil
+func (r *BenchReport) Find(year, day int, part string) *Bench {
+	for _, b := range r.Benches {
+		if b.Year == year && b.Day == day && b.Part == part {
+			return b
+		}
+	}
+	return nil
+}
+
+// heapBytes - the metric for the memory held by heap objects, live or not
+// yet swept
+const heapBytes = "/memory/classes/heap/objects:bytes"
+
+// heapWatcher - samples the heap's size every millisecond until stopped,
+// keeping the largest
+type heapWatcher struct {
+	stop chan struct{}
+	peak chan uint64
+}
+
+func watchHeap() *heapWatcher {
+	w := &heapWatcher{stop: make(chan struct{}), peak: make(chan uint64)}
+	sample := []metrics.Sample{{Name: heapBytes}}
+	read := func() uint64 {
+		metrics.Read(sample)
+		return sample[0].Value.Uint64()
+	}
+	go func() {
+		peak := read()
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				peak = max(peak, read())
+			case <-w.stop:
+				w.peak <- max(peak, read())
+				return
+			}
+		}
+	}()
+	return w
+}
+
+// Stop - stop sampling and return the largest sample
+func (w *heapWatcher) Stop() uint64 {
+	close(w.stop)
+	return <-w.peak
+}
+
+// runOnce - run +part+ of +s+ on +input+, turning a panic into an error
+func runOnce(s Solver, part string, input []byte) (answer string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return Run(s, part, bytes.NewReader(input))
+}
+
+// Measure - run +part+ of +s+ on +input+ +runs+ times, or fewer if they've
+// taken +budget+ between them already (but always once), and report how it
+// went. A run that fails stops it there.
+func Measure(s Solver, part string, input []byte, runs int, budget time.Duration) *Bench {
+	b := &Bench{Year: s.Year(), Day: s.Day(), Part: part}
+	var times []time.Duration
+	var total time.Duration
+	var allocs, allocBytes uint64
+	for i := 0; i < runs && (i == 0 || budget == 0 || total < budget); i++ {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		heap := watchHeap()
+		base := before.HeapAlloc
+
+		start := time.Now()
+		answer, err := runOnce(s, part, input)
+		elapsed := time.Since(start)
+
+		peak := heap.Stop()
+		runtime.ReadMemStats(&after)
+		// too short a run for a sample, so at least see what's left at the end
+		peak = max(peak, after.HeapAlloc)
+		if err != nil {
+			b.Err = err.Error()
+			break
+		}
+		b.Answer = answer
+		times = append(times, elapsed)
+		total += elapsed
+		allocs += after.Mallocs - before.Mallocs
+		allocBytes += after.TotalAlloc - before.TotalAlloc
+		if peak > base {
+			b.PeakBytes = max(b.PeakBytes, peak-base)
+		}
+	}
+	if b.Runs = len(times); b.Runs == 0 {
+		return b
+	}
+	slices.Sort(times)
+	b.Min, b.Median = times[0], times[len(times)/2]
+	b.Allocs, b.AllocBytes = allocs/uint64(b.Runs), allocBytes/uint64(b.Runs)
+	return b
+}
+
+const (
+	// MinComparedTime - medians this short are noise, so aren't compared
+	MinComparedTime = time.Millisecond
+	// MinComparedBytes - nor are allocations or peaks this small
+	MinComparedBytes = 1 << 20
+)
+
+// Regression - a Bench that's got worse than its baseline
+type Regression struct {
+	Bench    *Bench
+	Metric   string // "time", "allocated" or "peak heap"
+	Old, New float64
+}
+
+// String - what got worse, and by how much
+func (r *Regression) String() string {
+	was, is := FormatBytes(uint64(r.Old)), FormatBytes(uint64(r.New))
+	if r.Metric == "time" {
+		was, is = FormatDuration(time.Duration(r.Old)), FormatDuration(time.Duration(r.New))
+	}
+	return fmt.Sprintf("%s: %s %s -> %s (%+.0f%%)", r.Bench.Name(), r.Metric, was, is, 100*(r.New/r.Old-1))
+}
+
+// Compare - the metrics of +current+ more than +threshold+ (0.2 for 20%)
+// worse than in +baseline+. Parts that aren't in both are left out, as are
+// metrics the baseline has as 0, which are new rather than worse.
+func Compare(baseline, current *BenchReport, threshold float64) []*Regression {
+	var regressions []*Regression
+	for _, b := range current.Benches {
+		old := baseline.Find(b.Year, b.Day, b.Part)
+		if old == nil || old.Runs == 0 || b.Runs == 0 {
+			continue
+		}
+		check := func(metric string, was, is, floor float64) {
+			if was == 0 || max(was, is) < floor {
+				return
+			}
+			if is > was*(1+threshold) {
+				regressions = append(regressions, &Regression{Bench: b, Metric: metric, Old: was, New: is})
+			}
+		}
+		check("time", float64(old.Median), float64(b.Median), float64(MinComparedTime))
+		check("allocated", float64(old.AllocBytes), float64(b.AllocBytes), MinComparedBytes)
+		check("peak heap", float64(old.PeakBytes), float64(b.PeakBytes), MinComparedBytes)
+	}
+	return regressions
+}
+
+// FormatDuration - +d+ to three significant figures or so
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	}
+	return d.String()
+}
+
+// FormatBytes - +n+ in B, KiB, MiB or GiB
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit && exp < 2; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMG"[exp])
+}
@@ -0,0 +1,141 @@
+package aoc
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingSolver - a Solver whose part a counts its runs in +calls+ and
+// runs +run+ with the count, and whose part b just answers "b"
+func countingSolver(calls *int, run func(call int) (string, error)) Solver {
+	return New(2017, 25, func(r io.Reader) (string, error) {
+		*calls++
+		return run(*calls)
+	}, func(r io.Reader) (string, error) {
+		return "b", nil
+	})
+}
+
+func TestMeasure(t *testing.T) {
+	calls := 0
+	s := countingSolver(&calls, func(call int) (string, error) { return "42", nil })
+	b := Measure(s, "a", nil, 5, 0)
+	if calls != 5 || b.Runs != 5 || b.Answer != "42" || b.Err != "" {
+		t.Errorf("5 runs: called %d times, bench %+v", calls, b)
+	}
+	if b.Min > b.Median || b.Name() != "2017 day 25 part a" {
+		t.Errorf("5 runs: min %s, median %s, name %q", b.Min, b.Median, b.Name())
+	}
+	if b = Measure(s, "b", nil, 2, 0); b.Answer != "b" || b.Runs != 2 || calls != 5 {
+		t.Errorf("part b: called part a %d times, bench %+v", calls-5, b)
+	}
+}
+
+func TestMeasureBudget(t *testing.T) {
+	calls := 0
+	s := countingSolver(&calls, func(call int) (string, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "slow", nil
+	})
+	// three runs take at least 30ms, so it has to stop by then
+	b := Measure(s, "a", nil, 100, 25*time.Millisecond)
+	if b.Runs < 1 || b.Runs > 3 || calls != b.Runs {
+		t.Errorf("25ms of 10ms runs: %d runs, %d calls", b.Runs, calls)
+	}
+
+	// over budget before it starts, but it still runs once
+	calls = 0
+	if b = Measure(s, "a", nil, 100, time.Nanosecond); b.Runs != 1 || calls != 1 || b.Answer != "slow" {
+		t.Errorf("1ns budget: %d runs, %d calls", b.Runs, calls)
+	}
+}
+
+func TestMeasureFails(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(call int) (string, error)
+		err  string
+	}{
+		{"error", func(call int) (string, error) {
+			if call == 3 {
+				return "", errors.New("out of fuel")
+			}
+			return "7", nil
+		}, "out of fuel"},
+		{"panic", func(call int) (string, error) {
+			if call == 3 {
+				panic("index out of range")
+			}
+			return "7", nil
+		}, "panic: index out of range"},
+	}
+	for _, test := range tests {
+		calls := 0
+		b := Measure(countingSolver(&calls, test.run), "a", nil, 10, 0)
+		if calls != 3 {
+			t.Errorf("%s: called %d times, want it to stop at the third", test.name, calls)
+		}
+		// the runs before count, the failed one doesn't
+		if b.Runs != 2 || b.Err != test.err || b.Answer != "7" {
+			t.Errorf("%s: %d runs, error %q, answer %q; want 2 runs, error %q", test.name, b.Runs, b.Err, b.Answer, test.err)
+		}
+	}
+
+	calls := 0
+	first := countingSolver(&calls, func(call int) (string, error) { return "", errors.New("no input") })
+	if b := Measure(first, "a", nil, 10, 0); b.Runs != 0 || b.Err != "no input" || b.Median != 0 || calls != 1 {
+		t.Errorf("failing at once: %d calls, bench %+v", calls, b)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	const mib = 1 << 20
+	bench := func(day int, part string, median time.Duration, allocated, peak uint64) *Bench {
+		return &Bench{Year: 2018, Day: day, Part: part, Runs: 5, Median: median, AllocBytes: allocated, PeakBytes: peak}
+	}
+	baseline := &BenchReport{Benches: []*Bench{
+		bench(1, "a", 10*time.Millisecond, 2*mib, 2*mib),
+		bench(1, "b", 10*time.Millisecond, 2*mib, 2*mib),
+		bench(2, "a", 100*time.Microsecond, 100<<10, 100<<10),
+		bench(2, "b", 500*time.Microsecond, 2*mib, 2*mib),
+		bench(4, "a", 10*time.Millisecond, 2*mib, 2*mib),
+		bench(5, "a", 10*time.Millisecond, mib, 0),
+		{Year: 2018, Day: 6, Part: "a", Err: "panic: runtime error"},
+	}}
+	current := &BenchReport{Benches: []*Bench{
+		// within the threshold
+		bench(1, "a", 11*time.Millisecond, 2*mib, 2*mib+mib/10),
+		// slower
+		bench(1, "b", 13*time.Millisecond, 2*mib, 2*mib),
+		// worse, but too small to tell either way
+		bench(2, "a", 900*time.Microsecond, 900<<10, 900<<10),
+		// slower, and now over the floor
+		bench(2, "b", 2*time.Millisecond, 2*mib, 2*mib),
+		// not in the baseline
+		bench(3, "a", time.Second, 100*mib, 100*mib),
+		// a peak the baseline didn't measure is new, not worse
+		bench(5, "a", 10*time.Millisecond, 2*mib, 4*mib),
+		// failed in the baseline
+		bench(6, "a", time.Second, 100*mib, 100*mib),
+	}}
+
+	var got []string
+	for _, r := range Compare(baseline, current, 0.2) {
+		got = append(got, r.Bench.Name()+" "+r.Metric)
+		if s := r.String(); strings.Contains(s, "Inf") || strings.Contains(s, "NaN") {
+			t.Errorf("regression reads %q", s)
+		}
+	}
+	want := []string{"2018 day 1 part b time", "2018 day 2 part b time", "2018 day 5 part a allocated"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got regressions %q, want %q", got, want)
+	}
+
+	if r := Compare(baseline, current, 0.5); len(r) != 2 {
+		t.Errorf("with a 50%% threshold: got %d regressions, want 2 (day 2 part b and day 5)", len(r))
+	}
+}
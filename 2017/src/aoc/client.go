@@ -0,0 +1,258 @@
+package aoc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBaseURL - the Advent of Code site
+	DefaultBaseURL = "https://adventofcode.com"
+	// DefaultInterval - the least time to leave between requests, to go easy on
+	// the site
+	DefaultInterval = 5 * time.Second
+	// UserAgent - who's asking, as the site asks automated tools to say. The
+	// config's contact is added to it.
+	UserAgent = "aoc runner (Go net/http)"
+)
+
+var (
+	// ErrNotUnlocked - the puzzle isn't out yet (or doesn't exist)
+	ErrNotUnlocked = errors.New("puzzle not unlocked yet")
+	// ErrBadSession - the site didn't accept the session cookie
+	ErrBadSession = errors.New("session not accepted, log in again and update the session in the config")
+)
+
+// RateLimitError - the site wants us to slow down
+type RateLimitError struct {
+	RetryAfter time.Duration // 0 if the site didn't say
+}
+
+// Error - how long to wait, if we know
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited, try again in %s", e.RetryAfter)
+	}
+	return "rate limited, try again later"
+}
+
+// HTTPError - any other unexpected response
+type HTTPError struct {
+	Status int
+	Body   string // the start of it
+}
+
+// Error - the status and what the site said
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("unexpected response %d %s: %s", e.Status, http.StatusText(e.Status), e.Body)
+}
+
+// Client - talks to the Advent of Code site (or a stand in for it), no more
+// often than once every Interval
+type Client struct {
+	BaseURL   string
+	Session   string
+	UserAgent string
+	HTTP      *http.Client
+	Interval  time.Duration
+	// StateFile - if set, remembers when the last request was made, so the
+	// Interval holds across runs as well as within one
+	StateFile string
+	Trace     io.Writer // if set, every request (and every wait) is described here
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewClient - a Client for +c+, which remembers its last request in the
+// user's cache directory
+func NewClient(c *Config) *Client {
+	client := &Client{
+		BaseURL:   c.BaseURL,
+		Session:   c.Session,
+		UserAgent: UserAgent,
+		HTTP:      &http.Client{Timeout: 30 * time.Second},
+		Interval:  DefaultInterval,
+	}
+	if client.BaseURL == "" {
+		client.BaseURL = DefaultBaseURL
+	}
+	if c.Contact != "" {
+		client.UserAgent = fmt.Sprintf("%s %s", UserAgent, c.Contact)
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		client.StateFile = filepath.Join(dir, "aoc", "last-request")
+	}
+	return client
+}
+
+// throttle - wait until Interval has passed since the last request
+func (c *Client) throttle() {
+	c.mu.Lock()
+	last := c.last
+	c.mu.Unlock()
+	if c.StateFile != "" {
+		if b, err := os.ReadFile(c.StateFile); err == nil {
+			if t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(b))); err == nil && t.After(last) {
+				last = t
+			}
+		}
+	}
+	if wait := time.Until(last.Add(c.Interval)); wait > 0 {
+		if c.Trace != nil {
+			fmt.Fprintf(c.Trace, "waiting %s between requests\n", wait.Round(time.Millisecond))
+		}
+		time.Sleep(wait)
+	}
+}
+
+// finished - note that a request has just finished. The Interval counts from
+// here rather than from when it was sent, so a slow response doesn't bring
+// the next request closer to it. Failing to save that to the StateFile is
+// only reported to Trace.
+func (c *Client) finished() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.last = time.Now()
+	if c.StateFile == "" {
+		return
+	}
+	err := os.MkdirAll(filepath.Dir(c.StateFile), 0700)
+	if err == nil {
+		err = os.WriteFile(c.StateFile, []byte(c.last.Format(time.RFC3339Nano)+"\n"), 0600)
+	}
+	if err != nil && c.Trace != nil {
+		// the next run might not wait long enough, but this one still will
+		fmt.Fprintf(c.Trace, "couldn't note the request in %s: %s\n", c.StateFile, err)
+	}
+}
+
+// do - make +req+ with the session cookie, returning the body of a 200
+// response and an error for anything else
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	c.throttle()
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	if c.Trace != nil {
+		fmt.Fprintf(c.Trace, "%s %s\n", req.Method, req.URL)
+	}
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	defer c.finished()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if c.Trace != nil {
+		fmt.Fprintf(c.Trace, "  %s, %d bytes\n", resp.Status, len(body))
+	}
+
+	summary := strings.TrimSpace(string(body))
+	if i := strings.IndexByte(summary, '\n'); i >= 0 {
+		summary = summary[:i]
+	}
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return body, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrNotUnlocked, summary)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		e := &RateLimitError{}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			e.RetryAfter = time.Duration(secs) * time.Second
+		}
+		return nil, e
+	case strings.Contains(strings.ToLower(summary), "log in"):
+		return nil, ErrBadSession
+	}
+	return nil, &HTTPError{Status: resp.StatusCode, Body: summary}
+}
+
+// Input - the puzzle input for +day+ of +year+
+func (c *Client) Input(year, day int) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%d/day/%d/input", strings.TrimRight(c.BaseURL, "/"), year, day), nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return nil, fmt.Errorf("empty input for %d day %d", year, day)
+	}
+	return body, nil
+}
+
+// CachedInput - the input already saved for +day+ of +year+ under +root+, if
+// there is one, whole or in pieces
+func CachedInput(root string, year, day int) (string, bool) {
+	if path, err := InputPath(root, year, day, "a"); err == nil {
+		return path, true
+	}
+	if s, ok := Lookup(year, day); ok {
+		if paths, ok := piecePaths(root, s); ok {
+			return strings.Join(paths, " + "), true
+		}
+	}
+	return "", false
+}
+
+// FetchInput - save the input for +day+ of +year+ under +root+ (as
+// inputs/dayNN.txt) unless it's already there. Returns where it is and
+// whether it had to be fetched.
+func FetchInput(c *Client, root string, year, day int) (path string, fetched bool, err error) {
+	if path, ok := CachedInput(root, year, day); ok {
+		return path, false, nil
+	}
+	input, err := c.Input(year, day)
+	if err != nil {
+		return "", false, err
+	}
+	path = filepath.Join(InputDir(root, year), InputNames(day, "a")[0])
+	if err := writeFile(path, input); err != nil {
+		return "", false, err
+	}
+	return path, true, nil
+}
+
+// writeFile - write +data+ to +path+ all at once, so an interrupted write
+// doesn't leave half an input in the cache
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,142 @@
+package aoc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"aoc/fake"
+)
+
+// fakeClient - a Client for +server+ that doesn't wait between requests or
+// remember them
+func fakeClient(server *fake.Server) *Client {
+	return &Client{BaseURL: server.URL, Session: fake.Session, HTTP: server.Client()}
+}
+
+func TestFetchInputCached(t *testing.T) {
+	server := fake.New()
+	defer server.Close()
+	server.SetInput(2017, 1, "1122\n")
+	root := t.TempDir()
+	c := fakeClient(server)
+
+	path, fetched, err := FetchInput(c, root, 2017, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, "2017", "inputs", "day01.txt"); path != want || !fetched {
+		t.Errorf("first fetch: got %s (fetched=%t), want %s (fetched=true)", path, fetched, want)
+	}
+	if b, err := os.ReadFile(path); err != nil || string(b) != "1122\n" {
+		t.Errorf("saved input: got %q (%v), want %q", b, err, "1122\n")
+	}
+
+	again, fetched, err := FetchInput(c, root, 2017, 1)
+	if err != nil || again != path || fetched {
+		t.Errorf("second fetch: got %s (fetched=%t, %v), want %s from the cache", again, fetched, err, path)
+	}
+	if n := server.Requests(); n != 1 {
+		t.Errorf("server had %d requests, want 1", n)
+	}
+}
+
+func TestInputErrors(t *testing.T) {
+	server := fake.New()
+	defer server.Close()
+	server.SetInput(2017, 1, "1122\n")
+
+	c := fakeClient(server)
+	if _, err := c.Input(2017, 26); !errors.Is(err, ErrNotUnlocked) {
+		t.Errorf("day 26: got %v, want %v", err, ErrNotUnlocked)
+	}
+
+	c.Session = "wrong"
+	if _, err := c.Input(2017, 1); !errors.Is(err, ErrBadSession) {
+		t.Errorf("wrong session: got %v, want %v", err, ErrBadSession)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	server := fake.New()
+	defer server.Close()
+	server.SetInput(2017, 1, "1122\n")
+	server.MinInterval = 3 * time.Second
+
+	c := fakeClient(server)
+	if _, err := c.Input(2017, 1); err != nil {
+		t.Fatal(err)
+	}
+	_, err := c.Input(2017, 1)
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("got %v, want a RateLimitError", err)
+	}
+	if rateErr.RetryAfter != 3*time.Second {
+		t.Errorf("retry after %s, want 3s", rateErr.RetryAfter)
+	}
+}
+
+func TestInterval(t *testing.T) {
+	const interval = 50 * time.Millisecond
+	server := fake.New()
+	defer server.Close()
+	server.SetInput(2017, 1, "1122\n")
+	server.MinInterval = interval
+
+	var trace strings.Builder
+	c := fakeClient(server)
+	c.Interval = interval
+	c.StateFile = filepath.Join(t.TempDir(), "last-request")
+	c.Trace = &trace
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := c.Input(2017, 1); err != nil {
+			t.Fatalf("request %d: %s", i+1, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 2*interval {
+		t.Errorf("3 requests took %s, want at least %s", elapsed, 2*interval)
+	}
+	if waits := strings.Count(trace.String(), "waiting"); waits != 2 {
+		t.Errorf("waited %d times, want 2:\n%s", waits, trace.String())
+	}
+
+	// a new client, as in the next run, still waits for the last request
+	next := fakeClient(server)
+	next.Interval = interval
+	next.StateFile = c.StateFile
+	trace.Reset()
+	next.Trace = &trace
+	if _, err := next.Input(2017, 1); err != nil {
+		t.Errorf("request from a new client: %s", err)
+	}
+	if !strings.Contains(trace.String(), "waiting") {
+		t.Errorf("a new client didn't wait:\n%s", trace.String())
+	}
+}
+
+func TestStateFileUnwritable(t *testing.T) {
+	server := fake.New()
+	defer server.Close()
+	server.SetInput(2017, 1, "1122\n")
+
+	// a file where the state file's directory should be
+	blocker := filepath.Join(t.TempDir(), "aoc")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	var trace strings.Builder
+	c := fakeClient(server)
+	c.StateFile = filepath.Join(blocker, "last-request")
+	c.Trace = &trace
+	if _, err := c.Input(2017, 1); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(trace.String(), "couldn't note the request in "+c.StateFile) {
+		t.Errorf("trace doesn't report the state file:\n%s", trace.String())
+	}
+}
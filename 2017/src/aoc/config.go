@@ -0,0 +1,74 @@
+package aoc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config - settings for talking to the Advent of Code site, from a file of
+// "key = value" lines. Blank lines and lines starting with # are ignored.
+//
+//	# the value of the session cookie once you've logged in
+//	session = 53616c7465645f5f...
+//	# optional: how the site can reach you if the tool misbehaves
+//	contact = you@example.com
+//	# optional: somewhere other than https://adventofcode.com
+//	url = http://localhost:8080
+type Config struct {
+	Session string // "session"
+	Contact string // "contact"
+	BaseURL string // "url"
+}
+
+// DefaultConfigPath - where the config lives unless told otherwise:
+// aoc/config in the user's config directory (eg ~/.config/aoc/config)
+func DefaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aoc", "config"), nil
+}
+
+// LoadConfig - the Config in the file at +path+
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c := &Config{}
+	lineReader := bufio.NewScanner(f)
+	for n := 1; lineReader.Scan(); n++ {
+		line := strings.TrimSpace(lineReader.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value, not %q", path, n, line)
+		}
+		switch key, value = strings.TrimSpace(key), strings.TrimSpace(value); key {
+		case "session":
+			c.Session = value
+		case "contact":
+			c.Contact = value
+		case "url":
+			c.BaseURL = value
+		default:
+			return nil, fmt.Errorf("%s:%d: unexpected setting %q", path, n, key)
+		}
+	}
+	if err := lineReader.Err(); err != nil {
+		return nil, err
+	}
+	if c.Session == "" {
+		return nil, errors.New(path + ": no session set")
+	}
+	return c, nil
+}
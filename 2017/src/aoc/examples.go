@@ -0,0 +1,243 @@
+package aoc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Example - an input with known answers: day09-example0.txt and the
+// day09-example0.answer file next to it. An answer file named for the day
+// alone (day11.answer) is for the real input, which may be built in.
+type Example struct {
+	Year, Day int
+	Name      string            // eg day09-example0
+	Input     string            // the input file, or "" for the day's real input
+	Answers   map[string]string // part ("a" or "b") => answer
+}
+
+// Mismatch - a part of an Example that didn't give its answer
+type Mismatch struct {
+	Part      string
+	Want, Got string
+	Err       error // the part failed rather than giving the wrong answer
+}
+
+// String - what went wrong, with a Diff for a wrong answer
+func (m *Mismatch) String() string {
+	if m.Err != nil {
+		return fmt.Sprintf("part %s failed: %s", m.Part, m.Err)
+	}
+	return fmt.Sprintf("part %s:\n%s", m.Part, Diff(m.Want, m.Got))
+}
+
+var (
+	exampleName = regexp.MustCompile(`^day(\d\d)`)
+	answerLine  = regexp.MustCompile(`^([abAB]):(?:\s+(.*))?$`)
+)
+
+// ParseAnswers - the answers in an answer file, one part per line:
+//
+//	a: 32
+//	b: 22563
+//
+// A part with nothing after its colon has a multi-line answer, on the lines
+// up to the next part.
+func ParseAnswers(r io.Reader) (map[string]string, error) {
+	answers := make(map[string]string)
+	part := ""
+	var lines []string
+	flush := func() {
+		if part != "" {
+			answers[part] = strings.TrimRight(strings.Join(lines, "\n"), "\n ")
+		}
+	}
+	lineReader := bufio.NewScanner(r)
+	for lineReader.Scan() {
+		line := strings.TrimRight(lineReader.Text(), "\r")
+		m := answerLine.FindStringSubmatch(line)
+		if m == nil {
+			if part == "" {
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+				return nil, fmt.Errorf("expected \"a: answer\" or \"b: answer\", not %q", line)
+			}
+			lines = append(lines, line)
+			continue
+		}
+		flush()
+		part, lines = strings.ToLower(m[1]), nil
+		if _, dup := answers[part]; dup {
+			return nil, fmt.Errorf("part %s has two answers", part)
+		}
+		if m[2] != "" {
+			lines = []string{m[2]}
+		}
+	}
+	if err := lineReader.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return answers, nil
+}
+
+// LoadExamples - every Example for +year+ under +root+, by day then name
+func LoadExamples(root string, year int) ([]Example, error) {
+	dir := InputDir(root, year)
+	paths, err := filepath.Glob(filepath.Join(dir, "day*.answer"))
+	if err != nil {
+		return nil, err
+	}
+	examples := make([]Example, 0, len(paths))
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".answer")
+		m := exampleName.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		day, _ := strconv.Atoi(m[1])
+
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		answers, err := ParseAnswers(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		e := Example{Year: year, Day: day, Name: name, Answers: answers}
+		input := filepath.Join(dir, name+".txt")
+		if _, err := os.Stat(input); err == nil {
+			e.Input = input
+		} else if name != m[0] {
+			return nil, fmt.Errorf("%s: no input %s", path, input)
+		}
+		examples = append(examples, e)
+	}
+	sort.SliceStable(examples, func(i, j int) bool { return examples[i].Day < examples[j].Day })
+	return examples, nil
+}
+
+// Check - run +s+ on +e+ under +root+, returning the parts that didn't give
+// their answers. A part that panics fails rather than stopping the rest.
+func Check(root string, s Solver, e Example) []*Mismatch {
+	parts := make([]string, 0, len(e.Answers))
+	for part := range e.Answers {
+		parts = append(parts, part)
+	}
+	sort.Strings(parts)
+
+	var mismatches []*Mismatch
+	for _, part := range parts {
+		want := e.Answers[part]
+		got, err := checkPart(root, s, e, part)
+		if err != nil {
+			mismatches = append(mismatches, &Mismatch{Part: part, Want: want, Err: err})
+		} else if got = strings.TrimRight(got, "\n "); got != want {
+			mismatches = append(mismatches, &Mismatch{Part: part, Want: want, Got: got})
+		}
+	}
+	return mismatches
+}
+
+// Outcome - how the Solver for an Example's day did on it
+type Outcome struct {
+	Example
+	Solver     Solver      // nil if the day has no solution
+	Mismatches []*Mismatch // the parts that didn't give their answers
+}
+
+// Passed - did every part give its answer?
+func (o *Outcome) Passed() bool {
+	return o.Solver != nil && len(o.Mismatches) == 0
+}
+
+// Verify - Check every Example for +year+ under +root+, or only those for
+// +day+ if it isn't 0
+func Verify(root string, year, day int) ([]*Outcome, error) {
+	examples, err := LoadExamples(root, year)
+	if err != nil {
+		return nil, err
+	}
+	outcomes := make([]*Outcome, 0, len(examples))
+	for _, e := range examples {
+		if day != 0 && e.Day != day {
+			continue
+		}
+		o := &Outcome{Example: e}
+		if s, ok := Lookup(e.Year, e.Day); ok {
+			o.Solver = s
+			o.Mismatches = Check(root, s, e)
+		}
+		outcomes = append(outcomes, o)
+	}
+	return outcomes, nil
+}
+
+// checkPart - the answer +part+ of +s+ gives for +e+
+func checkPart(root string, s Solver, e Example, part string) (answer string, err error) {
+	var input io.ReadCloser
+	if e.Input != "" {
+		input, err = os.Open(e.Input)
+	} else {
+		input, _, err = OpenInput(root, s, part)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer input.Close()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return Run(s, part, input)
+}
+
+// Diff - the lines of +want+ and +got+, marked "-" if only +want+ has them,
+// "+" if only +got+ does and " " if both do
+func Diff(want, got string) string {
+	a, b := strings.Split(want, "\n"), strings.Split(got, "\n")
+	// lcs[i][j] - the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(&out, "  %s\n", a[i])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&out, "- %s\n", a[i])
+			i++
+		default:
+			fmt.Fprintf(&out, "+ %s\n", b[j])
+			j++
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,123 @@
+package aoc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAnswers(t *testing.T) {
+	tests := []struct {
+		name, text string
+		want       map[string]string
+	}{
+		{"one line each", "a: 32\nb: 22563\n", map[string]string{"a": "32", "b": "22563"}},
+		{"upper case and CRLF", "A: 1\r\nB: two words\r\n", map[string]string{"a": "1", "b": "two words"}},
+		{"blank lines first", "\n  \na: 1\n", map[string]string{"a": "1"}},
+		{"multi-line", "a:\n#..\n.#.\n\nb: 5\n", map[string]string{"a": "#..\n.#.", "b": "5"}},
+		{"multi-line last", "a: 5\nb:\n  x\n y\n\n\n", map[string]string{"a": "5", "b": "  x\n y"}},
+		{"empty", "a:\nb: 2\n", map[string]string{"a": "", "b": "2"}},
+		{"nothing", "", map[string]string{}},
+	}
+	for _, test := range tests {
+		got, err := ParseAnswers(strings.NewReader(test.text))
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParseAnswersErrors(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{"twice", "a: 1\nb: 2\na: 3\n", "part a has two answers"},
+		{"twice in different cases", "a: 1\nA: 1\n", "part a has two answers"},
+		{"junk first", "answers\na: 1\n", `expected "a: answer" or "b: answer", not "answers"`},
+		{"another part", "c: 1\n", `expected "a: answer" or "b: answer", not "c: 1"`},
+	}
+	for _, test := range tests {
+		_, err := ParseAnswers(strings.NewReader(test.text))
+		if err == nil || err.Error() != test.want {
+			t.Errorf("%s: got %v, want %q", test.name, err, test.want)
+		}
+	}
+}
+
+// writeInputs - files named by the keys of +files+ in +root+'s inputs for
+// 2017
+func writeInputs(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	dir := InputDir(root, 2017)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadExamples(t *testing.T) {
+	root := t.TempDir()
+	writeInputs(t, root, map[string]string{
+		"day09-example0.txt":    "{}",
+		"day09-example0.answer": "a: 1\n",
+		"day03.answer":          "a: 438\nb: 266330\n", // the real input
+		"day03.txt":             "265149",
+		"day11.answer":          "b: 1000\n", // built in, so no input file
+		"daily.answer":          "a: ignored\n",
+		"notes.txt":             "not an example",
+	})
+	examples, err := LoadExamples(root, 2017)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := InputDir(root, 2017)
+	want := []Example{
+		{Year: 2017, Day: 3, Name: "day03", Input: filepath.Join(dir, "day03.txt"), Answers: map[string]string{"a": "438", "b": "266330"}},
+		{Year: 2017, Day: 9, Name: "day09-example0", Input: filepath.Join(dir, "day09-example0.txt"), Answers: map[string]string{"a": "1"}},
+		{Year: 2017, Day: 11, Name: "day11", Answers: map[string]string{"b": "1000"}},
+	}
+	if !reflect.DeepEqual(examples, want) {
+		t.Errorf("got %+v, want %+v", examples, want)
+	}
+
+	// an example's answers with nothing to run them on
+	writeInputs(t, root, map[string]string{"day10-example.answer": "a: 12\n"})
+	_, err = LoadExamples(root, 2017)
+	if err == nil || !strings.Contains(err.Error(), "no input "+filepath.Join(dir, "day10-example.txt")) {
+		t.Errorf("got %v, want no input for day10-example", err)
+	}
+
+	writeInputs(t, root, map[string]string{"day10-example.answer": "a: 12\na: 13\n"})
+	_, err = LoadExamples(root, 2017)
+	if want := filepath.Join(dir, "day10-example.answer") + ": part a has two answers"; err == nil || err.Error() != want {
+		t.Errorf("got %v, want %q", err, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name, want, got, diff string
+	}{
+		{"same", "a\nb", "a\nb", "  a\n  b\n"},
+		{"inserted", "a\nc", "a\nb\nc", "  a\n+ b\n  c\n"},
+		{"deleted", "a\nb\nc", "a\nc", "  a\n- b\n  c\n"},
+		{"added at the end", "a", "a\nb\nc", "  a\n+ b\n+ c\n"},
+		{"deleted from the start", "a\nb\nc", "c", "- a\n- b\n  c\n"},
+		{"changed", "a\nb\nc", "a\nx\nc", "  a\n- b\n+ x\n  c\n"},
+		{"moved", "a\nb\nc", "b\nc\na", "- a\n  b\n  c\n+ a\n"},
+		{"nothing left", "1234", "", "- 1234\n+ \n"},
+	}
+	for _, test := range tests {
+		if got := Diff(test.want, test.got); got != test.diff {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.name, got, test.diff)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Package fake - a stand in for the Advent of Code site, on httptest, so the
+// aoc client can be tried out without a network or an account.
+//
+// It behaves like the real site as far as the client can tell: without the
+// right session cookie it asks you to log in, puzzles it hasn't got are "not
+// unlocked yet", and requests closer together than MinInterval get a 429.
+// Answers get the same pages the site sends: right, too high, too low, or
+// (within AnswerDelay of a wrong one) a wait.
+package fake
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Session - the session cookie a new Server accepts
+const Session = "fake-session"
+
+// Server - the stand in site. Its URL is the client's BaseURL.
+type Server struct {
+	*httptest.Server
+	Session     string        // the only session cookie accepted
+	MinInterval time.Duration // requests closer together than this are rate limited
+	AnswerDelay time.Duration // how long after a wrong answer the next one is refused
+
+	mu       sync.Mutex
+	inputs   map[[2]int]string // (year, day) => input
+	answers  map[[3]int]string // (year, day, level) => answer
+	solved   map[[3]int]bool
+	last     time.Time
+	noAnswer time.Time // answers before this are refused
+	requests int
+}
+
+// New - start a Server with no puzzles, accepting Session
+func New() *Server {
+	s := &Server{
+		Session:     Session,
+		AnswerDelay: time.Minute,
+		inputs:      make(map[[2]int]string),
+		answers:     make(map[[3]int]string),
+		solved:      make(map[[3]int]bool),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.route))
+	return s
+}
+
+// SetAnswer - unlock +day+ of +year+ (with an input, if it has none) and
+// make +answer+ the right answer to +part+ ("a" or "b")
+func (s *Server) SetAnswer(year, day int, part, answer string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.inputs[[2]int{year, day}]; !ok {
+		s.inputs[[2]int{year, day}] = fmt.Sprintf("fake input for %d day %d\n", year, day)
+	}
+	s.answers[[3]int{year, day, level(part)}] = answer
+}
+
+// level - the site's number for +part+, 0 if it isn't a part
+func level(part string) int {
+	switch strings.ToLower(part) {
+	case "a", "1":
+		return 1
+	case "b", "2":
+		return 2
+	}
+	return 0
+}
+
+// route - send /<year>/day/<day>/input to input and
+// /<year>/day/<day>/answer to answer. (Without a go.mod the build
+// gets the old ServeMux, which can't match wildcards, so this does it.)
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(path) != 4 || path[1] != "day" {
+		http.NotFound(w, r)
+		return
+	}
+	switch {
+	case path[3] == "input" && r.Method == http.MethodGet:
+		s.input(w, r, path[0], path[2])
+	case path[3] == "answer" && r.Method == http.MethodPost:
+		s.answer(w, r, path[0], path[2])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// SetInput - unlock +day+ of +year+, with +input+ as its input
+func (s *Server) SetInput(year, day int, input string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inputs[[2]int{year, day}] = input
+}
+
+// Requests - how many requests the Server has had
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// admit - count the request and check it isn't too soon after the last one
+// or missing the session. Writes the error response and returns false if it
+// is.
+func (s *Server) admit(w http.ResponseWriter, r *http.Request) bool {
+	s.mu.Lock()
+	now := time.Now()
+	tooSoon := s.requests > 0 && now.Sub(s.last) < s.MinInterval
+	s.requests++
+	s.last = now
+	s.mu.Unlock()
+
+	if tooSoon {
+		w.Header().Set("Retry-After", strconv.Itoa(int(s.MinInterval.Round(time.Second)/time.Second)))
+		http.Error(w, "Too many requests; please slow down.", http.StatusTooManyRequests)
+		return false
+	}
+	if c, err := r.Cookie("session"); err != nil || c.Value != s.Session {
+		http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// puzzle - the year and day in a request's path, and whether there's a
+// puzzle for them
+func (s *Server) puzzle(yearText, dayText string) (year, day int, ok bool) {
+	year, errYear := strconv.Atoi(yearText)
+	day, errDay := strconv.Atoi(dayText)
+	if errYear != nil || errDay != nil {
+		return 0, 0, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok = s.inputs[[2]int{year, day}]
+	return year, day, ok
+}
+
+func (s *Server) input(w http.ResponseWriter, r *http.Request, yearText, dayText string) {
+	if !s.admit(w, r) {
+		return
+	}
+	year, day, ok := s.puzzle(yearText, dayText)
+	if !ok {
+		http.Error(w, "Please don't repeatedly request this endpoint before it unlocks! The calendar countdown is synchronized with the server time; the link will be enabled on the calendar the instant this puzzle becomes available.", http.StatusNotFound)
+		return
+	}
+	s.mu.Lock()
+	input := s.inputs[[2]int{year, day}]
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprint(w, input)
+}
+
+// page - an HTML page with +text+ as its article, the way the site frames
+// its responses to answers
+func page(w http.ResponseWriter, day int, text string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html lang=\"en-us\">\n<body>\n<main>\n<article><p>%s <a href=\"/day/%d\">[Return to Day %d]</a></p></article>\n</main>\n</body>\n</html>\n",
+		html.EscapeString(text), day, day)
+}
+
+// wait - +d+ the way the site puts it: "1m 5s", "37s"
+func wait(d time.Duration) string {
+	secs := int(d.Round(time.Second) / time.Second)
+	if secs >= 60 {
+		return fmt.Sprintf("%dm %ds", secs/60, secs%60)
+	}
+	return fmt.Sprintf("%ds", secs)
+}
+
+// delay - "one minute", "5 seconds": how the site says how long to wait
+func delay(d time.Duration) string {
+	n, unit := int(d/time.Second), "second"
+	if d%time.Minute == 0 {
+		n, unit = int(d/time.Minute), "minute"
+	}
+	if n == 1 {
+		return "one " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+func (s *Server) answer(w http.ResponseWriter, r *http.Request, yearText, dayText string) {
+	if !s.admit(w, r) {
+		return
+	}
+	year, day, ok := s.puzzle(yearText, dayText)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	key := [3]int{year, day, level(r.PostFormValue("level"))}
+	given := strings.TrimSpace(r.PostFormValue("answer"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	right, ok := s.answers[key]
+	now := time.Now()
+	switch {
+	case now.Before(s.noAnswer):
+		page(w, day, fmt.Sprintf("You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have %s left to wait.", wait(s.noAnswer.Sub(now))))
+	case !ok || s.solved[key]:
+		page(w, day, "You don't seem to be solving the right level.  Did you already complete it?")
+	case given == right:
+		s.solved[key] = true
+		page(w, day, "That's the right answer!  You are one gold star closer to saving Christmas.")
+	default:
+		s.noAnswer = now.Add(s.AnswerDelay)
+		hint := ""
+		g, errG := strconv.ParseInt(given, 10, 64)
+		a, errA := strconv.ParseInt(right, 10, 64)
+		if errG == nil && errA == nil {
+			if g > a {
+				hint = "; your answer is too high"
+			} else {
+				hint = "; your answer is too low"
+			}
+		}
+		text := fmt.Sprintf("That's not the right answer%s.  If you're stuck, make sure you're using the full input data; there are also some general tips on the about page, or you can ask for hints on the subreddit.", hint)
+		if s.AnswerDelay > 0 {
+			text += fmt.Sprintf("  Please wait %s before trying again.", delay(s.AnswerDelay))
+		}
+		page(w, day, text)
+	}
+}
@@ -0,0 +1,129 @@
+package aoc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InputDir - where the inputs for +year+ live under the repository +root+
+func InputDir(root string, year int) string {
+	return filepath.Join(root, fmt.Sprint(year), "inputs")
+}
+
+// FindRoot - the repository root: +dir+ or the nearest directory above it
+// with an inputs directory for +year+
+func FindRoot(dir string, year int) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for start := dir; ; {
+		if info, err := os.Stat(InputDir(dir, year)); err == nil && info.IsDir() {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("couldn't find %d/inputs in %s or any directory above it", year, start)
+		}
+		dir = parent
+	}
+}
+
+// InputNames - the file names a real input for +part+ of +day+ may have, in
+// the order they're tried. Days have been saved as day02.txt, day01a.txt and
+// (for inputs that differ by part) day02b.txt.
+func InputNames(day int, part string) []string {
+	prefix := fmt.Sprintf("day%02d", day)
+	names := make([]string, 0, 3)
+	if part = strings.ToLower(part); part == "b" {
+		names = append(names, prefix+"b.txt")
+	}
+	return append(names, prefix+".txt", prefix+"a.txt")
+}
+
+// InputPath - the input file for +part+ of +day+ of +year+ under +root+
+func InputPath(root string, year, day int, part string) (string, error) {
+	dir := InputDir(root, year)
+	names := InputNames(day, part)
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no input for %d day %d in %s (tried %s): %w",
+		year, day, dir, strings.Join(names, ", "), os.ErrNotExist)
+}
+
+// piecePaths - where the pieces of the input of +s+ are under +root+, if it
+// has pieces and they're all there
+func piecePaths(root string, s Solver) ([]string, bool) {
+	p, ok := s.(Pieced)
+	if !ok || len(p.InputPieces()) == 0 {
+		return nil, false
+	}
+	paths := make([]string, 0, len(p.InputPieces()))
+	for _, name := range p.InputPieces() {
+		path := filepath.Join(InputDir(root, s.Year()), name)
+		if _, err := os.Stat(path); err != nil {
+			return nil, false
+		}
+		paths = append(paths, path)
+	}
+	return paths, true
+}
+
+// pieceReader - files read one after another, closed together
+type pieceReader struct {
+	io.Reader
+	files []*os.File
+}
+
+func (p *pieceReader) Close() error {
+	var err error
+	for _, f := range p.files {
+		if e := f.Close(); err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// openPieces - the files at +paths+ as one input
+func openPieces(paths []string) (io.ReadCloser, error) {
+	p := &pieceReader{}
+	readers := make([]io.Reader, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		p.files = append(p.files, f)
+		readers = append(readers, f)
+	}
+	p.Reader = io.MultiReader(readers...)
+	return p, nil
+}
+
+// OpenInput - the input for +part+ of +s+: the file InputPath finds under
+// +root+, or else its pieces if it's Pieced, or else the solver's built in
+// input. Also returns where it came from.
+func OpenInput(root string, s Solver, part string) (io.ReadCloser, string, error) {
+	path, err := InputPath(root, s.Year(), s.Day(), part)
+	if err == nil {
+		f, err := os.Open(path)
+		return f, path, err
+	}
+	if paths, ok := piecePaths(root, s); ok {
+		r, err := openPieces(paths)
+		return r, strings.Join(paths, " + "), err
+	}
+	if d, ok := s.(Defaulter); ok && d.DefaultInput() != "" {
+		return io.NopCloser(strings.NewReader(d.DefaultInput())), "built in input", nil
+	}
+	return nil, "", err
+}
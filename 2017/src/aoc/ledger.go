@@ -0,0 +1,111 @@
+package aoc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Attempt - an answer given to the site and what it made of it
+type Attempt struct {
+	Part    string    `json:"part"`
+	Answer  string    `json:"answer"`
+	Time    time.Time `json:"time"`
+	Verdict Verdict   `json:"verdict"`
+	// Until - no answer will be looked at before this, if the site said so
+	Until time.Time `json:"until,omitzero"`
+}
+
+// Ledger - every answer given for one day, so the same mistake isn't made
+// twice. It's kept as JSON in <root>/<year>/ledger/dayNN.json.
+type Ledger struct {
+	Year     int       `json:"year"`
+	Day      int       `json:"day"`
+	Attempts []Attempt `json:"attempts"`
+
+	path string
+}
+
+// LedgerPath - where the Ledger for +day+ of +year+ lives under +root+
+func LedgerPath(root string, year, day int) string {
+	return filepath.Join(root, fmt.Sprint(year), "ledger", fmt.Sprintf("day%02d.json", day))
+}
+
+// LoadLedger - the Ledger for +day+ of +year+ under +root+, empty if there
+// have been no answers yet
+func LoadLedger(root string, year, day int) (*Ledger, error) {
+	l := &Ledger{Year: year, Day: day, path: LedgerPath(root, year, day)}
+	b, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, l); err != nil {
+		return nil, fmt.Errorf("%s: %w", l.path, err)
+	}
+	if l.Year != year || l.Day != day {
+		return nil, fmt.Errorf("%s: ledger is for %d day %d", l.path, l.Year, l.Day)
+	}
+	return l, nil
+}
+
+// Save - write the Ledger back where it came from
+func (l *Ledger) Save() error {
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(l.path, append(b, '\n'))
+}
+
+// Record - note that +answer+ to +part+ got +r+ at +now+
+func (l *Ledger) Record(part, answer string, r *Result, now time.Time) {
+	a := Attempt{Part: strings.ToLower(part), Answer: answer, Time: now.UTC(), Verdict: r.Verdict}
+	if r.Wait > 0 {
+		a.Until = a.Time.Add(r.Wait)
+	}
+	l.Attempts = append(l.Attempts, a)
+}
+
+// Check - an error saying why +answer+ to +part+ shouldn't be given at
+// +now+, or nil if it's worth a try: the part is already solved, the answer
+// has already been given and was wrong, it's outside the bounds earlier
+// too high or too low answers set, or the site asked for a wait that isn't
+// over yet
+func (l *Ledger) Check(part, answer string, now time.Time) error {
+	part = strings.ToLower(part)
+	if answer == "" {
+		return errors.New("empty answer")
+	}
+	n, numeric := new(big.Int).SetString(answer, 10)
+	for _, a := range l.Attempts {
+		if !a.Until.IsZero() && now.Before(a.Until) {
+			return fmt.Errorf("the site asked for no answers until %s (another %s)",
+				a.Until.Local().Format(time.TimeOnly), a.Until.Sub(now).Round(time.Second))
+		}
+		if a.Part != part {
+			continue
+		}
+		switch {
+		case a.Verdict == Correct:
+			return fmt.Errorf("part %s was already solved with %s", part, a.Answer)
+		case a.Answer == answer && (a.Verdict == Wrong || a.Verdict == TooHigh || a.Verdict == TooLow):
+			return fmt.Errorf("%s was already given at %s and was %s", answer, a.Time.Local().Format(time.DateTime), a.Verdict)
+		}
+		if bound, ok := new(big.Int).SetString(a.Answer, 10); numeric && ok {
+			if a.Verdict == TooHigh && n.Cmp(bound) >= 0 {
+				return fmt.Errorf("%s is too high: %s already was", answer, a.Answer)
+			}
+			if a.Verdict == TooLow && n.Cmp(bound) <= 0 {
+				return fmt.Errorf("%s is too low: %s already was", answer, a.Answer)
+			}
+		}
+	}
+	return nil
+}
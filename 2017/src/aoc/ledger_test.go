@@ -0,0 +1,89 @@
+package aoc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLedgerCheck(t *testing.T) {
+	start := time.Date(2017, 12, 1, 5, 0, 0, 0, time.UTC)
+	l := &Ledger{Year: 2017, Day: 1}
+	l.Record("a", "100", &Result{Verdict: TooHigh}, start)
+	l.Record("a", "50", &Result{Verdict: TooLow}, start.Add(time.Minute))
+	l.Record("a", "abc", &Result{Verdict: Wrong}, start.Add(2*time.Minute))
+	l.Record("b", "99999999999999999999", &Result{Verdict: TooHigh, Wait: 5 * time.Minute}, start.Add(3*time.Minute))
+	later := start.Add(time.Hour)
+
+	tests := []struct {
+		name, part, answer string
+		now                time.Time
+		refused            bool
+	}{
+		{"between the bounds", "a", "75", later, false},
+		{"too high again", "a", "100", later, true},
+		{"above too high", "a", "101", later, true},
+		{"just under too high", "a", "99", later, false},
+		{"too low again", "a", "50", later, true},
+		{"below too low", "a", "49", later, true},
+		{"just over too low", "a", "51", later, false},
+		{"wrong again", "a", "abc", later, true},
+		{"another wrong guess", "A", "abd", later, false},
+		{"not a number", "a", "x100", later, false},
+		{"empty", "a", "", later, true},
+		{"past int64", "b", "100000000000000000000", later, true},
+		{"under a big bound", "b", "99999999999999999998", later, false},
+		{"during the wait", "a", "75", start.Add(4 * time.Minute), true},
+		{"after the wait", "a", "75", start.Add(8 * time.Minute), false},
+	}
+	for _, test := range tests {
+		err := l.Check(test.part, test.answer, test.now)
+		if test.refused && err == nil {
+			t.Errorf("%s: part %s %q wasn't refused", test.name, test.part, test.answer)
+		} else if !test.refused && err != nil {
+			t.Errorf("%s: part %s %q refused: %s", test.name, test.part, test.answer, err)
+		}
+	}
+
+	l.Record("a", "75", &Result{Verdict: Correct}, later)
+	if err := l.Check("a", "76", later); err == nil {
+		t.Errorf("part a was solved, but another answer wasn't refused")
+	}
+	if err := l.Check("b", "7", later); err != nil {
+		t.Errorf("part b refused after part a was solved: %s", err)
+	}
+}
+
+func TestLedgerSave(t *testing.T) {
+	root := t.TempDir()
+	l, err := LoadLedger(root, 2017, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Attempts) != 0 {
+		t.Fatalf("new ledger has %d attempts", len(l.Attempts))
+	}
+	now := time.Date(2017, 12, 1, 5, 0, 0, 0, time.UTC)
+	l.Record("A", "100", &Result{Verdict: TooHigh, Wait: time.Minute}, now)
+	if err := l.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := LoadLedger(root, 2017, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Attempt{Part: "a", Answer: "100", Time: now, Verdict: TooHigh, Until: now.Add(time.Minute)}
+	if len(again.Attempts) != 1 || again.Attempts[0] != want {
+		t.Errorf("loaded %+v, want [%+v]", again.Attempts, want)
+	}
+
+	// a ledger saved under the wrong name
+	if err := os.Rename(LedgerPath(root, 2017, 1), LedgerPath(root, 2017, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLedger(root, 2017, 2); err == nil {
+		t.Errorf("loaded %s as day 2", filepath.Base(LedgerPath(root, 2017, 2)))
+	}
+}
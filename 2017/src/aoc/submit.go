@@ -0,0 +1,117 @@
+package aoc
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Verdict - what the site made of an answer
+type Verdict string
+
+const (
+	Correct    Verdict = "correct"
+	TooHigh    Verdict = "too high"
+	TooLow     Verdict = "too low"
+	Wrong      Verdict = "wrong"       // and the site didn't say which way
+	Wait       Verdict = "wait"        // too soon after the last answer, so not checked
+	WrongLevel Verdict = "wrong level" // the part is already solved, or isn't open yet
+)
+
+// Result - the site's response to an answer
+type Result struct {
+	Verdict Verdict
+	Wait    time.Duration // how long before another answer will be looked at
+	Message string        // what the site said, as text
+}
+
+var (
+	article     = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tag         = regexp.MustCompile(`<[^>]*>`)
+	leftToWait  = regexp.MustCompile(`You have (?:(\d+)m\s*)?(?:(\d+)s\s*)?left to wait`)
+	waitBefore  = regexp.MustCompile(`[Pp]lease wait (one|\d+) (second|minute)s? before trying again`)
+	resultTexts = []struct {
+		text    string
+		verdict Verdict
+	}{
+		// "too high" and "too low" first: they're in a "not the right answer"
+		{"your answer is too high", TooHigh},
+		{"your answer is too low", TooLow},
+		{"That's not the right answer", Wrong},
+		{"That's the right answer", Correct},
+		{"You gave an answer too recently", Wait},
+		{"You don't seem to be solving the right level", WrongLevel},
+	}
+)
+
+// ParseResult - the Result in the page the site sends back for an answer
+func ParseResult(page []byte) (*Result, error) {
+	text := string(page)
+	if m := article.FindStringSubmatch(text); m != nil {
+		text = m[1]
+	}
+	text = strings.Join(strings.Fields(html.UnescapeString(tag.ReplaceAllString(text, ""))), " ")
+
+	r := &Result{Message: text}
+	for _, rt := range resultTexts {
+		if strings.Contains(text, rt.text) {
+			r.Verdict = rt.verdict
+			break
+		}
+	}
+	if r.Verdict == "" {
+		return nil, fmt.Errorf("unexpected response to an answer: %q", text)
+	}
+	if m := leftToWait.FindStringSubmatch(text); m != nil {
+		minutes, _ := strconv.Atoi(m[1])
+		seconds, _ := strconv.Atoi(m[2])
+		r.Wait = time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	} else if m := waitBefore.FindStringSubmatch(text); m != nil {
+		n := 1
+		if m[1] != "one" {
+			n, _ = strconv.Atoi(m[1])
+		}
+		unit := time.Second
+		if m[2] == "minute" {
+			unit = time.Minute
+		}
+		r.Wait = time.Duration(n) * unit
+	}
+	return r, nil
+}
+
+// Level - the site's name for +part+: "1" for a, "2" for b
+func Level(part string) (string, error) {
+	switch strings.ToLower(part) {
+	case "a":
+		return "1", nil
+	case "b":
+		return "2", nil
+	}
+	return "", fmt.Errorf("unexpected part %q, expected a or b", part)
+}
+
+// Submit - give +answer+ for +part+ of +day+ of +year+ and return what the
+// site made of it
+func (c *Client) Submit(year, day int, part, answer string) (*Result, error) {
+	level, err := Level(part)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{"level": {level}, "answer": {answer}}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%d/day/%d/answer", strings.TrimRight(c.BaseURL, "/"), year, day), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return ParseResult(body)
+}
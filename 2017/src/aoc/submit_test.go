@@ -0,0 +1,107 @@
+package aoc
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"aoc/fake"
+)
+
+// sitePage - +text+ framed the way the site sends its response to an answer
+func sitePage(text string) []byte {
+	return []byte(fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en-us">
+<head><title>Day 1 - Advent of Code 2017</title></head>
+<body>
+<header><h1 class="title-global"><a href="/">Advent of Code</a></h1></header>
+<main>
+<article><p>%s</p></article>
+</main>
+</body>
+</html>
+`, text))
+}
+
+func TestParseResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		verdict Verdict
+		wait    time.Duration
+	}{
+		{"correct", `That's the right answer!  You are <span class="day-success">one gold star</span> closer to saving Christmas. <a href="/2017/day/1#part2">[Continue to Part Two]</a>`, Correct, 0},
+		{"too high", `That's not the right answer; your answer is too high.  If you're stuck, make sure you're using the full input data.  Please wait one minute before trying again. <a href="/2017/day/1">[Return to Day 1]</a>`, TooHigh, time.Minute},
+		{"too low", `That's not the right answer; your answer is too low.  Please wait 5 minutes before trying again. <a href="/2017/day/1">[Return to Day 1]</a>`, TooLow, 5 * time.Minute},
+		{"wrong", `That's not the right answer.  If you're stuck, make sure you're using the full input data; there are also some general tips on the <a href="/2017/about">about page</a>.  Please wait 30 seconds before trying again.`, Wrong, 30 * time.Second},
+		{"wait", `You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 5m 3s left to wait. <a href="/2017/day/1">[Return to Day 1]</a>`, Wait, 5*time.Minute + 3*time.Second},
+		{"wait seconds", `You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 37s left to wait.`, Wait, 37 * time.Second},
+		{"wrong level", `You don't seem to be solving the right level.  Did you already complete it? <a href="/2017/day/1">[Return to Day 1]</a>`, WrongLevel, 0},
+	}
+	for _, test := range tests {
+		r, err := ParseResult(sitePage(test.text))
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if r.Verdict != test.verdict || r.Wait != test.wait {
+			t.Errorf("%s: got %s with a wait of %s, want %s with %s", test.name, r.Verdict, r.Wait, test.verdict, test.wait)
+		}
+	}
+
+	if r, err := ParseResult(sitePage("Something else entirely.")); err == nil {
+		t.Errorf("unexpected page: got %s, want an error", r.Verdict)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	server := fake.New()
+	defer server.Close()
+	server.AnswerDelay = 0
+	server.SetAnswer(2017, 1, "a", "100")
+	server.SetAnswer(2017, 1, "b", "200")
+	c := fakeClient(server)
+
+	submissions := []struct {
+		part, answer string
+		verdict      Verdict
+		wait         time.Duration
+	}{
+		{"a", "150", TooHigh, 0},
+		{"a", "50", TooLow, 0},
+		{"a", "abc", Wrong, 0},
+		{"a", "100", Correct, 0},
+		{"a", "100", WrongLevel, 0},
+		{"c", "100", "", 0},
+	}
+	for _, s := range submissions {
+		r, err := c.Submit(2017, 1, s.part, s.answer)
+		if s.verdict == "" {
+			if err == nil {
+				t.Errorf("part %s %s: got %s, want an error", s.part, s.answer, r.Verdict)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("part %s %s: %s", s.part, s.answer, err)
+		} else if r.Verdict != s.verdict || r.Wait != s.wait {
+			t.Errorf("part %s %s: got %s with a wait of %s, want %s with %s", s.part, s.answer, r.Verdict, r.Wait, s.verdict, s.wait)
+		}
+	}
+
+	server.AnswerDelay = 5*time.Minute + 3*time.Second
+	r, err := c.Submit(2017, 1, "b", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Verdict != TooLow || r.Wait != server.AnswerDelay {
+		t.Errorf("part b 1: got %s with a wait of %s, want %s with %s", r.Verdict, r.Wait, TooLow, server.AnswerDelay)
+	}
+	r, err = c.Submit(2017, 1, "b", "200")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Verdict != Wait || r.Wait != server.AnswerDelay {
+		t.Errorf("part b 200 straight after: got %s with a wait of %s, want %s with %s", r.Verdict, r.Wait, Wait, server.AnswerDelay)
+	}
+}
@@ -0,0 +1,20 @@
+// Package aoc2017 - every 2017 day. Import it for its side effects to
+// register them all with the aoc package.
+package aoc2017
+
+import (
+	_ "aoc2017/day01"
+	_ "aoc2017/day02"
+	_ "aoc2017/day03"
+	_ "aoc2017/day04"
+	_ "aoc2017/day05"
+	_ "aoc2017/day06"
+	_ "aoc2017/day07"
+	_ "aoc2017/day08"
+	_ "aoc2017/day09"
+	_ "aoc2017/day10"
+	_ "aoc2017/day11"
+	_ "aoc2017/day12"
+	_ "aoc2017/day13"
+	_ "aoc2017/day14"
+)
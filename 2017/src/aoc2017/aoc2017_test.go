@@ -0,0 +1,13 @@
+package aoc2017
+
+import (
+	"testing"
+
+	"aoc/aoctest"
+)
+
+// TestExamples - every answer file in 2017/inputs, the same as aoc verify
+// -year 2017
+func TestExamples(t *testing.T) {
+	aoctest.VerifyTest(t, 2017)
+}
@@ -0,0 +1,68 @@
+// Package day01 - 2017 day 1: sum the digits that match the digit a given
+// distance further round the (circular) list
+package day01
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2017, 1, PartA, PartB))
+}
+
+// Parse - the digits in +text+, ignoring surrounding whitespace
+func Parse(text string) ([]int, error) {
+	text = strings.TrimSpace(text)
+	digits := make([]int, 0, len(text))
+	for _, d := range strings.Split(text, "") {
+		digit, err := strconv.Atoi(d)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't convert %s to a digit: %w", d, err)
+		}
+		digits = append(digits, digit)
+	}
+	return digits, nil
+}
+
+// Sum - the sum of the digits that match the one +offset+ further on,
+// wrapping round the end
+func Sum(digits []int, offset int) int {
+	sum := 0
+	for i, d := range digits {
+		if d == digits[(i+offset)%len(digits)] {
+			sum += d
+		}
+	}
+	return sum
+}
+
+func solve(r io.Reader, half bool) (string, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	digits, err := Parse(string(text))
+	if err != nil {
+		return "", err
+	}
+	offset := 1
+	if half {
+		offset = len(digits) / 2
+	}
+	return strconv.Itoa(Sum(digits, offset)), nil
+}
+
+// PartA - compare each digit with the next one
+func PartA(r io.Reader) (string, error) {
+	return solve(r, false)
+}
+
+// PartB - compare each digit with the one halfway round
+func PartB(r io.Reader) (string, error) {
+	return solve(r, true)
+}
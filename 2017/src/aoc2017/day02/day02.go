@@ -0,0 +1,88 @@
+// Package day02 - 2017 day 2: checksum a spreadsheet, row by row
+package day02
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2017, 2, PartA, PartB))
+}
+
+// Parse - the rows of numbers in +r+, separated by tabs or spaces
+func Parse(r io.Reader) ([][]int, error) {
+	rows := make([][]int, 0)
+	lineReader := bufio.NewScanner(r)
+	for lineReader.Scan() {
+		var row []int
+		for _, d := range strings.Fields(lineReader.Text()) {
+			number, err := strconv.Atoi(d)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't convert >%s< to a number: %w", d, err)
+			}
+			row = append(row, number)
+		}
+		rows = append(rows, row)
+	}
+	return rows, lineReader.Err()
+}
+
+// Spread - the difference between the highest and lowest number in +row+
+func Spread(row []int) int {
+	if len(row) == 0 {
+		return 0
+	}
+	lowest, highest := row[0], row[0]
+	for _, number := range row {
+		lowest = min(lowest, number)
+		highest = max(highest, number)
+	}
+	return highest - lowest
+}
+
+// Quotients - the sum of every whole number you get dividing one number in
+// +row+ by another
+func Quotients(row []int) int {
+	sum := 0
+	for i := range row {
+		for j := range row {
+			if j != i && row[j] != 0 && row[i]%row[j] == 0 {
+				sum += row[i] / row[j]
+			}
+		}
+	}
+	return sum
+}
+
+// Checksum - the sum of +rowSum+ over every row
+func Checksum(rows [][]int, rowSum func([]int) int) int {
+	sum := 0
+	for _, row := range rows {
+		sum += rowSum(row)
+	}
+	return sum
+}
+
+func solve(r io.Reader, rowSum func([]int) int) (string, error) {
+	rows, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(Checksum(rows, rowSum)), nil
+}
+
+// PartA - sum the spread of each row
+func PartA(r io.Reader) (string, error) {
+	return solve(r, Spread)
+}
+
+// PartB - sum the one clean division in each row
+func PartB(r io.Reader) (string, error) {
+	return solve(r, Quotients)
+}
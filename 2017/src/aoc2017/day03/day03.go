@@ -0,0 +1,67 @@
+// Package day03 - 2017 day 3: spiral memory. The spiral itself is in the
+// spiral package; this is the puzzle's use of it.
+package day03
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"aoc"
+	"spiral"
+)
+
+func init() {
+	aoc.Register(aoc.WithInput(2017, 3, "368078", PartA, PartB))
+}
+
+// Parse - the square number in +r+
+func Parse(r io.Reader) (int, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(text)))
+	if err != nil {
+		return 0, fmt.Errorf("couldn't convert %q to a number: %w", text, err)
+	}
+	return n, nil
+}
+
+// Steps - how many steps data in square +n+ takes to reach square 1
+func Steps(n int) (int, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("squares start at 1, there is no square %d", n)
+	}
+	return spiral.Coord(n).Distance(), nil
+}
+
+// StressTest - the values written by the stress test, filling squares with
+// the sum of their neighbours until one is larger than +n+. Indexed by
+// square, so the last one is the answer.
+func StressTest(n int) []int {
+	return spiral.Fill(spiral.NeighbourSum, func(_ int, _ spiral.Point, value int) bool {
+		return value > n
+	})
+}
+
+// PartA - steps from the input square to square 1
+func PartA(r io.Reader) (string, error) {
+	n, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	steps, err := Steps(n)
+	return strconv.Itoa(steps), err
+}
+
+// PartB - the first value the stress test writes that's larger than the input
+func PartB(r io.Reader) (string, error) {
+	n, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	values := StressTest(n)
+	return strconv.Itoa(values[len(values)-1]), nil
+}
@@ -0,0 +1,81 @@
+// Package day04 - 2017 day 4: count the valid passphrases
+package day04
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2017, 4, PartA, PartB))
+}
+
+// IsAnagram - is +needle+ an anagram of +haystack+?
+func IsAnagram(needle, haystack string) bool {
+	// if they're a different length it is obviously not an angram
+	if len(needle) != len(haystack) {
+		return false
+	}
+
+	// count up the needle's letters and back down the haystack's; anagrams
+	// end up with nothing left over
+	freq := make(map[byte]int)
+	for i := 0; i < len(needle); i++ {
+		freq[needle[i]]++
+		freq[haystack[i]]--
+	}
+	for _, n := range freq {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Identical - are the two words the same?
+func Identical(a, b string) bool {
+	return a == b
+}
+
+// Valid - does no pair of +words+ clash according to +clash+?
+func Valid(words []string, clash func(a, b string) bool) bool {
+	for i := 0; i < len(words); i++ {
+		for j := i + 1; j < len(words); j++ {
+			if clash(words[i], words[j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CountValid - how many passphrases, one per line of +r+, are Valid
+func CountValid(r io.Reader, clash func(a, b string) bool) (int, error) {
+	lineReader := bufio.NewScanner(r)
+	validPassphrases := 0
+	for lineReader.Scan() {
+		if Valid(strings.Split(lineReader.Text(), " "), clash) {
+			validPassphrases++
+		}
+	}
+	return validPassphrases, lineReader.Err()
+}
+
+func solve(r io.Reader, clash func(a, b string) bool) (string, error) {
+	n, err := CountValid(r, clash)
+	return strconv.Itoa(n), err
+}
+
+// PartA - no word may appear twice
+func PartA(r io.Reader) (string, error) {
+	return solve(r, Identical)
+}
+
+// PartB - no word may be an anagram of another
+func PartB(r io.Reader) (string, error) {
+	return solve(r, IsAnagram)
+}
@@ -0,0 +1,80 @@
+// Package day05 - 2017 day 5: follow a maze of jump offsets until one leaves
+// the list
+package day05
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2017, 5, PartA, PartB))
+}
+
+// Parse - the offsets in +r+, one per line
+func Parse(r io.Reader) ([]int, error) {
+	offsets := make([]int, 0)
+	lineReader := bufio.NewScanner(r)
+	for lineReader.Scan() {
+		line := strings.TrimSpace(lineReader.Text())
+		if line == "" {
+			continue
+		}
+		offset, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't convert to int: %w", err)
+		}
+		offsets = append(offsets, offset)
+	}
+	return offsets, lineReader.Err()
+}
+
+// Increment - part A: every offset goes up by one after it's used
+func Increment(offset int) int {
+	return offset + 1
+}
+
+// Converge - part B: offsets of three or more go down by one after they're
+// used, the rest go up
+func Converge(offset int) int {
+	if offset >= 3 {
+		return offset - 1
+	}
+	return offset + 1
+}
+
+// Jumps - how many jumps it takes to leave +offsets+, starting at the first,
+// when +update+ changes each offset after it's used. +offsets+ isn't changed.
+func Jumps(offsets []int, update func(int) int) int {
+	maze := append([]int{}, offsets...)
+	jumps := 0
+	for position := 0; position >= 0 && position < len(maze); jumps++ {
+		step := maze[position]
+		maze[position] = update(step)
+		position += step
+	}
+	return jumps
+}
+
+func solve(r io.Reader, update func(int) int) (string, error) {
+	offsets, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(Jumps(offsets, update)), nil
+}
+
+// PartA - jumps to escape when offsets always increase
+func PartA(r io.Reader) (string, error) {
+	return solve(r, Increment)
+}
+
+// PartB - jumps to escape when large offsets decrease
+func PartB(r io.Reader) (string, error) {
+	return solve(r, Converge)
+}
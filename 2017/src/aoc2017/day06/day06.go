@@ -0,0 +1,122 @@
+// Package day06 - 2017 day 6: rebalance memory banks until a configuration
+// repeats. The loop is found with the cycle package: part A is mu+lambda and
+// part B is lambda.
+package day06
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"aoc"
+	"cycle"
+)
+
+func init() {
+	aoc.Register(aoc.New(2017, 6, PartA, PartB))
+}
+
+// Parse - the blocks in each bank, from a line of numbers separated by tabs
+// or spaces. Only the last line of +r+ counts.
+func Parse(r io.Reader) ([]int, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(text)), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	banks := make([]int, len(fields))
+	for i, d := range fields {
+		mag, err := strconv.Atoi(d)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't convert: %w", err)
+		}
+		banks[i] = mag
+	}
+	if len(banks) == 0 {
+		return nil, fmt.Errorf("no memory banks in the input")
+	}
+	return banks, nil
+}
+
+// Balance - redistribute the largest bank (the first, on a tie) one block
+// at a time, starting with the next bank and wrapping round. This will
+// modify +banks+.
+func Balance(banks []int) {
+	index := 0 // index of the highest element
+	for bank := range banks {
+		if banks[bank] > banks[index] {
+			index = bank
+		}
+	}
+
+	highest := banks[index]
+	banks[index] = 0
+	for rounds := 0; rounds < highest; rounds++ {
+		index = (index + 1) % len(banks)
+		banks[index]++
+	}
+}
+
+// Next - the banks after one balancing pass, leaving +banks+ alone
+func Next(banks []int) []int {
+	ret := append([]int{}, banks...)
+	Balance(ret)
+	return ret
+}
+
+// Equal - do the two configurations match?
+func Equal(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// String - the banks as one string of digits, eg 01312109875321111065
+func String(banks []int) string {
+	var ret strings.Builder
+	for _, b := range banks {
+		ret.WriteString(strconv.Itoa(b))
+	}
+	return ret.String()
+}
+
+// Loop - mu and lambda for balancing +banks+, found by +algorithm+ (floyd or
+// brent)
+func Loop(banks []int, algorithm string) (mu, lambda int, err error) {
+	switch algorithm {
+	case "floyd":
+		mu, lambda = cycle.Floyd(banks, Next, Equal)
+	case "brent":
+		mu, lambda = cycle.Brent(banks, Next, Equal)
+	default:
+		err = fmt.Errorf("unknown cycle detection algorithm %s", algorithm)
+	}
+	return mu, lambda, err
+}
+
+func solve(r io.Reader, answer func(mu, lambda int) int) (string, error) {
+	banks, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	mu, lambda, err := Loop(banks, "brent")
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(answer(mu, lambda)), nil
+}
+
+// PartA - balancing passes until a configuration repeats
+func PartA(r io.Reader) (string, error) {
+	return solve(r, func(mu, lambda int) int { return mu + lambda })
+}
+
+// PartB - passes in the loop
+func PartB(r io.Reader) (string, error) {
+	return solve(r, func(mu, lambda int) int { return lambda })
+}
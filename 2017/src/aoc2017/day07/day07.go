@@ -0,0 +1,339 @@
+// Package day07 - 2017 day 7: find the base of a tower of programs and the
+// one program whose weight unbalances it
+package day07
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2017, 7, PartA, PartB))
+}
+
+// Node - a program in the tower
+type Node struct {
+	Name       string
+	Children   []*Node
+	Weight     int
+	TreeWeight int
+	Parent     *Node
+}
+
+func NewNode(name string, weight int) *Node {
+	return &Node{
+		Name:       name,
+		Weight:     weight,
+		TreeWeight: weight,
+	}
+}
+
+/* Return both the parent and child because both need updating at the caller */
+func (n *Node) AddChild(c *Node) (*Node, *Node) {
+	n.Children = append(n.Children, c)
+	c.Parent = n
+	return n, c
+}
+
+// Compute the weights of all trees under n
+func (n *Node) ComputeTreeWeight() (*Node, int) {
+	var sum, subtreeSum int
+	sum = n.Weight // Start with the sum as the current node's weight
+	for _, node := range n.Children {
+		node, subtreeSum = node.ComputeTreeWeight()
+		sum += subtreeSum
+	}
+	n.TreeWeight = sum
+	return n, n.TreeWeight
+}
+
+// Return a node's sibling
+func (n *Node) GetSibling() *Node {
+	var ret *Node
+	for _, childNode := range n.Parent.Children {
+		if childNode != n {
+			ret = childNode
+		}
+	}
+	return ret
+}
+
+// Return true if the node is balanced
+// Return false if it is not along with the offending node causing unbalance and its offset relative to the correct weights
+func (n *Node) IsTreeBalanced() (bool, *Node, int) {
+	sum := 0
+	for _, childNode := range n.Children {
+		sum += childNode.TreeWeight
+	}
+	// Histogram will be weight => [Nodes with this weight]
+	weightHistogram := make(map[int][]*Node)
+	for _, child := range n.Children {
+		weightHistogram[child.TreeWeight] = append(weightHistogram[child.TreeWeight], child)
+	}
+	for _, weight := range weightHistogram {
+		if len(weight) == 1 {
+			return false, weight[0], weight[0].TreeWeight - weight[0].GetSibling().TreeWeight
+		}
+	}
+	return true, nil, 0
+}
+
+// SortedChildren - children by name, so reports come out the same every time
+func (n *Node) SortedChildren() []*Node {
+	ret := append([]*Node{}, n.Children...)
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// Imbalance - a node whose children's towers don't all weigh the same
+type Imbalance struct {
+	Node     *Node
+	Odd      *Node // the child that differs from the rest, nil if it can't be told (only two children)
+	Expected int   // tower weight the other children share
+}
+
+func (i Imbalance) String() string {
+	weights := make([]string, 0, len(i.Node.Children))
+	for _, child := range i.Node.SortedChildren() {
+		weights = append(weights, fmt.Sprintf("%s=%d", child.Name, child.TreeWeight))
+	}
+	ret := fmt.Sprintf("%s holds %s", i.Node.Name, strings.Join(weights, ", "))
+	if i.Odd != nil {
+		ret += fmt.Sprintf(": %s is off by %+d", i.Odd.Name, i.Odd.TreeWeight-i.Expected)
+	} else {
+		ret += ": can't tell which is wrong"
+	}
+	return ret
+}
+
+// imbalance - the imbalance at +n+ itself, if there is one
+func (n *Node) imbalance() (Imbalance, bool) {
+	counts := make(map[int]int)
+	for _, child := range n.Children {
+		counts[child.TreeWeight]++
+	}
+	if len(counts) < 2 {
+		return Imbalance{}, false
+	}
+	ret := Imbalance{Node: n}
+	// with three or more children the odd one is the weight only one has
+	if len(n.Children) > 2 && len(counts) == 2 {
+		for _, child := range n.Children {
+			if counts[child.TreeWeight] == 1 {
+				ret.Odd = child
+			} else {
+				ret.Expected = child.TreeWeight
+			}
+		}
+	}
+	return ret, true
+}
+
+// Imbalances - every imbalance in the tower under +n+, parents before
+// children
+func (n *Node) Imbalances() []Imbalance {
+	ret := make([]Imbalance, 0)
+	if i, ok := n.imbalance(); ok {
+		ret = append(ret, i)
+	}
+	for _, child := range n.SortedChildren() {
+		ret = append(ret, child.Imbalances()...)
+	}
+	return ret
+}
+
+// Fix - the one weight change that balances the whole tower
+type Fix struct {
+	Node     *Node
+	From, To int
+	Proof    []Imbalance // from the root down to the node's parent
+}
+
+// Path - names from the root to the node being fixed
+func (f *Fix) Path() []string {
+	ret := make([]string, 0)
+	for n := f.Node; n != nil; n = n.Parent {
+		ret = append([]string{n.Name}, ret...)
+	}
+	return ret
+}
+
+// Diagnose - find the single node whose weight unbalances the tower under
+// +n+. Starting at +n+, follow the odd child down while its own children are
+// unbalanced; the first odd child whose children all agree is the culprit,
+// and the weights at each step on the way down are the proof. The tree
+// weights must be computed first.
+func (n *Node) Diagnose() (*Fix, error) {
+	current, ok := n.imbalance()
+	if !ok {
+		return nil, errors.New("the tower is already balanced")
+	}
+	fix := &Fix{}
+	for {
+		fix.Proof = append(fix.Proof, current)
+		if current.Odd == nil {
+			return nil, fmt.Errorf("no single weight change can be proven: %s", current)
+		}
+		next, unbalanced := current.Odd.imbalance()
+		if !unbalanced {
+			break
+		}
+		current = next
+	}
+	fix.Node = current.Odd
+	fix.From = fix.Node.Weight
+	fix.To = fix.Node.Weight + current.Expected - fix.Node.TreeWeight
+	if fix.To < 0 {
+		return nil, fmt.Errorf("%s would need a negative weight (%d)", fix.Node.Name, fix.To)
+	}
+	// the other imbalances must all be on the way down, or one change can't
+	// fix them all
+	for _, i := range n.Imbalances() {
+		onPath := false
+		for _, p := range fix.Proof {
+			onPath = onPath || p.Node == i.Node
+		}
+		if !onPath {
+			return nil, fmt.Errorf("more than one node is wrong: %s", i)
+		}
+	}
+	return fix, nil
+}
+
+// Report - every node's weight and tower weight, indented by depth, with
+// imbalances marked
+func (n *Node) Report(out io.Writer, depth int) {
+	fmt.Fprintf(out, "%s%s (%d) tower %d", strings.Repeat("  ", depth), n.Name, n.Weight, n.TreeWeight)
+	if i, ok := n.imbalance(); ok {
+		fmt.Fprintf(out, " <- %s", i)
+	}
+	fmt.Fprintln(out)
+	for _, child := range n.SortedChildren() {
+		child.Report(out, depth+1)
+	}
+}
+
+// DOT - the tower as a Graphviz digraph, base at the top. Unbalanced nodes
+// are red and the node +fix+ changes (if any) is filled.
+func (n *Node) DOT(fix *Fix) string {
+	var out strings.Builder
+	out.WriteString("digraph tower {\n\tnode [shape=box];\n")
+	var visit func(node *Node)
+	visit = func(node *Node) {
+		attrs := ""
+		if _, ok := node.imbalance(); ok {
+			attrs += ", color=red"
+		}
+		if fix != nil && node == fix.Node {
+			attrs += fmt.Sprintf(", style=filled, fillcolor=gold, xlabel=\"should be %d\"", fix.To)
+		}
+		fmt.Fprintf(&out, "\t%q [label=\"%s\\nweight %d\\ntower %d\"%s];\n", node.Name, node.Name, node.Weight, node.TreeWeight, attrs)
+		for _, child := range node.SortedChildren() {
+			fmt.Fprintf(&out, "\t%q -> %q;\n", node.Name, child.Name)
+			visit(child)
+		}
+	}
+	visit(n)
+	out.WriteString("}\n")
+	return out.String()
+}
+
+// Parse - the tower described by +r+, one program per line, with its tree
+// weights computed. Returns the base.
+func Parse(r io.Reader) (*Node, error) {
+	// Create a map of name -> Node for quicker access, especially when building
+	tower := make(map[string]*Node)
+
+	// Map of parents => children. Keys are names of nodes whose children are the values
+	children := make(map[string][]string)
+
+	lineReader := bufio.NewScanner(r)
+	for lineReader.Scan() {
+		// loop over tokens separated by spaces
+		line := lineReader.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var nodeName string
+		var nodeWeight int
+
+		for n, token := range strings.Split(line, " ") {
+			switch {
+			case n == 0:
+				// name
+				nodeName = token
+			case n == 1:
+				// weight
+				if _, err := fmt.Sscanf(token, "(%d)", &nodeWeight); err != nil {
+					return nil, fmt.Errorf("couldn't read the weight in %q: %w", line, err)
+				}
+			case n == 2:
+				// ->
+				continue
+			case n > 2:
+				// list of children whose name may end in ,
+				childName := strings.TrimSuffix(token, ",")
+				children[nodeName] = append(children[nodeName], childName)
+			}
+		}
+		tower[nodeName] = NewNode(nodeName, nodeWeight)
+	}
+	if err := lineReader.Err(); err != nil {
+		return nil, err
+	}
+	// Go through and turn references to names into pointers
+	for parentName, childNames := range children {
+		// name => list of name's children
+		for _, child := range childNames {
+			if tower[child] == nil {
+				return nil, fmt.Errorf("%s holds %s, which isn't in the tower", parentName, child)
+			}
+			tower[parentName], tower[child] = tower[parentName].AddChild(tower[child])
+		}
+	}
+	// at this point the tower has parentage, so, the only Node without a parent is the base.
+	var rootNode *Node
+	for _, node := range tower {
+		if node.Parent == nil {
+			if rootNode != nil {
+				return nil, fmt.Errorf("both %s and %s are standing on nothing", rootNode.Name, node.Name)
+			}
+			rootNode = node
+		}
+	}
+	if rootNode == nil {
+		return nil, errors.New("no program is at the base of the tower")
+	}
+
+	rootNode, _ = rootNode.ComputeTreeWeight()
+	return rootNode, nil
+}
+
+// PartA - the name of the base
+func PartA(r io.Reader) (string, error) {
+	root, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return root.Name, nil
+}
+
+// PartB - the weight the one wrong program should have
+func PartB(r io.Reader) (string, error) {
+	root, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	fix, err := root.Diagnose()
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(fix.To), nil
+}
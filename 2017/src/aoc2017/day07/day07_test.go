@@ -0,0 +1,91 @@
+package day07
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// example - the puzzle's example tower
+func example(t *testing.T) *Node {
+	t.Helper()
+	input, err := os.Open("../../../inputs/day07-example.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer input.Close()
+	root, err := Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestDiagnose(t *testing.T) {
+	root := example(t)
+	if root.Name != "tknk" || root.TreeWeight != 778 {
+		t.Fatalf("base %s with tower %d, want tknk with 778", root.Name, root.TreeWeight)
+	}
+	fix, err := root.Diagnose()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fix.Node.Name != "ugml" || fix.From != 68 || fix.To != 60 {
+		t.Errorf("fix %s from %d to %d, want ugml from 68 to 60", fix.Node.Name, fix.From, fix.To)
+	}
+	if got, want := fix.Path(), []string{"tknk", "ugml"}; !slices.Equal(got, want) {
+		t.Errorf("path %v, want %v", got, want)
+	}
+	if len(fix.Proof) != 1 {
+		t.Fatalf("%d steps of proof, want 1", len(fix.Proof))
+	}
+	if got, want := fix.Proof[0].String(), "tknk holds fwft=243, padx=243, ugml=251: ugml is off by +8"; got != want {
+		t.Errorf("proof %q, want %q", got, want)
+	}
+
+	// with the fix made there's nothing left to diagnose
+	fix.Node.Weight = fix.To
+	root.ComputeTreeWeight()
+	if _, err := root.Diagnose(); err == nil {
+		t.Errorf("fixed tower still has something wrong with it")
+	}
+}
+
+func TestDiagnoseUnprovable(t *testing.T) {
+	// two children that disagree, and nothing to say which is wrong
+	root, err := Parse(strings.NewReader("a (1) -> b, c\nb (2)\nc (3)\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = root.Diagnose()
+	if err == nil || !strings.Contains(err.Error(), "can't tell which is wrong") {
+		t.Errorf("got %v, want an error saying it can't tell", err)
+	}
+}
+
+func TestDOT(t *testing.T) {
+	root := example(t)
+	fix, err := root.Diagnose()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dot := root.DOT(fix)
+	for _, line := range []string{
+		"\t\"tknk\" [label=\"tknk\\nweight 41\\ntower 778\", color=red];\n",
+		"\t\"ugml\" [label=\"ugml\\nweight 68\\ntower 251\", style=filled, fillcolor=gold, xlabel=\"should be 60\"];\n",
+		"\t\"padx\" [label=\"padx\\nweight 45\\ntower 243\"];\n",
+		"\t\"pbga\" [label=\"pbga\\nweight 66\\ntower 66\"];\n",
+		"\t\"tknk\" -> \"ugml\";\n",
+	} {
+		if !strings.Contains(dot, line) {
+			t.Errorf("no %q in:\n%s", line, dot)
+		}
+	}
+	if !strings.HasPrefix(dot, "digraph tower {\n") || !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("not a digraph:\n%s", dot)
+	}
+	if strings.Contains(root.DOT(nil), "gold") {
+		t.Errorf("without a fix, a node is still filled in")
+	}
+}
@@ -0,0 +1,66 @@
+// Package day08 - 2017 day 8: run the register language (see the reglang
+// package) and report the largest value
+package day08
+
+import (
+	"io"
+	"strconv"
+
+	"aoc"
+	"reglang"
+)
+
+func init() {
+	aoc.Register(aoc.New(2017, 8, PartA, PartB))
+}
+
+// Load - the program in +r+
+func Load(r io.Reader) (*reglang.Program, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return reglang.Load(string(text), reglang.NewRegistry())
+}
+
+// Highest - the register holding the largest value (above 0) at the end, by
+// name on a tie. An empty name means none is above 0.
+func Highest(m *reglang.Machine) (string, int) {
+	highest := 0
+	highestRegister := ""
+	for _, register := range m.Names() {
+		if value := m.Value(register); value > highest {
+			highest = value
+			highestRegister = register
+		}
+	}
+	return highestRegister, highest
+}
+
+// PartA - the largest value in any register at the end
+func PartA(r io.Reader) (string, error) {
+	program, err := Load(r)
+	if err != nil {
+		return "", err
+	}
+	machine := reglang.NewMachine(program)
+	if err := machine.Run(0); err != nil {
+		return "", err
+	}
+	_, highest := Highest(machine)
+	return strconv.Itoa(highest), nil
+}
+
+// PartB - the largest value any register held while running
+func PartB(r io.Reader) (string, error) {
+	program, err := Load(r)
+	if err != nil {
+		return "", err
+	}
+	machine := reglang.NewMachine(program)
+	history := machine.Record()
+	if err := machine.Run(0); err != nil {
+		return "", err
+	}
+	return strconv.Itoa(history.HighestEver()), nil
+}
@@ -0,0 +1,87 @@
+// Package day09 - 2017 day 9: score the groups in a stream and count its
+// garbage
+package day09
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2017, 9, PartA, PartB))
+}
+
+// Process - the total score of the groups in +stream+ and the number of
+// cleaned up garbage characters. If +trace+ isn't nil the state before each
+// character is written to it.
+func Process(stream string, trace io.Writer) (score, garbageCharacters int) {
+	depth := 0 // Group depth; used with scoring
+	ignoreNext := false
+	ignoreGroups := false
+	for i, token := range stream {
+		if trace != nil {
+			fmt.Fprintf(trace, "[%d/%d] score: %d, depth: %d, ignoreNext: %t, ignoreGroups: %t, token %c\n", i, len(stream)-1, score, depth, ignoreNext, ignoreGroups, token)
+		}
+		if ignoreNext {
+			// Clear and skip this character
+			ignoreNext = false
+			continue
+		}
+		switch token {
+		case '{':
+			if !ignoreGroups {
+				depth++
+			} else {
+				garbageCharacters++
+			}
+		case '<':
+			if ignoreGroups {
+				// Already ignoring, so we need to bump garbageCharacters
+				garbageCharacters++
+			}
+			ignoreGroups = true // start of garbage
+		case '>':
+			ignoreGroups = false // end of garbage
+		case '}':
+			if !ignoreGroups {
+				score += depth
+				depth--
+			} else {
+				garbageCharacters++
+			}
+		case '!':
+			ignoreNext = true
+		default:
+			if ignoreGroups {
+				garbageCharacters++
+			}
+		}
+	}
+	return score, garbageCharacters
+}
+
+func solve(r io.Reader, garbage bool) (string, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	score, garbageCharacters := Process(strings.TrimSpace(string(text)), nil)
+	if garbage {
+		return strconv.Itoa(garbageCharacters), nil
+	}
+	return strconv.Itoa(score), nil
+}
+
+// PartA - the total score of all the groups
+func PartA(r io.Reader) (string, error) {
+	return solve(r, false)
+}
+
+// PartB - how many characters of garbage there are
+func PartB(r io.Reader) (string, error) {
+	return solve(r, true)
+}
@@ -0,0 +1,71 @@
+// Package day10 - 2017 day 10: one round of the knot hash, then the whole
+// thing. The hashing is in the knothash package.
+package day10
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"aoc"
+	"knothash"
+)
+
+func init() {
+	aoc.Register(aoc.New(2017, 10, PartA, PartB))
+}
+
+// Lengths - +input+ as a comma-separated list of numbers. (Part B treats
+// each character as its ASCII code; knothash.Sum does that.)
+func Lengths(input string) ([]int, error) {
+	ret := make([]int, 0)
+	for _, numberString := range strings.Split(input, ",") {
+		number, err := strconv.Atoi(strings.TrimSpace(numberString))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't convert %s to a number: %w", numberString, err)
+		}
+		ret = append(ret, number)
+	}
+	return ret, nil
+}
+
+// Product - the first two numbers multiplied after one round over a list of
+// +size+ numbers
+func Product(lengths []int, size int) (int, error) {
+	list, err := knothash.Sparse(lengths, size, 1)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't hash: %w", err)
+	}
+	return list[0] * list[1], nil
+}
+
+func read(r io.Reader) (string, error) {
+	text, err := io.ReadAll(r)
+	return strings.TrimSpace(string(text)), err
+}
+
+// PartA - the product after one round over 256 numbers
+func PartA(r io.Reader) (string, error) {
+	input, err := read(r)
+	if err != nil {
+		return "", err
+	}
+	lengths, err := Lengths(input)
+	if err != nil {
+		return "", err
+	}
+	product, err := Product(lengths, knothash.ListSize)
+	return strconv.Itoa(product), err
+}
+
+// PartB - the knot hash of the input, in hex
+func PartB(r io.Reader) (string, error) {
+	input, err := read(r)
+	if err != nil {
+		return "", err
+	}
+	sum := knothash.Sum([]byte(input))
+	return hex.EncodeToString(sum[:]), nil
+}
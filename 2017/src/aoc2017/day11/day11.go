@@ -0,0 +1,64 @@
+// Package day11 - 2017 day 11: follow a child's path through a flat topped
+// hex grid (see the hexgrid package) and count the steps back home
+package day11
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"aoc"
+	"hexgrid"
+)
+
+func init() {
+	aoc.Register(aoc.New(2017, 11, PartA, PartB))
+}
+
+// Walk - follow +moves+ (comma separated, eg ne,ne,s) from the origin.
+// Returns every hex visited with the step it was last visited at, where the
+// walk ends and the furthest it got from home. If +trace+ isn't nil each
+// move is written to it.
+func Walk(moves string, trace io.Writer) (path hexgrid.Grid[int], end hexgrid.Hex, furthest int, err error) {
+	end = hexgrid.Origin
+	path = hexgrid.NewGrid[int]() // hex -> step it was last visited
+	path[end] = 0
+	if trace != nil {
+		fmt.Fprintf(trace, "Initial hex %s\n", end)
+	}
+	for n, direction := range strings.Split(strings.TrimSpace(moves), ",") {
+		end, err = hexgrid.Flat.Move(end, direction)
+		if err != nil {
+			return nil, end, furthest, err
+		}
+		path[end] = n + 1
+		furthest = max(furthest, end.Len())
+		if trace != nil {
+			fmt.Fprintf(trace, "currentHex: %s (moved %s)\n", end, direction)
+		}
+	}
+	return path, end, furthest, nil
+}
+
+func solve(r io.Reader, answer func(end hexgrid.Hex, furthest int) int) (string, error) {
+	moves, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	_, end, furthest, err := Walk(string(moves), nil)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(answer(end, furthest)), nil
+}
+
+// PartA - steps from where the walk ends back home
+func PartA(r io.Reader) (string, error) {
+	return solve(r, func(end hexgrid.Hex, _ int) int { return end.Len() })
+}
+
+// PartB - the furthest the walk ever got from home
+func PartB(r io.Reader) (string, error) {
+	return solve(r, func(_ hexgrid.Hex, furthest int) int { return furthest })
+}
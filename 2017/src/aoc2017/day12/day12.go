@@ -0,0 +1,148 @@
+// Package day12 - 2017 day 12: group programs by the pipes between them
+package day12
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"aoc"
+	"unionfind"
+)
+
+func init() {
+	aoc.Register(aoc.New(2017, 12, PartA, PartB))
+}
+
+// PipeMap - program id -> the programs it has pipes to
+type PipeMap map[int][]int
+
+// Components - every group of connected programs, members ascending, groups
+// ordered by their smallest member
+func (p PipeMap) Components() [][]int {
+	network := NewNetwork()
+	for pid, pipes := range p {
+		network.AddProgram(pid)
+		for _, other := range pipes {
+			network.AddPipe(pid, other)
+		}
+	}
+	return network.Components()
+}
+
+// Network - programs and the pipes between them, with the groups kept up to
+// date as pipes are added
+type Network struct {
+	Pipes PipeMap
+	sets  *unionfind.Set
+}
+
+// NewNetwork - a network with no programs
+func NewNetwork() *Network {
+	return &Network{
+		Pipes: make(PipeMap),
+		sets:  unionfind.New(),
+	}
+}
+
+// AddProgram - add a program with no pipes (yet)
+func (n *Network) AddProgram(pid int) {
+	if _, ok := n.Pipes[pid]; !ok {
+		n.Pipes[pid] = make([]int, 0)
+	}
+	n.sets.Add(pid)
+}
+
+// AddPipe - connect +a+ and +b+ both ways. Returns true if that joined two
+// groups into one.
+func (n *Network) AddPipe(a, b int) bool {
+	n.AddProgram(a)
+	n.AddProgram(b)
+	n.connect(a, b)
+	n.connect(b, a)
+	return n.sets.Union(a, b)
+}
+
+// connect - record the pipe from +a+ to +b+ if it isn't already
+func (n *Network) connect(a, b int) {
+	for _, other := range n.Pipes[a] {
+		if other == b {
+			return
+		}
+	}
+	n.Pipes[a] = append(n.Pipes[a], b)
+}
+
+// Groups - how many groups there are
+func (n *Network) Groups() int {
+	return n.sets.Count()
+}
+
+// GroupSize - how many programs are in +pid+'s group, including itself
+func (n *Network) GroupSize(pid int) int {
+	if _, ok := n.Pipes[pid]; !ok {
+		return 0
+	}
+	return n.sets.Size(pid)
+}
+
+// Components - see PipeMap.Components
+func (n *Network) Components() [][]int {
+	return n.sets.Groups()
+}
+
+// Parse - the network in +r+, one "program <-> pipes" line per program. If
+// +trace+ isn't nil every pipe that joins two groups is written to it.
+func Parse(r io.Reader, trace io.Writer) (*Network, error) {
+	network := NewNetwork()
+	lineReader := bufio.NewScanner(r)
+	for lineReader.Scan() {
+		line := lineReader.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var program int
+		for n, token := range strings.Split(line, " ") {
+			switch n {
+			case 0:
+				var err error
+				program, err = strconv.Atoi(token)
+				if err != nil {
+					return nil, fmt.Errorf("couldn't convert %s to a program id: %w", token, err)
+				}
+				network.AddProgram(program)
+			case 1:
+				continue // <->
+			default:
+				endpoint, err := strconv.Atoi(strings.TrimRight(token, ", "))
+				if err != nil {
+					return nil, fmt.Errorf("couldn't convert %s to a pipe endpoint: %w", token, err)
+				}
+				if network.AddPipe(program, endpoint) && trace != nil {
+					fmt.Fprintf(trace, "Pipe %d <-> %d joined two groups, %d left\n", program, endpoint, network.Groups())
+				}
+			}
+		} // EOL
+	} // EOF
+	return network, lineReader.Err()
+}
+
+func solve(r io.Reader, answer func(*Network) int) (string, error) {
+	network, err := Parse(r, nil)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(answer(network)), nil
+}
+
+// PartA - how many programs are in program 0's group
+func PartA(r io.Reader) (string, error) {
+	return solve(r, func(n *Network) int { return n.GroupSize(0) })
+}
+
+// PartB - how many groups there are
+func PartB(r io.Reader) (string, error) {
+	return solve(r, (*Network).Groups)
+}
@@ -0,0 +1,617 @@
+// Package day13 - 2017 day 13: get a packet through the layers of a
+// firewall without its scanners catching it
+package day13
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2017, 13, PartA, PartB))
+}
+
+// Debug - print what the scanners and the simulation are doing
+var Debug = false
+
+// Firewall - the layers of scanners a packet has to get through
+type Firewall struct {
+	Rules             map[int]int  // layer # -> depth
+	Positions         map[int]int  // current position in each layer
+	MovementDirection map[int]bool // true=down, false=up
+}
+
+func (fw *Firewall) Clone() *Firewall {
+	ret := NewFirewall()
+	for layerNumber := 0; layerNumber <= fw.HighestLayer(); layerNumber++ {
+		ret.Rules[layerNumber] = fw.Rules[layerNumber]
+		ret.Positions[layerNumber] = fw.Positions[layerNumber]
+		ret.MovementDirection[layerNumber] = fw.MovementDirection[layerNumber]
+	}
+	return ret
+}
+
+func (fw *Firewall) AddRuleAtPos(layer, depth int) {
+	fw.Rules[layer] = depth
+	fw.Positions[layer] = 0
+	fw.MovementDirection[layer] = true
+}
+
+// how many layers in total?
+func (fw *Firewall) Len() int {
+	return len(fw.Rules)
+}
+
+/*
+Advance the scanner once. Return a map of what the current position is for
+each layer. If there is a gap between layers there will not be an entry.
+*/
+func (fw *Firewall) Advance() map[int]int {
+	for layerNumber := 0; layerNumber <= fw.HighestLayer(); layerNumber++ {
+		// Skip if there's no rules, or it has 0 length.
+		if fw.Rules[layerNumber] == 0 {
+			continue
+		}
+		/*
+			If we're previously going down:
+			 if i can go down, go down. otherwise, flip direction; go up
+			If we're previously going up:
+			 if i can go up, go up. otherwise, flip direction; go down
+		*/
+		if Debug {
+			fmt.Printf("Advancing to firewall position (layer) %d\n", layerNumber)
+		}
+
+		if fw.MovementDirection[layerNumber] {
+			// Going down
+			if Debug {
+				fmt.Printf("Currently going down ")
+			}
+			if fw.Positions[layerNumber]+1 >= fw.Rules[layerNumber] {
+				if Debug {
+					fmt.Printf("Can't go down because fw.Positions[layerNumber]+1 >= fw.Rules[layerNumber] (%d>=%d)\n", fw.Positions[layerNumber]+1, fw.Rules[layerNumber])
+				}
+				// can't keep going down, so flip around and go up
+				fw.MovementDirection[layerNumber] = false
+				fw.Positions[layerNumber] -= 1
+			} else {
+				if Debug {
+					fmt.Printf("Keeping on going down\n")
+				}
+				// keep going down
+				fw.Positions[layerNumber] += 1
+			}
+		} else {
+			// Going up
+			if Debug {
+				fmt.Printf("Currently going up ")
+			}
+			if fw.Positions[layerNumber]-1 < 0 {
+				if Debug {
+					fmt.Printf("Can't keep going up because fw.Positions[layerNumber]-1 < 0 (%d<0)\n", fw.Positions[layerNumber]-1)
+				}
+				// can't keep going up, so flip around and go down
+				fw.MovementDirection[layerNumber] = true
+				fw.Positions[layerNumber] += 1
+			} else {
+				// keep going up
+				if Debug {
+					fmt.Printf("Keep on going up")
+				}
+				fw.Positions[layerNumber] -= 1
+			}
+		} // end: which way am i going?
+	} // out of layers
+	return fw.Positions
+}
+
+func (fw *Firewall) HighestLayer() int {
+	// highest layer number
+	highest := -1
+	for layerNumber, _ := range fw.Rules {
+		if layerNumber > highest {
+			highest = layerNumber
+		}
+	}
+	return highest
+}
+
+// It's possible to have gaps, so fill them in with 0 depth layers
+func (fw *Firewall) FillInGaps() {
+	for i := 0; i <= fw.HighestLayer(); i++ {
+		if fw.Rules[i] == 0 {
+			fw.Rules[i] = 0
+			fw.MovementDirection[i] = true
+		}
+	}
+}
+
+func (fw *Firewall) PrintMap() {
+	for i := 0; i <= fw.HighestLayer(); i++ {
+		fmt.Printf("Layer %d Depth: %d Pos: %d Down?: %t\n", i, fw.Rules[i], fw.Positions[i], fw.MovementDirection[i])
+	}
+}
+
+// true if the position of the scan is at 0 in layer layerPosition.
+func (fw *Firewall) CheckCollision(layerPosition int) bool {
+	return fw.Rules[layerPosition] > 0 && fw.Positions[layerPosition] == 0
+}
+
+// What's the cost of being caught in layer layerPosition?
+func (fw *Firewall) CollisionCost(layerPosition int) int {
+	return layerPosition * fw.Rules[layerPosition]
+}
+
+/*
+Closed form: a scanner on a layer of depth d is back at the top every
+2*(d-1) picoseconds, and a packet sent after a delay reaches layer n at
+picosecond delay+n. So it's caught on layer n exactly when delay+n is a
+multiple of the period, and nothing needs simulating.
+*/
+
+// Period - picoseconds for the scanner in +layer+ to get back to the top, 0
+// for an empty layer
+func (fw *Firewall) Period(layer int) int {
+	switch depth := fw.Rules[layer]; {
+	case depth <= 0:
+		return 0
+	case depth == 1:
+		return 1
+	default:
+		return 2 * (depth - 1)
+	}
+}
+
+// CaughtAt - is a packet sent after +delay+ caught on +layer+?
+func (fw *Firewall) CaughtAt(layer, delay int) bool {
+	period := fw.Period(layer)
+	return period > 0 && (delay+layer)%period == 0
+}
+
+// SafeDelay - does a packet sent after +delay+ get through uncaught?
+func (fw *Firewall) SafeDelay(delay int) bool {
+	for layer := 0; layer <= fw.HighestLayer(); layer++ {
+		if fw.CaughtAt(layer, delay) {
+			return false
+		}
+	}
+	return true
+}
+
+// Severity - total collision cost for a packet sent after +delay+, carrying on
+// through every layer as part A does
+func (fw *Firewall) Severity(delay int) int {
+	cost := 0
+	for layer := 0; layer <= fw.HighestLayer(); layer++ {
+		if fw.CaughtAt(layer, delay) {
+			cost += fw.CollisionCost(layer)
+		}
+	}
+	return cost
+}
+
+// sieveLimit - most residues SmallestSafeDelay will keep while combining
+// periods before it falls back to checking candidates one by one
+const sieveLimit = 1 << 20
+
+// lcm - least common multiple, or -1 if it doesn't fit in an int
+func lcm(a, b int) int {
+	x, y := a, b
+	for y != 0 {
+		x, y = y, x%y
+	}
+	if a/x > math.MaxInt64/b {
+		return -1
+	}
+	return a / x * b
+}
+
+/*
+SmallestSafeDelay - the first delay that gets through, without simulating.
+
+Each layer forbids one residue modulo its period. Starting from "every delay
+modulo 1 is fine", combine periods in ascending order CRT-style: the safe
+residues modulo lcm(M, p) are the safe residues modulo M, lifted, minus the
+ones p forbids. Once the safe set would grow past sieveLimit, the remaining
+periods are checked directly against candidates drawn from it in order.
+The pattern repeats every lcm of all periods, so if nothing is safe by then
+nothing ever will be.
+*/
+func (fw *Firewall) SmallestSafeDelay() (int, error) {
+	forbidden := make(map[int]map[int]bool) // period -> residues
+	for layer := 0; layer <= fw.HighestLayer(); layer++ {
+		period := fw.Period(layer)
+		if period == 0 {
+			continue
+		}
+		if forbidden[period] == nil {
+			forbidden[period] = make(map[int]bool)
+		}
+		forbidden[period][((-layer)%period+period)%period] = true
+	}
+	periods := make([]int, 0, len(forbidden))
+	for period := range forbidden {
+		periods = append(periods, period)
+	}
+	sort.Ints(periods)
+
+	modulus, safe := 1, []int{0}
+	combined := 0
+	for _, period := range periods {
+		next := lcm(modulus, period)
+		if next < 0 || len(safe)*(next/modulus) > sieveLimit {
+			break
+		}
+		lifted := make([]int, 0)
+		for k := 0; k < next/modulus; k++ {
+			for _, residue := range safe {
+				delay := residue + k*modulus
+				if !forbidden[period][delay%period] {
+					lifted = append(lifted, delay)
+				}
+			}
+		}
+		modulus, safe = next, lifted
+		combined++
+		if len(safe) == 0 {
+			return 0, fmt.Errorf("no delay is safe: period %d rules out everything left modulo %d", period, modulus)
+		}
+		if Debug {
+			fmt.Printf("Combined period %d: %d safe residues modulo %d\n", period, len(safe), modulus)
+		}
+	}
+	sort.Ints(safe)
+
+	rest := periods[combined:]
+	repeat := modulus
+	for _, period := range rest {
+		if repeat = lcm(repeat, period); repeat < 0 {
+			repeat = math.MaxInt64
+			break
+		}
+	}
+	for base := 0; base < repeat && base >= 0; base += modulus {
+	candidates:
+		for _, residue := range safe {
+			delay := base + residue
+			for _, period := range rest {
+				if forbidden[period][delay%period] {
+					continue candidates
+				}
+			}
+			return delay, nil
+		}
+	}
+	return 0, fmt.Errorf("no delay is safe: none of the residues modulo %d survive", repeat)
+}
+
+// Seek - put every scanner where Advance would have it +picosecond+
+// picoseconds after they all started at the top, without stepping there.
+// A scanner keeps its direction until a move would take it off the end, so
+// it is still "down" at the bottom and "up" back at the top.
+func (fw *Firewall) Seek(picosecond int) {
+	for layer := 0; layer <= fw.HighestLayer(); layer++ {
+		depth := fw.Rules[layer]
+		if depth < 2 {
+			fw.Positions[layer] = 0
+			fw.MovementDirection[layer] = true
+			continue
+		}
+		period := fw.Period(layer)
+		k := picosecond % period
+		switch {
+		case k == 0:
+			fw.Positions[layer] = 0
+			fw.MovementDirection[layer] = picosecond == 0
+		case k < depth:
+			fw.Positions[layer] = k
+			fw.MovementDirection[layer] = true
+		default:
+			fw.Positions[layer] = period - k
+			fw.MovementDirection[layer] = false
+		}
+	}
+}
+
+// Frame - the firewall drawn the way the puzzle does, with the packet in
+// +packet+: one column per layer, scanners shown as v or ^ for the way
+// MovementDirection says they're heading, the packet's cell in parentheses
+// and an X where it's caught
+func (fw *Firewall) Frame(packet int) string {
+	var out strings.Builder
+	highest := fw.HighestLayer()
+	deepest := 1
+	for layer := 0; layer <= highest; layer++ {
+		fmt.Fprintf(&out, " %-3d", layer)
+		if fw.Rules[layer] > deepest {
+			deepest = fw.Rules[layer]
+		}
+	}
+	out.WriteString("\n")
+	for row := 0; row < deepest; row++ {
+		for layer := 0; layer <= highest; layer++ {
+			depth := fw.Rules[layer]
+			left, right := "[", "]"
+			if layer == packet && row == 0 {
+				left, right = "(", ")"
+			}
+			switch {
+			case depth == 0 && row == 0 && layer == packet:
+				out.WriteString("(.) ")
+			case depth == 0 && row == 0:
+				out.WriteString("... ")
+			case row >= depth:
+				out.WriteString("    ")
+			case fw.Positions[layer] != row:
+				fmt.Fprintf(&out, "%s %s ", left, right)
+			case layer == packet && row == 0:
+				fmt.Fprintf(&out, "%sX%s ", left, right)
+			case fw.MovementDirection[layer]:
+				fmt.Fprintf(&out, "%sv%s ", left, right)
+			default:
+				fmt.Fprintf(&out, "%s^%s ", left, right)
+			}
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// ReplayFrame - one picosecond of a traversal
+type ReplayFrame struct {
+	Picosecond int          // since the scanners started
+	Packet     int          // layer the packet is in
+	Positions  map[int]int  // scanner positions, by layer
+	Down       map[int]bool // scanner directions, by layer
+	Caught     bool
+	Text       string // Frame drawn as text
+}
+
+// Replay - every picosecond of a packet sent after +delay+ crossing the
+// firewall, with the scanners as Advance moves them. The firewall itself is
+// left alone.
+func (fw *Firewall) Replay(delay int) []ReplayFrame {
+	run := fw.Clone()
+	run.Seek(delay)
+	frames := make([]ReplayFrame, 0)
+	for layer := 0; layer <= run.HighestLayer(); layer++ {
+		if layer > 0 {
+			run.Advance()
+		}
+		frame := ReplayFrame{
+			Picosecond: delay + layer,
+			Packet:     layer,
+			Positions:  make(map[int]int),
+			Down:       make(map[int]bool),
+			Caught:     run.CheckCollision(layer),
+			Text:       run.Frame(layer),
+		}
+		for l := range run.Rules {
+			frame.Positions[l] = run.Positions[l]
+			frame.Down[l] = run.MovementDirection[l]
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// PrintReplay - write the frames of a replay one after another, or over each
+// other with a pause between them if +pause+ is positive
+func (fw *Firewall) PrintReplay(out io.Writer, delay int, pause time.Duration) {
+	caught := make([]string, 0)
+	for _, frame := range fw.Replay(delay) {
+		if pause > 0 {
+			fmt.Fprint(out, "\033[H\033[2J")
+		}
+		fmt.Fprintf(out, "Delay %d, picosecond %d, packet in layer %d\n", delay, frame.Picosecond, frame.Packet)
+		fmt.Fprint(out, frame.Text)
+		if frame.Caught {
+			caught = append(caught, strconv.Itoa(frame.Packet))
+			fmt.Fprintf(out, "Caught in layer %d! (cost %d)\n", frame.Packet, fw.CollisionCost(frame.Packet))
+		}
+		fmt.Fprintln(out)
+		if pause > 0 {
+			time.Sleep(pause)
+		}
+	}
+	if len(caught) == 0 {
+		fmt.Fprintf(out, "Delay %d gets through\n", delay)
+	} else {
+		fmt.Fprintf(out, "Delay %d is caught in layers %s\n", delay, strings.Join(caught, ", "))
+	}
+}
+
+// Colours for WriteReplayGIF
+var replayPalette = color.Palette{
+	color.RGBA{0x0f, 0x0f, 0x23, 0xff}, // background
+	color.RGBA{0x44, 0x44, 0x55, 0xff}, // empty cell
+	color.RGBA{0x44, 0x99, 0xff, 0xff}, // scanner going down
+	color.RGBA{0x33, 0xcc, 0x66, 0xff}, // scanner going up
+	color.RGBA{0xff, 0xff, 0x66, 0xff}, // packet
+	color.RGBA{0xff, 0x33, 0x33, 0xff}, // collision
+}
+
+const (
+	gifCell   = 12 // pixels per cell
+	gifGap    = 4  // pixels between cells
+	gifBorder = 2  // packet / collision outline
+)
+
+// WriteReplayGIF - the replay of +delay+ as an animated GIF, +pause+ per
+// frame. Each layer is a column of cells; scanners are blue going down and
+// green going up, the packet is outlined in yellow at the top of its layer
+// and a collision turns its cell red. The last frame holds for a second.
+func (fw *Firewall) WriteReplayGIF(out io.Writer, delay int, pause time.Duration) error {
+	deepest := 1
+	for _, depth := range fw.Rules {
+		if depth > deepest {
+			deepest = depth
+		}
+	}
+	step := gifCell + gifGap
+	bounds := image.Rect(0, 0, (fw.HighestLayer()+1)*step+gifGap, deepest*step+gifGap)
+	fill := func(img *image.Paletted, r image.Rectangle, index uint8) {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				img.SetColorIndex(x, y, index)
+			}
+		}
+	}
+
+	anim := &gif.GIF{}
+	frames := fw.Replay(delay)
+	for n, frame := range frames {
+		img := image.NewPaletted(bounds, replayPalette)
+		for layer := 0; layer <= fw.HighestLayer(); layer++ {
+			x := gifGap + layer*step
+			for row := 0; row < fw.Rules[layer]; row++ {
+				cell := image.Rect(x, gifGap+row*step, x+gifCell, gifGap+row*step+gifCell)
+				index := uint8(1)
+				if frame.Positions[layer] == row {
+					index = 3
+					if frame.Down[layer] {
+						index = 2
+					}
+				}
+				fill(img, cell, index)
+			}
+			if layer == frame.Packet {
+				cell := image.Rect(x, gifGap, x+gifCell, gifGap+gifCell)
+				index := uint8(4)
+				if frame.Caught {
+					index = 5
+					fill(img, cell, index)
+				}
+				outline := cell.Inset(-gifBorder)
+				fill(img, image.Rect(outline.Min.X, outline.Min.Y, outline.Max.X, cell.Min.Y), index)
+				fill(img, image.Rect(outline.Min.X, cell.Max.Y, outline.Max.X, outline.Max.Y), index)
+				fill(img, image.Rect(outline.Min.X, cell.Min.Y, cell.Min.X, cell.Max.Y), index)
+				fill(img, image.Rect(cell.Max.X, cell.Min.Y, outline.Max.X, cell.Max.Y), index)
+			}
+		}
+		hold := int(pause / (10 * time.Millisecond))
+		if n == len(frames)-1 {
+			hold += 100
+		}
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, hold)
+	}
+	return gif.EncodeAll(out, anim)
+}
+
+/*
+Try to run through the layers with the current configuration (eg scan position) to see if the checker is at the top.
+Returns: success/failure, failure position, collision cost.
+*/
+func (fw *Firewall) CheckRun() (bool, int, int) {
+	collisionCost := 0
+	failPosition := 0
+	ret := true
+	if Debug {
+		fmt.Printf("Firewall at the start of CheckRun\n")
+		fw.PrintMap()
+	}
+	// Now, run through the firewall
+	// check initial condition; later, do them all
+	if fw.CheckCollision(0) {
+		collisionCost += fw.CollisionCost(0)
+		if Debug {
+			fmt.Printf("Firewall at end of CheckRun (0 check)\n")
+			fw.PrintMap()
+		}
+		return false, 0, collisionCost
+	}
+	for position := 1; position <= fw.HighestLayer(); position++ {
+		fw.Advance()
+		if Debug {
+			fmt.Printf("CheckRun - checking at layer %d\n", position)
+		}
+		if fw.CheckCollision(position) {
+			failPosition = position
+			collisionCost += fw.CollisionCost(position)
+			ret = false
+			break
+		}
+	}
+	if Debug {
+		fmt.Printf("Firewall at end of CheckRun\n")
+		fw.PrintMap()
+	}
+	return ret, failPosition, collisionCost
+}
+
+// NewFirewall - a firewall with no layers
+func NewFirewall() *Firewall {
+	return &Firewall{
+		Rules:             make(map[int]int),
+		Positions:         make(map[int]int),
+		MovementDirection: make(map[int]bool),
+	}
+}
+
+// Parse - the firewall in +r+, one "layer: depth" line per layer, with the
+// gaps filled in
+func Parse(r io.Reader) (*Firewall, error) {
+	firewall := NewFirewall()
+	lineReader := bufio.NewScanner(r)
+	for lineReader.Scan() {
+		line := lineReader.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var layer, depth int
+		var err error
+		for n, token := range strings.Split(line, ":") {
+			switch n {
+			case 0:
+				layer, err = strconv.Atoi(strings.Trim(token, " "))
+				if err != nil {
+					return nil, fmt.Errorf("couldn't convert %s to layer number", token)
+				}
+			case 1:
+				depth, err = strconv.Atoi(strings.Trim(token, " "))
+				if err != nil {
+					return nil, fmt.Errorf("couldn't convert %s to depth", token)
+				}
+			default:
+				return nil, fmt.Errorf("unknown item found at %d (%s) in %s", n, token, line)
+			}
+		} // EOL
+		firewall.AddRuleAtPos(layer, depth)
+	} // EOF
+	if err := lineReader.Err(); err != nil {
+		return nil, err
+	}
+	firewall.FillInGaps()
+	return firewall, nil
+}
+
+// PartA - the severity of going straight away
+func PartA(r io.Reader) (string, error) {
+	firewall, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(firewall.Severity(0)), nil
+}
+
+// PartB - the shortest delay that gets through without being caught
+func PartB(r io.Reader) (string, error) {
+	firewall, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	delay, err := firewall.SmallestSafeDelay()
+	return strconv.Itoa(delay), err
+}
@@ -0,0 +1,194 @@
+package day13
+
+import (
+	"bytes"
+	"fmt"
+	"image/gif"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// simulate - send a packet through a copy of +fw+ as its scanners stand now,
+// stepping them the slow way. Returns whether it got through and what it
+// cost, carrying on through every layer as part A does.
+func simulate(fw *Firewall) (bool, int) {
+	run := fw.Clone()
+	cost := 0
+	safe := true
+	for position := 0; position <= run.HighestLayer(); position++ {
+		if position > 0 {
+			run.Advance()
+		}
+		if run.CheckCollision(position) {
+			cost += run.CollisionCost(position)
+			safe = false
+		}
+	}
+	return safe, cost
+}
+
+// repeat - picoseconds until every scanner in +fw+ is back where it started
+func repeat(fw *Firewall) int {
+	r := 1
+	for layer := 0; layer <= fw.HighestLayer(); layer++ {
+		if period := fw.Period(layer); period > 0 {
+			r = lcm(r, period)
+		}
+	}
+	return r
+}
+
+// checkClosedForm - compare Seek, SafeDelay, Severity and SmallestSafeDelay
+// with the simulation for every delay before the scanners repeat
+func checkClosedForm(t *testing.T, name string, fw *Firewall) {
+	t.Helper()
+	firstSafe := -1
+	scanners := fw.Clone()
+	for delay := 0; delay < repeat(fw); delay, _ = delay+1, scanners.Advance() {
+		seek := fw.Clone()
+		seek.Seek(delay)
+		for layer := range scanners.Rules {
+			if seek.Positions[layer] != scanners.Positions[layer] || seek.MovementDirection[layer] != scanners.MovementDirection[layer] {
+				t.Errorf("%s delay %d: layer %d scanner at %d (down=%t), Seek says %d (down=%t)", name, delay, layer,
+					scanners.Positions[layer], scanners.MovementDirection[layer], seek.Positions[layer], seek.MovementDirection[layer])
+			}
+		}
+		safe, cost := simulate(scanners)
+		if safe != fw.SafeDelay(delay) {
+			t.Errorf("%s delay %d: simulation safe=%t, closed form safe=%t", name, delay, safe, fw.SafeDelay(delay))
+		}
+		if cost != fw.Severity(delay) {
+			t.Errorf("%s delay %d: simulation severity %d, closed form %d", name, delay, cost, fw.Severity(delay))
+		}
+		if safe && firstSafe < 0 {
+			firstSafe = delay
+		}
+	}
+	found, err := fw.SmallestSafeDelay()
+	switch {
+	case firstSafe < 0 && err == nil:
+		t.Errorf("%s: nothing is safe before the scanners repeat, but SmallestSafeDelay found %d", name, found)
+	case firstSafe >= 0 && err != nil:
+		t.Errorf("%s: simulation is safe after %d, SmallestSafeDelay says %s", name, firstSafe, err)
+	case firstSafe >= 0 && found != firstSafe:
+		t.Errorf("%s: simulation is first safe after %d, SmallestSafeDelay says %d", name, firstSafe, found)
+	}
+}
+
+// example - the firewall in the puzzle's example
+func example(t *testing.T) *Firewall {
+	t.Helper()
+	input, err := os.Open("../../../inputs/day13-example.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer input.Close()
+	fw, err := Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fw
+}
+
+func TestExample(t *testing.T) {
+	fw := example(t)
+	if got := fw.Severity(0); got != 24 {
+		t.Errorf("severity: got %d, want 24", got)
+	}
+	if got, err := fw.SmallestSafeDelay(); err != nil || got != 10 {
+		t.Errorf("smallest safe delay: got %d (%v), want 10", got, err)
+	}
+	checkClosedForm(t, "example", fw)
+}
+
+func TestGenerated(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	for n := 0; n < 20; n++ {
+		fw := NewFirewall()
+		for layer := 0; layer < 10; layer++ {
+			// a gap now and then; depth 1 never comes up in the puzzle and
+			// Advance can't move a scanner that has nowhere to go
+			if rng.Intn(3) > 0 {
+				fw.AddRuleAtPos(layer, 2+rng.Intn(5))
+			}
+		}
+		fw.FillInGaps()
+		checkClosedForm(t, fmt.Sprintf("firewall %d %v", n, fw.Rules), fw)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	fw := example(t)
+	tests := []struct {
+		delay  int
+		caught []int
+	}{
+		{0, []int{0, 6}},
+		{10, nil},
+	}
+	for _, test := range tests {
+		frames := fw.Replay(test.delay)
+		if len(frames) != 7 {
+			t.Errorf("delay %d: %d frames, want one per layer, 7", test.delay, len(frames))
+		}
+		var caught []int
+		for i, frame := range frames {
+			if frame.Packet != i || frame.Picosecond != test.delay+i {
+				t.Errorf("delay %d frame %d: packet in layer %d at picosecond %d", test.delay, i, frame.Packet, frame.Picosecond)
+			}
+			if frame.Caught {
+				caught = append(caught, frame.Packet)
+			}
+			if marks := strings.Count(frame.Text, "(X)"); marks != map[bool]int{true: 1, false: 0}[frame.Caught] {
+				t.Errorf("delay %d frame %d: %d collision marks, caught=%t:\n%s", test.delay, i, marks, frame.Caught, frame.Text)
+			}
+		}
+		if !slices.Equal(caught, test.caught) {
+			t.Errorf("delay %d: caught in layers %v, want %v", test.delay, caught, test.caught)
+		}
+	}
+
+	// the puzzle's drawing of picosecond 6 with no delay
+	want := ` 0   1   2   3   4   5   6  
+[ ] [^] ... ... [^] ... (X) 
+[ ] [ ]         [ ]     [ ] 
+[v]             [ ]     [ ] 
+                [ ]     [ ] 
+`
+	if got := fw.Replay(0)[6].Text; got != want {
+		t.Errorf("picosecond 6:\n%s\nwant:\n%s", got, want)
+	}
+	if fw.Positions[0] != 0 || !fw.MovementDirection[0] {
+		t.Errorf("Replay moved the firewall's own scanners")
+	}
+}
+
+func TestWriteReplayGIF(t *testing.T) {
+	fw := example(t)
+	for _, delay := range []int{0, 10} {
+		var out bytes.Buffer
+		if err := fw.WriteReplayGIF(&out, delay, 200*time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+		anim, err := gif.DecodeAll(&out)
+		if err != nil {
+			t.Fatalf("delay %d: %s", delay, err)
+		}
+		if len(anim.Image) != 7 {
+			t.Errorf("delay %d: %d frames, want 7", delay, len(anim.Image))
+		}
+		if want := []int{20, 20, 20, 20, 20, 20, 120}; !slices.Equal(anim.Delay, want) {
+			t.Errorf("delay %d: frame delays %v, want %v", delay, anim.Delay, want)
+		}
+		// the middle of layer 0's top cell: red when it's caught there, and
+		// empty at delay 10, with the scanner at the bottom
+		centre := gifGap + gifCell/2
+		if got, want := anim.Image[0].ColorIndexAt(centre, centre), map[bool]uint8{true: 5, false: 1}[delay == 0]; got != want {
+			t.Errorf("delay %d: layer 0's top cell has colour %d, want %d", delay, got, want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package day14 - 2017 day 14: the disk grid made of knot hashes, its used
+// squares and the regions they form
+package day14
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"aoc"
+	"knothash"
+	"regions"
+)
+
+func init() {
+	aoc.Register(aoc.New(2017, 14, PartA, PartB))
+}
+
+// GridSize - the disk is GridSize x GridSize squares, one row per hash
+const GridSize = 128
+
+// ActiveBitsToString - a row as # for used squares and . for free ones, for
+// printing the fragmentation
+func ActiveBitsToString(hash [knothash.Size]byte) string {
+	ret := ""
+	for _, b := range hash {
+		for bit := 7; bit >= 0; bit-- {
+			if b&(1<<uint(bit)) != 0 {
+				ret += "#"
+			} else {
+				ret += "."
+			}
+		}
+	}
+	return ret
+}
+
+// CountActiveBits - how many squares in a row are used
+func CountActiveBits(hash [knothash.Size]byte) int {
+	ret := 0
+	for _, b := range hash {
+		ret += bits.OnesCount8(b)
+	}
+	return ret
+}
+
+func stringToChars(str string) []byte {
+	ret := make([]byte, len(str))
+	for i, char := range strings.Split(str, "") {
+		ret[i] = byte(char[0])
+	}
+	return ret
+}
+
+// DiskGrid - the used squares of the disk and the regions they form
+type DiskGrid struct {
+	Hashes  [][knothash.Size]byte // one per row, each bit a square
+	Used    [GridSize][GridSize]bool
+	Regions *regions.Labelling
+}
+
+// NewDiskGrid - the grid for the key +key+: row n is the knot hash of
+// "key-n"
+func NewDiskGrid(key string) *DiskGrid {
+	keys := make([][]byte, GridSize)
+	for i := range keys {
+		keys[i] = stringToChars(fmt.Sprintf("%s-%d", key, i))
+	}
+	g := &DiskGrid{Hashes: knothash.SumAll(keys, 0)}
+	for y, hash := range g.Hashes {
+		for x := 0; x < GridSize; x++ {
+			g.Used[y][x] = hash[x/8]&(0x80>>uint(x%8)) != 0
+		}
+	}
+	g.Regions = regions.Label(GridSize, GridSize, func(x, y int) bool { return g.Used[y][x] })
+	return g
+}
+
+// UsedSquares - how many squares are used
+func (g *DiskGrid) UsedSquares() int {
+	return GridSize*GridSize - g.Regions.Sizes[0]
+}
+
+// regionSymbols - cycled through to letter regions in String
+const regionSymbols = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// String - the grid with free squares as '.' and used ones lettered by
+// region; neighbouring regions can share a letter once they run out
+func (g *DiskGrid) String() string {
+	var out strings.Builder
+	for y := range g.Used {
+		for x := range g.Used[y] {
+			if label := g.Regions.Labels[y][x]; label == 0 {
+				out.WriteByte('.')
+			} else {
+				out.WriteByte(regionSymbols[(label-1)%len(regionSymbols)])
+			}
+		}
+		out.WriteByte('\n')
+	}
+	return out.String()
+}
+
+// regionColour - a colour for region +label+. Stepping the hue by the golden
+// ratio keeps consecutive labels, which are often neighbours, far apart.
+func regionColour(label int) color.RGBA {
+	h := float64(label) * 0.618033988749895
+	h -= float64(int(h))
+	// HSV to RGB with full saturation and value
+	sector := int(h * 6)
+	f := h*6 - float64(sector)
+	rise, fall := uint8(255*f), uint8(255*(1-f))
+	switch sector {
+	case 0:
+		return color.RGBA{255, rise, 0, 255}
+	case 1:
+		return color.RGBA{fall, 255, 0, 255}
+	case 2:
+		return color.RGBA{0, 255, rise, 255}
+	case 3:
+		return color.RGBA{0, fall, 255, 255}
+	case 4:
+		return color.RGBA{rise, 0, 255, 255}
+	default:
+		return color.RGBA{255, 0, fall, 255}
+	}
+}
+
+// WritePNG - draw the grid with +scale+ pixels per square, free squares
+// black and each region its own colour
+func (g *DiskGrid) WritePNG(out io.Writer, scale int) error {
+	img := image.NewRGBA(image.Rect(0, 0, GridSize*scale, GridSize*scale))
+	for y := 0; y < GridSize*scale; y++ {
+		for x := 0; x < GridSize*scale; x++ {
+			c := color.RGBA{0, 0, 0, 255}
+			if label := g.Regions.Labels[y/scale][x/scale]; label != 0 {
+				c = regionColour(label)
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return png.Encode(out, img)
+}
+
+func read(r io.Reader) (*DiskGrid, error) {
+	key, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewDiskGrid(strings.TrimSpace(string(key))), nil
+}
+
+// PartA - how many squares are used
+func PartA(r io.Reader) (string, error) {
+	grid, err := read(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(grid.UsedSquares()), nil
+}
+
+// PartB - how many regions the used squares form
+func PartB(r io.Reader) (string, error) {
+	grid, err := read(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(grid.Regions.Count()), nil
+}
@@ -0,0 +1,84 @@
+// Package cycle - find where an iterated function starts repeating.
+//
+// Starting from x0 and applying next over and over gives x0, x1, x2, ...
+// Once a state repeats, the sequence cycles for ever: Mu is the index of the
+// first state in the cycle and Lambda is the cycle's length, so x(Mu) is the
+// first state seen twice and is seen again at x(Mu+Lambda).
+//
+// Both algorithms keep a fixed handful of states however long the sequence
+// runs, so next must return a new state rather than change the one it's
+// given. Equality is up to the caller: states that are equal "enough" (eg the
+// same shape in a different place) count as a repeat.
+//
+// 2018 uses this package too, through the symlink 2018/src/cycle.
+package cycle
+
+// Floyd - Mu and Lambda by Floyd's tortoise and hare: the hare moves twice
+// as fast as the tortoise until they meet inside the cycle, which takes about
+// Mu+Lambda steps.
+func Floyd[S any](x0 S, next func(S) S, equal func(a, b S) bool) (mu, lambda int) {
+	tortoise, hare := next(x0), next(next(x0))
+	for !equal(tortoise, hare) {
+		tortoise, hare = next(tortoise), next(next(hare))
+	}
+
+	// The distance from x0 to the meeting point is a multiple of Lambda, so
+	// stepping one pointer from x0 and the other from the meeting point at
+	// the same speed brings them together at x(Mu)
+	tortoise = x0
+	for !equal(tortoise, hare) {
+		tortoise, hare = next(tortoise), next(hare)
+		mu++
+	}
+
+	lambda = 1
+	hare = next(tortoise)
+	for !equal(tortoise, hare) {
+		hare = next(hare)
+		lambda++
+	}
+	return mu, lambda
+}
+
+// Brent - Mu and Lambda by Brent's algorithm: look for the tortoise with a
+// hare that teleports to it at each power of two. Finds Lambda directly and
+// usually calls next fewer times than Floyd.
+func Brent[S any](x0 S, next func(S) S, equal func(a, b S) bool) (mu, lambda int) {
+	power, lambda := 1, 1
+	tortoise, hare := x0, next(x0)
+	for !equal(tortoise, hare) {
+		if power == lambda {
+			tortoise = hare
+			power *= 2
+			lambda = 0
+		}
+		hare = next(hare)
+		lambda++
+	}
+
+	// Start the hare Lambda ahead of the tortoise; they meet at x(Mu)
+	tortoise, hare = x0, x0
+	for i := 0; i < lambda; i++ {
+		hare = next(hare)
+	}
+	for !equal(tortoise, hare) {
+		tortoise, hare = next(tortoise), next(hare)
+		mu++
+	}
+	return mu, lambda
+}
+
+// Nth - x(+n+), making use of the cycle found by Floyd or Brent to skip
+// straight to the equivalent state within the first loop round it. If states
+// are only equal "enough", the state returned is the equivalent one and the
+// caller has to account for the difference.
+func Nth[S any](x0 S, next func(S) S, mu, lambda, n int) S {
+	if n > mu && lambda > 0 {
+		n = mu + (n-mu)%lambda
+	}
+	x := x0
+	for i := 0; i < n; i++ {
+		x = next(x)
+	}
+	return x
+}
@@ -0,0 +1,95 @@
+package cycle
+
+import (
+	"testing"
+)
+
+func equal(a, b int) bool {
+	return a == b
+}
+
+// rho - a sequence 0, 1, 2, ... that runs +mu+ states before a cycle of
+// +lambda+
+func rho(mu, lambda int) func(int) int {
+	return func(x int) int {
+		if x == mu+lambda-1 {
+			return mu
+		}
+		return x + 1
+	}
+}
+
+// naive - Mu and Lambda by remembering every state
+func naive(x0 int, next func(int) int) (int, int) {
+	seen := make(map[int]int)
+	for i, x := 0, x0; ; i, x = i+1, next(x) {
+		if first, ok := seen[x]; ok {
+			return first, i - first
+		}
+		seen[x] = i
+	}
+}
+
+func TestFloydAndBrent(t *testing.T) {
+	type sequence struct {
+		name string
+		x0   int
+		next func(int) int
+	}
+	sequences := []sequence{
+		{"fixed point", 7, func(x int) int { return x }},                       // mu 0, lambda 1
+		{"tail into a fixed point", 0, func(x int) int { return min(x+1, 5) }}, // mu 5, lambda 1
+		{"squares mod 255", 3, func(x int) int { return (x*x + 1) % 255 }},
+		{"doubling mod 1000", 1, func(x int) int { return 2 * x % 1000 }},
+	}
+	for mu := 0; mu <= 6; mu++ {
+		for lambda := 1; lambda <= 7; lambda++ {
+			sequences = append(sequences, sequence{"rho", 0, rho(mu, lambda)})
+		}
+	}
+	for _, s := range sequences {
+		wantMu, wantLambda := naive(s.x0, s.next)
+		if mu, lambda := Floyd(s.x0, s.next, equal); mu != wantMu || lambda != wantLambda {
+			t.Errorf("%s from %d: Floyd got (%d, %d), want (%d, %d)", s.name, s.x0, mu, lambda, wantMu, wantLambda)
+		}
+		if mu, lambda := Brent(s.x0, s.next, equal); mu != wantMu || lambda != wantLambda {
+			t.Errorf("%s from %d: Brent got (%d, %d), want (%d, %d)", s.name, s.x0, mu, lambda, wantMu, wantLambda)
+		}
+	}
+
+	// check naive itself on a couple
+	if mu, lambda := naive(0, rho(3, 4)); mu != 3 || lambda != 4 {
+		t.Errorf("naive got (%d, %d) for (3, 4)", mu, lambda)
+	}
+	if mu, lambda := naive(7, func(x int) int { return x }); mu != 0 || lambda != 1 {
+		t.Errorf("naive got (%d, %d) for a fixed point", mu, lambda)
+	}
+}
+
+func TestNth(t *testing.T) {
+	next := rho(5, 7)
+	mu, lambda := Brent(0, next, equal)
+	x := 0
+	for n := 0; n < 100; n++ {
+		if got := Nth(0, next, mu, lambda, n); got != x {
+			t.Errorf("x(%d): got %d, want %d", n, got, x)
+		}
+		x = next(x)
+	}
+	// 5 + (1000000000-5)%7 = 5 + 1
+	if got := Nth(0, next, mu, lambda, 1000000000); got != 6 {
+		t.Errorf("x(1000000000): got %d, want 6", got)
+	}
+
+	// states equal "enough": only the last digit matters, so the state
+	// returned is the equivalent one in the first loop
+	step := func(x int) int { return x + 3 }
+	lastDigit := func(a, b int) bool { return a%10 == b%10 }
+	mu, lambda = Floyd(0, step, lastDigit)
+	if mu != 0 || lambda != 10 {
+		t.Fatalf("last digits: got (%d, %d), want (0, 10)", mu, lambda)
+	}
+	if got := Nth(0, step, mu, lambda, 25); got != 15 {
+		t.Errorf("x(25) by last digit: got %d, want 15 (standing in for 75)", got)
+	}
+}
@@ -0,0 +1,166 @@
+package hexgrid
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Grid - a value of type T on each hex that has one
+type Grid[T any] map[Hex]T
+
+// NewGrid - an empty grid
+func NewGrid[T any]() Grid[T] {
+	return make(Grid[T])
+}
+
+// Neighbours - the hexes next to +h+ that are in the grid
+func (g Grid[T]) Neighbours(h Hex) []Hex {
+	ret := make([]Hex, 0, 6)
+	for _, n := range h.Neighbours() {
+		if _, ok := g[n]; ok {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
+
+// Distances - how many steps it takes to reach each hex from +from+ moving
+// only through hexes in the grid that +passable+ accepts (a nil +passable+
+// accepts them all). +from+ itself is always 0.
+func (g Grid[T]) Distances(from Hex, passable func(Hex, T) bool) map[Hex]int {
+	dist := map[Hex]int{from: 0}
+	queue := []Hex{from}
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		for _, n := range g.Neighbours(h) {
+			if _, seen := dist[n]; seen {
+				continue
+			}
+			if passable != nil && !passable(n, g[n]) {
+				continue
+			}
+			dist[n] = dist[h] + 1
+			queue = append(queue, n)
+		}
+	}
+	return dist
+}
+
+// Path - a shortest route from +from+ to +to+, both included, moving as
+// Distances does. False if +to+ can't be reached.
+func (g Grid[T]) Path(from, to Hex, passable func(Hex, T) bool) ([]Hex, bool) {
+	previous := map[Hex]Hex{from: from}
+	queue := []Hex{from}
+	for len(queue) > 0 && queue[0] != to {
+		h := queue[0]
+		queue = queue[1:]
+		for _, n := range g.Neighbours(h) {
+			if _, seen := previous[n]; seen {
+				continue
+			}
+			if passable != nil && !passable(n, g[n]) {
+				continue
+			}
+			previous[n] = h
+			queue = append(queue, n)
+		}
+	}
+	if _, ok := previous[to]; !ok {
+		return nil, false
+	}
+	path := []Hex{to}
+	for h := to; h != from; {
+		h = previous[h]
+		path = append(path, h)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, true
+}
+
+// ASCII - the grid as text, one character per hex from +label+, laid out in
+// doubled coordinates so the rows and columns line up the way +o+ draws
+// them. Gaps are spaces.
+func (g Grid[T]) ASCII(o *Orientation, label func(Hex, T) rune) string {
+	if len(g) == 0 {
+		return ""
+	}
+	minCol, minRow := math.MaxInt64, math.MaxInt64
+	maxCol, maxRow := math.MinInt64, math.MinInt64
+	for h := range g {
+		col, row := o.doubled(h)
+		minCol, maxCol = min(minCol, col), max(maxCol, col)
+		minRow, maxRow = min(minRow, row), max(maxRow, row)
+	}
+	lines := make([][]rune, maxRow-minRow+1)
+	for i := range lines {
+		lines[i] = []rune(strings.Repeat(" ", maxCol-minCol+1))
+	}
+	for h, v := range g {
+		col, row := o.doubled(h)
+		lines[row-minRow][col-minCol] = label(h, v)
+	}
+	var out strings.Builder
+	for _, line := range lines {
+		out.WriteString(strings.TrimRight(string(line), " "))
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// SVG - the grid as an SVG document with hexes of radius +size+. +style+
+// gives each hex a fill colour and a (possibly empty) label.
+func (g Grid[T]) SVG(o *Orientation, size float64, style func(Hex, T) (fill, label string)) string {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for h := range g {
+		for _, c := range o.Corners(h, size) {
+			minX, maxX = math.Min(minX, c[0]), math.Max(maxX, c[0])
+			minY, maxY = math.Min(minY, c[1]), math.Max(maxY, c[1])
+		}
+	}
+	if len(g) == 0 {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"%.2f %.2f %.2f %.2f\">\n",
+		minX-1, minY-1, maxX-minX+2, maxY-minY+2)
+	// draw in a fixed order so the output is stable
+	for _, h := range sortedHexes(g) {
+		fill, label := style(h, g[h])
+		points := make([]string, 0, 6)
+		for _, c := range o.Corners(h, size) {
+			points = append(points, fmt.Sprintf("%.2f,%.2f", c[0], c[1]))
+		}
+		fmt.Fprintf(&out, "  <polygon points=\"%s\" fill=\"%s\" stroke=\"black\" stroke-width=\"%.2f\"/>\n",
+			strings.Join(points, " "), html.EscapeString(fill), size/20)
+		if label != "" {
+			x, y := o.ToPixel(h, size)
+			fmt.Fprintf(&out, "  <text x=\"%.2f\" y=\"%.2f\" font-size=\"%.2f\" text-anchor=\"middle\" dominant-baseline=\"middle\">%s</text>\n",
+				x, y, size/2, html.EscapeString(label))
+		}
+	}
+	out.WriteString("</svg>\n")
+	return out.String()
+}
+
+// sortedHexes - the grid's hexes by R then Q
+func sortedHexes[T any](g Grid[T]) []Hex {
+	ret := make([]Hex, 0, len(g))
+	for h := range g {
+		ret = append(ret, h)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].R != ret[j].R {
+			return ret[i].R < ret[j].R
+		}
+		return ret[i].Q < ret[j].Q
+	})
+	return ret
+}
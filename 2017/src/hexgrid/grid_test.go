@@ -0,0 +1,87 @@
+package hexgrid
+
+import (
+	"testing"
+)
+
+// notWall - passable for grids of runes with '#' for walls
+func notWall(h Hex, v rune) bool {
+	return v != '#'
+}
+
+func TestDistancesAndPath(t *testing.T) {
+	g := NewGrid[rune]()
+	for _, h := range Spiral(Origin, 2) {
+		g[h] = '.'
+	}
+	g[Origin] = '#'
+	from, to := Hex{Q: -1, R: 0}, Hex{Q: 1, R: 0}
+
+	if d := g.Distances(from, nil)[to]; d != 2 {
+		t.Errorf("through the middle: %d steps, want 2", d)
+	}
+	dist := g.Distances(from, notWall)
+	if dist[to] != 3 {
+		t.Errorf("round the wall: %d steps, want 3", dist[to])
+	}
+	if _, ok := dist[Origin]; ok {
+		t.Errorf("the wall has a distance")
+	}
+	if len(dist) != len(g)-1 {
+		t.Errorf("reached %d hexes, want all %d but the wall", len(dist), len(g)-1)
+	}
+
+	path, ok := g.Path(from, to, notWall)
+	if !ok || len(path) != 4 || path[0] != from || path[3] != to || !adjacent(path) {
+		t.Fatalf("path round the wall: got %v (%t)", path, ok)
+	}
+	for _, h := range path {
+		if g[h] == '#' {
+			t.Errorf("path goes through the wall at %s", h)
+		}
+	}
+	if path, ok := g.Path(from, from, notWall); !ok || len(path) != 1 {
+		t.Errorf("path to itself: got %v (%t)", path, ok)
+	}
+
+	// wall the target in
+	for _, h := range Ring(to, 1) {
+		if _, ok := g[h]; ok {
+			g[h] = '#'
+		}
+	}
+	if path, ok := g.Path(from, to, notWall); ok {
+		t.Errorf("walled in, but got a path %v", path)
+	}
+	if _, ok := g.Distances(from, notWall)[to]; ok {
+		t.Errorf("walled in, but it has a distance")
+	}
+}
+
+func TestASCII(t *testing.T) {
+	g := NewGrid[rune]()
+	g[Origin] = 'o'
+	for d, h := range Origin.Neighbours() {
+		g[h] = rune('0' + d)
+	}
+	g[Hex{Q: 2, R: -1}] = 'x'
+	label := func(h Hex, v rune) rune { return v }
+
+	tests := []struct {
+		o    *Orientation
+		want string
+	}{
+		// flat topped: columns, with north straight up
+		{Flat, " 5\n4 0\n o x\n3 1\n 2\n"},
+		// pointy topped: rows, with east straight across
+		{Pointy, " 5 0 x\n4 o 1\n 3 2\n"},
+	}
+	for _, test := range tests {
+		if got := g.ASCII(test.o, label); got != test.want {
+			t.Errorf("%s:\n%s\nwant:\n%s", test.o.Name, got, test.want)
+		}
+	}
+	if got := NewGrid[rune]().ASCII(Flat, label); got != "" {
+		t.Errorf("empty grid: got %q", got)
+	}
+}
@@ -0,0 +1,185 @@
+// Package hexgrid - hexagonal grid coordinates, grown out of 2017 day 11.
+//
+// Hexes use axial coordinates (Q, R); the third cube coordinate S is always
+// -Q-R and Cube converts between the two. The six directions are numbered
+// clockwise from north-east, and an Orientation gives them names: flat
+// topped grids (like day 11) have n and s, pointy topped ones e and w.
+// See https://www.redblobgames.com/grids/hexagons/ for the maths.
+package hexgrid
+
+import (
+	"fmt"
+	"math"
+)
+
+// Hex - a hex in axial coordinates
+type Hex struct {
+	Q, R int
+}
+
+// Cube - a hex in cube coordinates, X+Y+Z == 0
+type Cube struct {
+	X, Y, Z int
+}
+
+// Origin - the hex at (0, 0)
+var Origin = Hex{}
+
+func (h Hex) String() string {
+	return fmt.Sprintf("(%d,%d)", h.Q, h.R)
+}
+
+// S - the implied third coordinate
+func (h Hex) S() int {
+	return -h.Q - h.R
+}
+
+// Cube - +h+ in cube coordinates
+func (h Hex) Cube() Cube {
+	return Cube{X: h.Q, Y: h.S(), Z: h.R}
+}
+
+// Hex - +c+ in axial coordinates
+func (c Cube) Hex() Hex {
+	return Hex{Q: c.X, R: c.Z}
+}
+
+// Add - +h+ moved by +o+
+func (h Hex) Add(o Hex) Hex {
+	return Hex{Q: h.Q + o.Q, R: h.R + o.R}
+}
+
+// Sub - the offset from +o+ to +h+
+func (h Hex) Sub(o Hex) Hex {
+	return Hex{Q: h.Q - o.Q, R: h.R - o.R}
+}
+
+// Scale - +h+ multiplied by +k+
+func (h Hex) Scale(k int) Hex {
+	return Hex{Q: h.Q * k, R: h.R * k}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Len - steps from the origin to +h+
+func (h Hex) Len() int {
+	return (abs(h.Q) + abs(h.R) + abs(h.S())) / 2
+}
+
+// Distance - steps from +a+ to +b+
+func Distance(a, b Hex) int {
+	return a.Sub(b).Len()
+}
+
+// directions - unit offsets, clockwise from north-east
+var directions = [6]Hex{
+	{Q: 1, R: -1},
+	{Q: 1, R: 0},
+	{Q: 0, R: 1},
+	{Q: -1, R: 1},
+	{Q: -1, R: 0},
+	{Q: 0, R: -1},
+}
+
+// Direction - the unit offset for direction +d+ (0-5 clockwise from
+// north-east; anything else wraps round)
+func Direction(d int) Hex {
+	return directions[((d%6)+6)%6]
+}
+
+// Neighbour - the hex next to +h+ in direction +d+
+func (h Hex) Neighbour(d int) Hex {
+	return h.Add(Direction(d))
+}
+
+// Neighbours - all six hexes next to +h+, clockwise from north-east
+func (h Hex) Neighbours() [6]Hex {
+	var ret [6]Hex
+	for d := range directions {
+		ret[d] = h.Neighbour(d)
+	}
+	return ret
+}
+
+// RotateRight - +h+ turned 60 degrees clockwise about the origin
+func (h Hex) RotateRight() Hex {
+	return Hex{Q: -h.R, R: -h.S()}
+}
+
+// RotateLeft - +h+ turned 60 degrees anticlockwise about the origin
+func (h Hex) RotateLeft() Hex {
+	return Hex{Q: -h.S(), R: -h.Q}
+}
+
+// Rotate - +h+ turned +steps+ sixths of a turn clockwise (negative for
+// anticlockwise) about +center+
+func (h Hex) Rotate(center Hex, steps int) Hex {
+	v := h.Sub(center)
+	for i := 0; i < ((steps%6)+6)%6; i++ {
+		v = v.RotateRight()
+	}
+	return center.Add(v)
+}
+
+// Ring - every hex exactly +radius+ steps from +center+, clockwise starting
+// at the corner in Direction(4) (nw on flat grids, w on pointy ones)
+func Ring(center Hex, radius int) []Hex {
+	if radius <= 0 {
+		return []Hex{center}
+	}
+	ret := make([]Hex, 0, 6*radius)
+	h := center.Add(Direction(4).Scale(radius))
+	for d := 0; d < 6; d++ {
+		for i := 0; i < radius; i++ {
+			ret = append(ret, h)
+			h = h.Neighbour(d)
+		}
+	}
+	return ret
+}
+
+// Spiral - +center+ then each ring around it out to +radius+
+func Spiral(center Hex, radius int) []Hex {
+	ret := []Hex{center}
+	for r := 1; r <= radius; r++ {
+		ret = append(ret, Ring(center, r)...)
+	}
+	return ret
+}
+
+// Round - the hex containing the fractional axial coordinates (+q+, +r+)
+func Round(q, r float64) Hex {
+	s := -q - r
+	rq, rr, rs := math.Round(q), math.Round(r), math.Round(s)
+	dq, dr, ds := math.Abs(rq-q), math.Abs(rr-r), math.Abs(rs-s)
+	switch {
+	case dq > dr && dq > ds:
+		rq = -rr - rs
+	case dr > ds:
+		rr = -rq - rs
+	}
+	return Hex{Q: int(rq), R: int(rr)}
+}
+
+// Line - the hexes on a straight line from +a+ to +b+, both included
+func Line(a, b Hex) []Hex {
+	n := Distance(a, b)
+	ret := make([]Hex, 0, n+1)
+	// nudge off the edges so ties always break the same way
+	const nudge = 1e-6
+	aq, ar := float64(a.Q)+nudge, float64(a.R)+nudge
+	bq, br := float64(b.Q)+nudge, float64(b.R)+nudge
+	for i := 0; i <= n; i++ {
+		t := 0.0
+		if n > 0 {
+			t = float64(i) / float64(n)
+		}
+		ret = append(ret, Round(aq+(bq-aq)*t, ar+(br-ar)*t))
+	}
+	return ret
+}
@@ -0,0 +1,163 @@
+package hexgrid
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCube(t *testing.T) {
+	for _, h := range Spiral(Origin, 4) {
+		c := h.Cube()
+		if c.X+c.Y+c.Z != 0 {
+			t.Errorf("%s is %+v in cube coordinates, which don't add up to 0", h, c)
+		}
+		if c.Hex() != h {
+			t.Errorf("%s went to %+v and came back as %s", h, c, c.Hex())
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// the day 11 examples, on flat topped hexes
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"ne,ne,ne", 3},
+		{"ne,ne,sw,sw", 0},
+		{"ne,ne,s,s", 2},
+		{"se,sw,se,sw,sw", 3},
+	}
+	for _, test := range tests {
+		h := Origin
+		for _, step := range strings.Split(test.path, ",") {
+			var err error
+			if h, err = Flat.Move(h, step); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := Distance(Origin, h); got != test.want || h.Len() != test.want {
+			t.Errorf("%s: ended %d steps away (Len %d), want %d", test.path, got, h.Len(), test.want)
+		}
+		if got := Distance(h, Origin); got != test.want {
+			t.Errorf("%s: %d steps back, want %d", test.path, got, test.want)
+		}
+	}
+
+	if _, err := Flat.Move(Origin, "e"); err == nil {
+		t.Errorf("flat topped hexes have no east, but moving there worked")
+	}
+	if h, err := Pointy.Move(Origin, "e"); err != nil || h != Direction(1) {
+		t.Errorf("pointy east: got %s (%v), want %s", h, err, Direction(1))
+	}
+}
+
+// adjacent - is every hex in +hexes+ next to the one before it?
+func adjacent(hexes []Hex) bool {
+	for i := 1; i < len(hexes); i++ {
+		if Distance(hexes[i-1], hexes[i]) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRingAndSpiral(t *testing.T) {
+	center := Hex{Q: 2, R: -3}
+	if ring := Ring(center, 0); !slices.Equal(ring, []Hex{center}) {
+		t.Errorf("ring of radius 0: got %v, want just the centre", ring)
+	}
+	for radius := 1; radius <= 4; radius++ {
+		ring := Ring(center, radius)
+		if len(ring) != 6*radius {
+			t.Errorf("radius %d: %d hexes, want %d", radius, len(ring), 6*radius)
+		}
+		if start := center.Add(Direction(4).Scale(radius)); ring[0] != start {
+			t.Errorf("radius %d: starts at %s, want %s", radius, ring[0], start)
+		}
+		for _, h := range ring {
+			if Distance(center, h) != radius {
+				t.Errorf("radius %d: %s is %d away", radius, h, Distance(center, h))
+			}
+		}
+		// all the way round and back to the start
+		if !adjacent(append(ring, ring[0])) {
+			t.Errorf("radius %d: not a closed ring: %v", radius, ring)
+		}
+	}
+
+	spiral := Spiral(center, 3)
+	if want := 1 + 3*3*4; len(spiral) != want {
+		t.Errorf("spiral of radius 3: %d hexes, want %d", len(spiral), want)
+	}
+	seen := make(map[Hex]bool)
+	for i, h := range spiral {
+		if seen[h] {
+			t.Errorf("spiral has %s twice", h)
+		}
+		seen[h] = true
+		if i > 0 && Distance(center, h) < Distance(center, spiral[i-1]) {
+			t.Errorf("spiral goes back in from %s to %s", spiral[i-1], h)
+		}
+	}
+}
+
+func TestLine(t *testing.T) {
+	if got, want := Line(Origin, Hex{Q: 3, R: -1}), []Hex{{0, 0}, {1, 0}, {2, -1}, {3, -1}}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := Line(Hex{Q: 1, R: 1}, Hex{Q: 1, R: 1}); !slices.Equal(got, []Hex{{1, 1}}) {
+		t.Errorf("line to itself: got %v", got)
+	}
+	for _, a := range Spiral(Origin, 2) {
+		for _, b := range Ring(Hex{Q: 1, R: 2}, 3) {
+			line := Line(a, b)
+			if len(line) != Distance(a, b)+1 || line[0] != a || line[len(line)-1] != b || !adjacent(line) {
+				t.Errorf("line from %s to %s: %v", a, b, line)
+			}
+		}
+	}
+}
+
+func TestRotate(t *testing.T) {
+	for d := 0; d < 6; d++ {
+		if got := Direction(d).RotateRight(); got != Direction(d+1) {
+			t.Errorf("direction %d turned right: got %s, want %s", d, got, Direction(d+1))
+		}
+		if got := Direction(d).RotateLeft(); got != Direction(d-1) {
+			t.Errorf("direction %d turned left: got %s, want %s", d, got, Direction(d-1))
+		}
+	}
+	center := Hex{Q: 1, R: 0}
+	for _, h := range Spiral(center, 3) {
+		if got := h.Rotate(center, 6); got != h {
+			t.Errorf("%s turned all the way round: got %s", h, got)
+		}
+		if got := h.Rotate(center, -1); got != h.Rotate(center, 5) {
+			t.Errorf("%s: one step left is %s, five right %s", h, got, h.Rotate(center, 5))
+		}
+		if Distance(center, h.Rotate(center, 2)) != Distance(center, h) {
+			t.Errorf("%s moved nearer or further from the centre turning", h)
+		}
+	}
+	if got := (Hex{Q: 2, R: 0}).Rotate(center, 1); got != (Hex{Q: 1, R: 1}) {
+		t.Errorf("(2,0) about (1,0): got %s, want (1,1)", got)
+	}
+}
+
+func TestPixels(t *testing.T) {
+	for _, o := range []*Orientation{Flat, Pointy} {
+		for _, h := range Spiral(Origin, 3) {
+			x, y := o.ToPixel(h, 10)
+			if got := o.FromPixel(x, y, 10); got != h {
+				t.Errorf("%s: %s is at (%.1f, %.1f), which is in %s", o.Name, h, x, y, got)
+			}
+			// a corner is on the edge; just inside it is still h
+			corner := o.Corners(h, 10)[0]
+			if got := o.FromPixel(x+(corner[0]-x)*0.9, y+(corner[1]-y)*0.9, 10); got != h {
+				t.Errorf("%s: just inside the corner of %s is in %s", o.Name, h, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package hexgrid
+
+import (
+	"fmt"
+	"math"
+)
+
+// Orientation - which way up the hexes are drawn, and so what the
+// directions are called
+type Orientation struct {
+	Name  string
+	Names [6]string // direction names, clockwise from Direction(0)
+
+	// forward matrix, axial to pixel; see ToPixel
+	f0, f1, f2, f3 float64
+	// angle of the first corner, in sixths of a turn
+	startAngle float64
+}
+
+var (
+	// Flat - flat topped hexes in columns, as in 2017 day 11
+	Flat = &Orientation{
+		Name:       "flat",
+		Names:      [6]string{"ne", "se", "s", "sw", "nw", "n"},
+		f0:         3.0 / 2.0,
+		f1:         0,
+		f2:         math.Sqrt(3) / 2,
+		f3:         math.Sqrt(3),
+		startAngle: 0,
+	}
+	// Pointy - pointy topped hexes in rows
+	Pointy = &Orientation{
+		Name:       "pointy",
+		Names:      [6]string{"ne", "e", "se", "sw", "w", "nw"},
+		f0:         math.Sqrt(3),
+		f1:         math.Sqrt(3) / 2,
+		f2:         0,
+		f3:         3.0 / 2.0,
+		startAngle: 0.5,
+	}
+)
+
+// Direction - the number of the direction called +name+
+func (o *Orientation) Direction(name string) (int, error) {
+	for d, n := range o.Names {
+		if n == name {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unexpected direction %s for %s topped hexes, can't move there", name, o.Name)
+}
+
+// Move - the hex next to +h+ in the direction called +name+
+func (o *Orientation) Move(h Hex, name string) (Hex, error) {
+	d, err := o.Direction(name)
+	if err != nil {
+		return h, err
+	}
+	return h.Neighbour(d), nil
+}
+
+// ToPixel - the centre of +h+ when hexes have radius +size+, with y
+// increasing downwards
+func (o *Orientation) ToPixel(h Hex, size float64) (x, y float64) {
+	q, r := float64(h.Q), float64(h.R)
+	return (o.f0*q + o.f1*r) * size, (o.f2*q + o.f3*r) * size
+}
+
+// FromPixel - the hex containing the point (+x+, +y+)
+func (o *Orientation) FromPixel(x, y, size float64) Hex {
+	// invert the 2x2 forward matrix
+	det := o.f0*o.f3 - o.f1*o.f2
+	px, py := x/size, y/size
+	return Round((o.f3*px-o.f1*py)/det, (-o.f2*px+o.f0*py)/det)
+}
+
+// Corners - the six corners of +h+ around its ToPixel centre
+func (o *Orientation) Corners(h Hex, size float64) [6][2]float64 {
+	cx, cy := o.ToPixel(h, size)
+	var ret [6][2]float64
+	for i := range ret {
+		angle := 2 * math.Pi * (o.startAngle + float64(i)) / 6
+		ret[i] = [2]float64{cx + size*math.Cos(angle), cy + size*math.Sin(angle)}
+	}
+	return ret
+}
+
+// doubled - "doubled" coordinates for text layout: every hex lands on a
+// distinct (col, row) with neighbours at most two columns or rows apart
+func (o *Orientation) doubled(h Hex) (col, row int) {
+	if o == Pointy {
+		return 2*h.Q + h.R, h.R
+	}
+	return h.Q, 2*h.R + h.Q
+}
@@ -1,3 +1,6 @@
+// Package knot - ring based knot hash.
+//
+// Deprecated: use knothash, which day10 and day14 now share.
 package knot
 
 import (
@@ -17,7 +20,7 @@ type Hash struct {
 func (h *Hash) RawInputAsString() string {
 	str := ""
 	for i := 0; i < len(h.rawInput); i++ {
-		str += string(h.rawInput[i])
+		str += string(rune(h.rawInput[i]))
 	}
 	return str
 }
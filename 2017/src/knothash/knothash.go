@@ -0,0 +1,171 @@
+// Package knothash - the knot hash from 2017 day 10, used again by day 14.
+//
+// The sparse hash is the list left over after twisting; one round of it is
+// the day 10 part A puzzle. The dense hash is the full 64 round hash of a key
+// with the standard suffix, XOR-folded to 16 bytes; New returns it as a
+// hash.Hash.
+package knothash
+
+import (
+	"fmt"
+	"hash"
+	"runtime"
+	"sync"
+)
+
+const (
+	// Size - length of a dense hash in bytes
+	Size = 16
+	// BlockSize - the hash has to see the whole key before it can start, so
+	// there is no natural block size; writes of any length are fine
+	BlockSize = 1
+	// ListSize - elements in the list used for the dense hash
+	ListSize = 256
+	// Rounds - rounds used for the dense hash
+	Rounds = 64
+)
+
+// Suffix - appended to the key before computing the dense hash
+var Suffix = []byte{17, 31, 73, 47, 23}
+
+// Sparse - twist a list of +size+ elements (0 to size-1, in order) with
+// +lengths+, repeating the whole sequence +rounds+ times. The position and
+// skip size carry over between rounds. Returns the list.
+func Sparse(lengths []int, size, rounds int) ([]int, error) {
+	list := make([]int, size)
+	for i := range list {
+		list[i] = i
+	}
+	position, skip := 0, 0
+	for round := 0; round < rounds; round++ {
+		for _, length := range lengths {
+			if length < 0 || length > size {
+				return nil, fmt.Errorf("length %d doesn't fit in a list of %d", length, size)
+			}
+			reverse(list, position, length)
+			position = (position + length + skip) % size
+			skip++
+		}
+	}
+	return list, nil
+}
+
+// reverse - reverse +length+ elements of +list+ starting at +start+,
+// wrapping around the end
+func reverse(list []int, start, length int) {
+	size := len(list)
+	for i, j := start, start+length-1; i < j; i, j = i+1, j-1 {
+		list[i%size], list[j%size] = list[j%size], list[i%size]
+	}
+}
+
+// Dense - XOR each run of 16 elements of a sparse hash of ListSize elements
+// together
+func Dense(sparse []int) [Size]byte {
+	var ret [Size]byte
+	for i, v := range sparse {
+		ret[i/16] ^= byte(v)
+	}
+	return ret
+}
+
+// state - the list being twisted for a dense hash. ListSize is 256, so byte
+// arithmetic on the position wraps around the list for free.
+type state struct {
+	list           [ListSize]byte
+	position, skip byte
+}
+
+// twist - reverse +length+ elements from the current position in place,
+// then move on
+func (s *state) twist(length byte) {
+	for k := byte(0); k < length/2; k++ {
+		i, j := s.position+k, s.position+length-1-k
+		s.list[i], s.list[j] = s.list[j], s.list[i]
+	}
+	s.position += length + s.skip
+	s.skip++
+}
+
+// Sum - the dense hash of +key+. Twists a fixed size array in place rather
+// than building a list of lengths, so it doesn't allocate.
+func Sum(key []byte) [Size]byte {
+	var s state
+	for i := range s.list {
+		s.list[i] = byte(i)
+	}
+	for round := 0; round < Rounds; round++ {
+		for _, length := range key {
+			s.twist(length)
+		}
+		for _, length := range Suffix {
+			s.twist(length)
+		}
+	}
+
+	var ret [Size]byte
+	for i, v := range s.list {
+		ret[i/16] ^= v
+	}
+	return ret
+}
+
+// SumAll - the dense hash of every key in +keys+, in the same order, spread
+// over +workers+ goroutines (runtime.NumCPU() if < 1)
+func SumAll(keys [][]byte, workers int) [][Size]byte {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	ret := make([][Size]byte, len(keys))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				ret[i] = Sum(keys[i])
+			}
+		}()
+	}
+	for i := range keys {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return ret
+}
+
+// digest - hash.Hash around Sum. Writes collect the key.
+type digest struct {
+	key []byte
+}
+
+// New - a hash.Hash computing the dense knot hash of everything written to it
+func New() hash.Hash {
+	return &digest{key: make([]byte, 0)}
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	d.key = append(d.key, p...)
+	return len(p), nil
+}
+
+// Sum - append the dense hash of everything written so far to +b+. Doesn't
+// change the state, so more can be written afterwards.
+func (d *digest) Sum(b []byte) []byte {
+	sum := Sum(d.key)
+	return append(b, sum[:]...)
+}
+
+func (d *digest) Reset() {
+	d.key = d.key[:0]
+}
+
+func (d *digest) Size() int {
+	return Size
+}
+
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
@@ -0,0 +1,154 @@
+package knothash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"testing"
+
+	"knot"
+	"simpleknot"
+)
+
+// vectors - the published day 10 part B examples
+var vectors = []struct {
+	key, sum string
+}{
+	{"", "a2582a3a0e66e6e86e3812dcb672a272"},
+	{"AoC 2017", "33efeb34ea91902bb2f59c9920caa6cd"},
+	{"1,2,3", "3efbe78a8d82f29979031a4aa0b16a9d"},
+	{"1,2,4", "63960835bcdc130f0b66d7ff4f6a5a8e"},
+}
+
+func TestSum(t *testing.T) {
+	for _, v := range vectors {
+		sum := Sum([]byte(v.key))
+		if got := hex.EncodeToString(sum[:]); got != v.sum {
+			t.Errorf("Sum(%q) = %s, want %s", v.key, got, v.sum)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	for _, v := range vectors {
+		h := New()
+		// a byte at a time, to check writes accumulate
+		for i := range v.key {
+			h.Write([]byte{v.key[i]})
+		}
+		if got := hex.EncodeToString(h.Sum(nil)); got != v.sum {
+			t.Errorf("New() of %q = %s, want %s", v.key, got, v.sum)
+		}
+		h.Reset()
+		h.Write([]byte("AoC 2017"))
+		if got := hex.EncodeToString(h.Sum(nil)); got != vectors[1].sum {
+			t.Errorf("after Reset: %s, want %s", got, vectors[1].sum)
+		}
+	}
+}
+
+func TestSparse(t *testing.T) {
+	list, err := Sparse([]int{3, 4, 1, 5}, 5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{3, 4, 2, 1, 0}; !slices.Equal(list, want) {
+		t.Errorf("got %v, want %v", list, want)
+	}
+	if _, err := Sparse([]int{6}, 5, 1); err == nil {
+		t.Error("a length longer than the list should fail")
+	}
+}
+
+// day14Keys - the day 14 example's row keys
+func day14Keys() [][]byte {
+	keys := make([][]byte, 128)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("flqrgnkx-%d", i))
+	}
+	return keys
+}
+
+func TestSumAll(t *testing.T) {
+	keys := day14Keys()
+	for _, workers := range []int{0, 1, 3} {
+		sums := SumAll(keys, workers)
+		if len(sums) != len(keys) {
+			t.Fatalf("%d workers: %d sums for %d keys", workers, len(sums), len(keys))
+		}
+		for i, key := range keys {
+			if sums[i] != Sum(key) {
+				t.Errorf("%d workers: key %q: SumAll %x, Sum %x", workers, key, sums[i], Sum(key))
+			}
+		}
+	}
+}
+
+// knotSum - the dense hash of +key+ from the knot package, which twists a
+// container/ring
+func knotSum(key []byte) []byte {
+	ints := make([]int, len(key))
+	for i, c := range key {
+		ints[i] = int(c)
+	}
+	return knot.New(ints).ComputeDenseHash()
+}
+
+// simpleKnotSum - the dense hash of +key+ from the simpleknot package, which
+// makes a new slice for every reversal
+func simpleKnotSum(key []byte) []byte {
+	return simpleknot.New(append([]byte{}, key...)).ComputeDenseHash()
+}
+
+// the older implementations had better agree before their speed is compared.
+// knot takes a good part of a second a hash, so only a few keys.
+func TestImplementationsAgree(t *testing.T) {
+	keys := day14Keys()[:3]
+	for _, v := range vectors {
+		keys = append(keys, []byte(v.key))
+	}
+	for _, key := range keys {
+		want := Sum(key)
+		if got := knotSum(key); !bytes.Equal(got, want[:]) {
+			t.Errorf("%q: knot %x, want %x", key, got, want)
+		}
+		if got := simpleKnotSum(key); !bytes.Equal(got, want[:]) {
+			t.Errorf("%q: simpleknot %x, want %x", key, got, want)
+		}
+	}
+}
+
+func BenchmarkKnot(b *testing.B) {
+	keys := day14Keys()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		knotSum(keys[n%len(keys)])
+	}
+}
+
+func BenchmarkSimpleKnot(b *testing.B) {
+	keys := day14Keys()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		simpleKnotSum(keys[n%len(keys)])
+	}
+}
+
+func BenchmarkSum(b *testing.B) {
+	keys := day14Keys()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		Sum(keys[n%len(keys)])
+	}
+}
+
+// BenchmarkSumAll - a whole day 14 grid per op; compare with 128 times
+// BenchmarkSum
+func BenchmarkSumAll(b *testing.B) {
+	keys := day14Keys()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		SumAll(keys, 0)
+	}
+}
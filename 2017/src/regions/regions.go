@@ -0,0 +1,85 @@
+// Package regions - connected regions in a 2D grid of cells that are either
+// filled or not. Cells connect to the filled cells above, below, left and
+// right of them (not diagonally).
+package regions
+
+import (
+	"unionfind"
+)
+
+// Labelling - the regions found in a grid
+type Labelling struct {
+	Width, Height int
+	Labels        [][]int // [y][x], 0 for an empty cell, otherwise 1..Count()
+	Sizes         []int   // cells in each region by label; Sizes[0] is the empty cells
+}
+
+// Count - how many regions there are
+func (l *Labelling) Count() int {
+	return len(l.Sizes) - 1
+}
+
+// Label - find the regions in a +width+ x +height+ grid. Classic two pass
+// labelling: the first pass gives each filled cell its own set and joins it
+// to the filled cells above and to its left, the second numbers the sets in
+// the order their first cell is reached (left to right, top to bottom).
+func Label(width, height int, filled func(x, y int) bool) *Labelling {
+	sets := unionfind.New()
+	id := func(x, y int) int { return y*width + x }
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if !filled(x, y) {
+				continue
+			}
+			sets.Add(id(x, y))
+			if x > 0 && filled(x-1, y) {
+				sets.Union(id(x, y), id(x-1, y))
+			}
+			if y > 0 && filled(x, y-1) {
+				sets.Union(id(x, y), id(x, y-1))
+			}
+		}
+	}
+
+	l := &Labelling{
+		Width:  width,
+		Height: height,
+		Labels: make([][]int, height),
+		Sizes:  []int{0},
+	}
+	label := make(map[int]int) // set representative -> label
+	for y := 0; y < height; y++ {
+		l.Labels[y] = make([]int, width)
+		for x := 0; x < width; x++ {
+			if !filled(x, y) {
+				l.Sizes[0]++
+				continue
+			}
+			root := sets.Find(id(x, y))
+			n, ok := label[root]
+			if !ok {
+				n = len(l.Sizes)
+				label[root] = n
+				l.Sizes = append(l.Sizes, 0)
+			}
+			l.Labels[y][x] = n
+			l.Sizes[n]++
+		}
+	}
+	return l
+}
+
+// Largest - the label and size of the biggest region, (0, 0) if there are
+// none
+func (l *Labelling) Largest() (int, int) {
+	best := 0
+	for n := 1; n < len(l.Sizes); n++ {
+		if best == 0 || l.Sizes[n] > l.Sizes[best] {
+			best = n
+		}
+	}
+	if best == 0 {
+		return 0, 0
+	}
+	return best, l.Sizes[best]
+}
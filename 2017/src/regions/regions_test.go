@@ -0,0 +1,85 @@
+package regions
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"knothash"
+)
+
+// parse - a grid drawn with # for filled cells
+func parse(text string) (int, int, func(x, y int) bool) {
+	rows := strings.Split(strings.TrimSpace(text), "\n")
+	return len(rows[0]), len(rows), func(x, y int) bool { return rows[y][x] == '#' }
+}
+
+func TestLabel(t *testing.T) {
+	// the cell in the middle of the top right touches three others, but only
+	// at the corners, so it stays on its own
+	width, height, filled := parse(`
+##..#
+#..#.
+..#.#
+###.#
+`)
+	l := Label(width, height, filled)
+	want := [][]int{
+		{1, 1, 0, 0, 2},
+		{1, 0, 0, 3, 0},
+		{0, 0, 4, 0, 5},
+		{4, 4, 4, 0, 5},
+	}
+	if !reflect.DeepEqual(l.Labels, want) {
+		t.Errorf("labels %v, want %v", l.Labels, want)
+	}
+	if want := []int{9, 3, 1, 1, 4, 2}; !reflect.DeepEqual(l.Sizes, want) {
+		t.Errorf("sizes %v, want %v", l.Sizes, want)
+	}
+	if l.Count() != 5 {
+		t.Errorf("%d regions, want 5", l.Count())
+	}
+	if label, size := l.Largest(); label != 4 || size != 4 {
+		t.Errorf("largest %d of %d cells, want 4 of 4", label, size)
+	}
+}
+
+func TestLabelJoins(t *testing.T) {
+	// a U only joins up on the bottom row, after both arms have been labelled
+	width, height, filled := parse(`
+#.#.#
+#.#.#
+###.#
+`)
+	l := Label(width, height, filled)
+	if l.Count() != 2 || l.Labels[0][0] != l.Labels[0][2] {
+		t.Errorf("labels %v, want the U as one region", l.Labels)
+	}
+	if label, size := l.Largest(); label != 1 || size != 7 {
+		t.Errorf("largest %d of %d cells, want 1 of 7", label, size)
+	}
+
+	empty := Label(3, 2, func(x, y int) bool { return false })
+	if label, size := empty.Largest(); empty.Count() != 0 || label != 0 || size != 0 || empty.Sizes[0] != 6 {
+		t.Errorf("empty grid: %d regions, largest %d of %d", empty.Count(), label, size)
+	}
+}
+
+func TestLabelDisk(t *testing.T) {
+	// the day 14 example
+	var used [128][128]bool
+	for y := range used {
+		hash := knothash.Sum([]byte(fmt.Sprintf("flqrgnkx-%d", y)))
+		for x := range used[y] {
+			used[y][x] = hash[x/8]&(0x80>>uint(x%8)) != 0
+		}
+	}
+	l := Label(128, 128, func(x, y int) bool { return used[y][x] })
+	if l.Count() != 1242 {
+		t.Errorf("%d regions, want 1242", l.Count())
+	}
+	if filled := 128*128 - l.Sizes[0]; filled != 8108 {
+		t.Errorf("%d used squares, want 8108", filled)
+	}
+}
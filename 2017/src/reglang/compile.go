@@ -0,0 +1,187 @@
+package reglang
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrStepLimit - Run gave up before the program finished
+var ErrStepLimit = errors.New("step limit reached")
+
+// instruction - a compiled statement. Returns how far to move the
+// instruction pointer.
+type instruction func(r []int) int
+
+// Program - compiled statements. Registers are numbered in the order they
+// first appear.
+type Program struct {
+	Statements []*Statement
+	Registers  []string // register names by number
+	Targets    []int    // the register each statement may change, by statement
+
+	slots map[string]int
+	code  []instruction
+}
+
+// Load - parse and compile +text+
+func Load(text string, registry *Registry) (*Program, error) {
+	statements, err := Parse(text, registry)
+	if err != nil {
+		return nil, err
+	}
+	return Compile(statements), nil
+}
+
+// Compile - turn statements into closures over a register slice, so running
+// them doesn't look anything up by name
+func Compile(statements []*Statement) *Program {
+	p := &Program{
+		Statements: statements,
+		Registers:  make([]string, 0),
+		Targets:    make([]int, len(statements)),
+		slots:      make(map[string]int),
+		code:       make([]instruction, len(statements)),
+	}
+	for i, s := range statements {
+		p.Targets[i] = p.slot(s.Register)
+		p.code[i] = p.compile(s)
+	}
+	return p
+}
+
+// slot - the number of register +name+, allocating one if it's new
+func (p *Program) slot(name string) int {
+	if n, ok := p.slots[name]; ok {
+		return n
+	}
+	p.slots[name] = len(p.Registers)
+	p.Registers = append(p.Registers, name)
+	return len(p.Registers) - 1
+}
+
+// Slot - the number of register +name+, if the program uses it
+func (p *Program) Slot(name string) (int, bool) {
+	n, ok := p.slots[name]
+	return n, ok
+}
+
+// operand - a closure reading +o+
+func (p *Program) operand(o Operand) func(r []int) int {
+	if o.Register == "" {
+		v := o.Value
+		return func(r []int) int { return v }
+	}
+	n := p.slot(o.Register)
+	return func(r []int) int { return r[n] }
+}
+
+// condition - a closure testing +c+. Register against literal is by far the
+// commonest form, so it gets a closure of its own.
+func (p *Program) condition(c *Condition) func(r []int) bool {
+	compare := c.Compare
+	if c.Left.Register != "" && c.Right.Register == "" {
+		n, v := p.slot(c.Left.Register), c.Right.Value
+		return func(r []int) bool { return compare(r[n], v) }
+	}
+	left, right := p.operand(c.Left), p.operand(c.Right)
+	return func(r []int) bool { return compare(left(r), right(r)) }
+}
+
+// compile - the closure for one statement
+func (p *Program) compile(s *Statement) instruction {
+	target := p.slot(s.Register)
+	operand := p.operand(s.Operand)
+	cond := func(r []int) bool { return true }
+	if s.Condition != nil {
+		cond = p.condition(s.Condition)
+	}
+	if jump := s.Operation.Jump; jump != nil {
+		return func(r []int) int {
+			if cond(r) {
+				return jump(r[target], operand(r))
+			}
+			return 1
+		}
+	}
+	apply := s.Operation.Apply
+	return func(r []int) int {
+		if cond(r) {
+			r[target] = apply(r[target], operand(r))
+		}
+		return 1
+	}
+}
+
+// Machine - a Program being run
+type Machine struct {
+	Program   *Program
+	Registers []int // values by register number
+	IP        int   // index of the next statement
+	Steps     int   // statements executed so far
+
+	History *History // if set, every register change is logged here, see Record
+}
+
+// NewMachine - a machine about to run +p+ with every register at 0
+func NewMachine(p *Program) *Machine {
+	return &Machine{Program: p, Registers: make([]int, len(p.Registers))}
+}
+
+// Done - has the instruction pointer left the program?
+func (m *Machine) Done() bool {
+	return m.IP < 0 || m.IP >= len(m.Program.code)
+}
+
+// Step - execute the statement at IP. Returns its index.
+func (m *Machine) Step() int {
+	executed := m.IP
+	if m.History == nil {
+		m.IP += m.Program.code[executed](m.Registers)
+		m.Steps++
+		return executed
+	}
+	target := m.Program.Targets[executed]
+	old := m.Registers[target]
+	m.IP += m.Program.code[executed](m.Registers)
+	m.Steps++
+	m.History.record(m.Steps, executed, target, old, m.Registers[target])
+	return executed
+}
+
+// Run - step until the program ends, or until +limit+ statements have been
+// executed in total if +limit+ > 0
+func (m *Machine) Run(limit int) error {
+	if m.History != nil {
+		for !m.Done() {
+			if limit > 0 && m.Steps >= limit {
+				return ErrStepLimit
+			}
+			m.Step()
+		}
+		return nil
+	}
+	code, r := m.Program.code, m.Registers
+	for m.IP >= 0 && m.IP < len(code) {
+		if limit > 0 && m.Steps >= limit {
+			return ErrStepLimit
+		}
+		m.IP += code[m.IP](r)
+		m.Steps++
+	}
+	return nil
+}
+
+// Value - the value of register +name+ (0 for one the program never uses)
+func (m *Machine) Value(name string) int {
+	if n, ok := m.Program.Slot(name); ok {
+		return m.Registers[n]
+	}
+	return 0
+}
+
+// Names - every register name, sorted
+func (m *Machine) Names() []string {
+	ret := append([]string{}, m.Program.Registers...)
+	sort.Strings(ret)
+	return ret
+}
@@ -0,0 +1,90 @@
+package reglang
+
+import (
+	"errors"
+	"testing"
+)
+
+// runBoth - +text+ compiled with +registry+ and run up to +limit+ steps,
+// once plain and once recording History, which take different paths through
+// Run. Fails the test if they end differently.
+func runBoth(t *testing.T, text string, registry *Registry, limit int) (*Machine, error) {
+	t.Helper()
+	program, err := Load(text, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := NewMachine(program)
+	plainErr := plain.Run(limit)
+	recorded := NewMachine(program)
+	recorded.Record()
+	recordedErr := recorded.Run(limit)
+	for _, name := range plain.Names() {
+		if plain.Value(name) != recorded.Value(name) {
+			t.Errorf("%q: %s is %d, but %d with History", text, name, plain.Value(name), recorded.Value(name))
+		}
+	}
+	if plain.Steps != recorded.Steps || plainErr != recordedErr {
+		t.Errorf("%q: %d steps (%v), but %d (%v) with History", text, plain.Steps, plainErr, recorded.Steps, recordedErr)
+	}
+	return plain, plainErr
+}
+
+func TestOperations(t *testing.T) {
+	tests := []struct {
+		name, program string
+		want          map[string]int
+	}{
+		{"mul and set", "a set 3\na mul 4\nb set a if a == 12\nb mul -1\nc set b if a != 12", map[string]int{"a": 12, "b": -12, "c": 0}},
+		{"factorial", "n set 5\nf set 1\nf mul n\nn dec 1\nn jmp -2", map[string]int{"n": 0, "f": 120}},
+		{"jmp over", "x inc 1\nx jmp 2 if y == 0\nz inc 100\nw inc 1", map[string]int{"x": 1, "z": 0, "w": 1}},
+		{"jmp not taken", "x jmp 2\nz inc 100\ny inc 1\nx inc 1\nx jmp 2 if y == 0\nw inc 7", map[string]int{"z": 100, "w": 7}},
+		{"jmp off the start", "a inc 1\na jmp -5\na inc 1", map[string]int{"a": 1}},
+	}
+	for _, test := range tests {
+		m, err := runBoth(t, test.program, NewRegistry(), 1000)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		for name, want := range test.want {
+			if got := m.Value(name); got != want {
+				t.Errorf("%s: %s is %d, want %d", test.name, name, got, want)
+			}
+		}
+	}
+}
+
+func TestStepLimit(t *testing.T) {
+	m, err := runBoth(t, "a inc 1\na jmp -1", NewRegistry(), 100)
+	if !errors.Is(err, ErrStepLimit) {
+		t.Fatalf("got %v, want %v", err, ErrStepLimit)
+	}
+	if m.Steps != 100 || m.Value("a") != 50 {
+		t.Errorf("stopped after %d steps with a at %d, want 100 and 50", m.Steps, m.Value("a"))
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	const program = "a set 17\na mod 5\nb inc 1 if a <> 3"
+	if _, err := Load(program, NewRegistry()); err == nil {
+		t.Fatalf("%q loaded without mod registered", program)
+	}
+
+	r := NewRegistry()
+	r.Register(&Operation{Name: "mod", Apply: func(current, operand int) int { return current % operand }})
+	r.RegisterComparison("<>", func(a, b int) bool { return a != b })
+	m, err := runBoth(t, program, r, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Value("a") != 2 || m.Value("b") != 1 {
+		t.Errorf("a is %d and b %d, want 2 and 1", m.Value("a"), m.Value("b"))
+	}
+
+	// replacing an operation changes what existing programs do
+	r.Register(&Operation{Name: "inc", Apply: func(current, operand int) int { return current + 2*operand }})
+	if m, _ = runBoth(t, "a inc 5", r, 0); m.Value("a") != 10 {
+		t.Errorf("doubled inc: a is %d, want 10", m.Value("a"))
+	}
+}
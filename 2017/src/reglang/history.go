@@ -0,0 +1,147 @@
+package reglang
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Change - one register changing value
+type Change struct {
+	Step      int // 1 based count of statements executed, including this one
+	Statement int // index of the statement that made the change
+	Register  int // register number
+	Old, New  int
+}
+
+// History - every change a Machine made to its registers, in order. Attach
+// one with Machine.Record before running.
+type History struct {
+	Program *Program
+	Changes []Change
+
+	byRegister [][]int // indexes into Changes, by register number
+}
+
+// Record - start logging every register change to a new History
+func (m *Machine) Record() *History {
+	m.History = &History{
+		Program:    m.Program,
+		Changes:    make([]Change, 0),
+		byRegister: make([][]int, len(m.Program.Registers)),
+	}
+	return m.History
+}
+
+// record - log a statement's effect, if it had one
+func (h *History) record(step, statement, register, old, new int) {
+	if old == new {
+		return
+	}
+	h.byRegister[register] = append(h.byRegister[register], len(h.Changes))
+	h.Changes = append(h.Changes, Change{Step: step, Statement: statement, Register: register, Old: old, New: new})
+}
+
+// register - the number of register +name+
+func (h *History) register(name string) (int, error) {
+	n, ok := h.Program.Slot(name)
+	if !ok {
+		return 0, fmt.Errorf("the program has no register %q", name)
+	}
+	return n, nil
+}
+
+// ValueAfter - the value register +name+ held once +step+ statements had
+// been executed (step 0 is before the first). Without jumps, step N is the
+// statement on line N.
+func (h *History) ValueAfter(name string, step int) (int, error) {
+	n, err := h.register(name)
+	if err != nil {
+		return 0, err
+	}
+	changes := h.byRegister[n]
+	// the first change after +step+, and so the last one at or before it
+	k := sort.Search(len(changes), func(i int) bool { return h.Changes[changes[i]].Step > step })
+	if k == 0 {
+		return 0, nil
+	}
+	return h.Changes[changes[k-1]].New, nil
+}
+
+// AllTimeMax - the change that first set the highest value any register
+// ever held. Registers start at 0, so a change only counts if it went above
+// that; false if none did, when the highest value ever held is 0.
+func (h *History) AllTimeMax() (Change, bool) {
+	best := -1
+	for i, c := range h.Changes {
+		if c.New > 0 && (best < 0 || c.New > h.Changes[best].New) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return Change{}, false
+	}
+	return h.Changes[best], true
+}
+
+// HighestEver - the highest value any register held, including the 0 they
+// all start at
+func (h *History) HighestEver() int {
+	if c, ok := h.AllTimeMax(); ok {
+		return c.New
+	}
+	return 0
+}
+
+// First - the first change to register +name+ that left it with a value
+// satisfying +test+. False if there wasn't one.
+func (h *History) First(name string, test func(value int) bool) (Change, bool, error) {
+	n, err := h.register(name)
+	if err != nil {
+		return Change{}, false, err
+	}
+	for _, i := range h.byRegister[n] {
+		if test(h.Changes[i].New) {
+			return h.Changes[i], true, nil
+		}
+	}
+	return Change{}, false, nil
+}
+
+// FirstNegative - the first time register +name+ went below zero
+func (h *History) FirstNegative(name string) (Change, bool, error) {
+	return h.First(name, func(value int) bool { return value < 0 })
+}
+
+// Describe - a change as "step 12, line 4 (c dec -10 if a >= 1): c 0 -> 10"
+func (h *History) Describe(c Change) string {
+	s := h.Program.Statements[c.Statement]
+	return fmt.Sprintf("step %d, line %d (%s): %s %d -> %d", c.Step, s.Line, s, h.Program.Registers[c.Register], c.Old, c.New)
+}
+
+// WriteCSV - write every change as step,line,statement,register,old,new
+// with a header row
+func (h *History) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"step", "line", "statement", "register", "old", "new"}); err != nil {
+		return err
+	}
+	for _, c := range h.Changes {
+		s := h.Program.Statements[c.Statement]
+		record := []string{
+			strconv.Itoa(c.Step),
+			strconv.Itoa(s.Line),
+			s.String(),
+			h.Program.Registers[c.Register],
+			strconv.Itoa(c.Old),
+			strconv.Itoa(c.New),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,64 @@
+package reglang
+
+import "testing"
+
+// run - +text+ run to the end with its History recorded
+func run(t *testing.T, text string) *History {
+	t.Helper()
+	program, err := Load(text, NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMachine(program)
+	h := m.Record()
+	if err := m.Run(0); err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+const example = `b inc 5 if a > 1
+a inc 1 if b < 5
+c dec -10 if a >= 1
+c inc -20 if c == 10
+`
+
+func TestAllTimeMax(t *testing.T) {
+	tests := []struct {
+		name, program string
+		highest       int
+		changed       bool // whether any change went above 0
+	}{
+		{"day 8 example", example, 10, true},
+		{"only negative values", "a dec 5 if b == 0\nb inc 1 if a > 100\n", 0, false},
+		{"no writes", "a inc 1 if b > 0\nb dec 2 if a != 0\n", 0, false},
+		{"up then down", "a inc 3 if a == 0\na dec 10 if a > 0\nb inc 2 if a < 0\n", 3, true},
+	}
+	for _, test := range tests {
+		h := run(t, test.program)
+		if got := h.HighestEver(); got != test.highest {
+			t.Errorf("%s: HighestEver = %d, want %d", test.name, got, test.highest)
+		}
+		c, ok := h.AllTimeMax()
+		if ok != test.changed {
+			t.Errorf("%s: AllTimeMax found a change: %t, want %t", test.name, ok, test.changed)
+		} else if ok && c.New != test.highest {
+			t.Errorf("%s: AllTimeMax = %s", test.name, h.Describe(c))
+		}
+	}
+}
+
+func TestValueAfter(t *testing.T) {
+	h := run(t, example)
+	for step, want := range []int{0, 0, 0, 10, -10} {
+		if got, err := h.ValueAfter("c", step); err != nil || got != want {
+			t.Errorf("c after step %d = %d, %v; want %d", step, got, err, want)
+		}
+	}
+	if _, err := h.ValueAfter("zz", 1); err == nil {
+		t.Error("no error for a register the program doesn't have")
+	}
+	if c, ok, err := h.FirstNegative("c"); err != nil || !ok || c.Step != 4 {
+		t.Errorf("FirstNegative(c) = %+v, %t, %v", c, ok, err)
+	}
+}
@@ -0,0 +1,121 @@
+// Package reglang - the register language from 2017 day 8.
+//
+// Each line changes one register, optionally only if a condition holds:
+//
+//	b inc 5 if a > 1
+//	c dec -10 if a >= 1
+//
+// Operations and comparisons come from a Registry, so the language can grow
+// beyond inc and dec (NewRegistry also knows mul, set and jmp). Programs are
+// tokenized, parsed into Statements and compiled into closures over a slice
+// of registers, which is what a Machine runs.
+package reglang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TokenKind - what sort of token a Token is
+type TokenKind int
+
+const (
+	Ident      TokenKind = iota // register or operation name, or "if"
+	Number                      // integer literal, optionally signed
+	Comparison                  // run of < > = !
+	Newline                     // end of a line
+	EOF                         // end of the input
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case Ident:
+		return "name"
+	case Number:
+		return "number"
+	case Comparison:
+		return "comparison"
+	case Newline:
+		return "end of line"
+	case EOF:
+		return "end of input"
+	}
+	return fmt.Sprintf("TokenKind(%d)", int(k))
+}
+
+// Token - one token and where it starts (both 1 based)
+type Token struct {
+	Kind      TokenKind
+	Text      string
+	Line, Col int
+}
+
+func (t Token) String() string {
+	if t.Kind == Newline || t.Kind == EOF {
+		return t.Kind.String()
+	}
+	return fmt.Sprintf("%q", t.Text)
+}
+
+// Error - a problem with the program text at a line and column
+type Error struct {
+	Line, Col int
+	Msg       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Col, e.Msg)
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Lex - split +text+ into tokens. '#' starts a comment running to the end of
+// the line. The last token is always EOF.
+func Lex(text string) ([]Token, error) {
+	tokens := make([]Token, 0)
+	line, col := 1, 1
+	for i := 0; i < len(text); {
+		c := text[i]
+		start := i
+		switch {
+		case c == '\n':
+			tokens = append(tokens, Token{Kind: Newline, Text: "\n", Line: line, Col: col})
+			i++
+			line, col = line+1, 1
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case isLetter(c):
+			for i < len(text) && (isLetter(text[i]) || isDigit(text[i])) {
+				i++
+			}
+			tokens = append(tokens, Token{Kind: Ident, Text: text[start:i], Line: line, Col: col})
+		case isDigit(c) || ((c == '-' || c == '+') && i+1 < len(text) && isDigit(text[i+1])):
+			i++
+			for i < len(text) && isDigit(text[i]) {
+				i++
+			}
+			tokens = append(tokens, Token{Kind: Number, Text: text[start:i], Line: line, Col: col})
+		case strings.IndexByte("<>=!", c) >= 0:
+			for i < len(text) && strings.IndexByte("<>=!", text[i]) >= 0 {
+				i++
+			}
+			tokens = append(tokens, Token{Kind: Comparison, Text: text[start:i], Line: line, Col: col})
+		default:
+			return nil, &Error{Line: line, Col: col, Msg: fmt.Sprintf("unexpected character %q", c)}
+		}
+		col += i - start
+	}
+	tokens = append(tokens, Token{Kind: EOF, Line: line, Col: col})
+	return tokens, nil
+}
@@ -0,0 +1,53 @@
+package reglang
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	tokens, err := Lex("b inc -5 if a >= 1 # comment\n\tc dec +2\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Token{
+		{Ident, "b", 1, 1},
+		{Ident, "inc", 1, 3},
+		{Number, "-5", 1, 7},
+		{Ident, "if", 1, 10},
+		{Ident, "a", 1, 13},
+		{Comparison, ">=", 1, 15},
+		{Number, "1", 1, 18},
+		{Newline, "\n", 1, 29},
+		{Ident, "c", 2, 2},
+		{Ident, "dec", 2, 4},
+		{Number, "+2", 2, 8},
+		{Newline, "\n", 2, 10},
+		{EOF, "", 3, 1},
+	}
+	if !slices.Equal(tokens, want) {
+		t.Errorf("got %v\nwant %v", tokens, want)
+	}
+}
+
+func TestLexErrors(t *testing.T) {
+	tests := []struct {
+		text      string
+		line, col int
+		msg       string
+	}{
+		{"a inc 1 if b > 2;", 1, 17, `unexpected character ';'`},
+		{"a inc 1\n  b @", 2, 5, `unexpected character '@'`},
+		{"a inc - 1", 1, 7, `unexpected character '-'`},
+	}
+	for _, test := range tests {
+		_, err := Lex(test.text)
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Errorf("%q: got %v, want an Error", test.text, err)
+		} else if e.Line != test.line || e.Col != test.col || e.Msg != test.msg {
+			t.Errorf("%q: got %s, want line %d, column %d: %s", test.text, err, test.line, test.col, test.msg)
+		}
+	}
+}
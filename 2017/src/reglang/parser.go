@@ -0,0 +1,177 @@
+package reglang
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Operand - a register name or a literal value
+type Operand struct {
+	Register string // empty for a literal
+	Value    int
+}
+
+func (o Operand) String() string {
+	if o.Register != "" {
+		return o.Register
+	}
+	return strconv.Itoa(o.Value)
+}
+
+// Condition - the "if" clause of a statement
+type Condition struct {
+	Left, Right Operand
+	Symbol      string
+	Compare     Compare
+}
+
+func (c *Condition) String() string {
+	return fmt.Sprintf("%s %s %s", c.Left, c.Symbol, c.Right)
+}
+
+// Statement - one parsed line: apply Operation to Register with Operand if
+// Condition (nil for always) holds
+type Statement struct {
+	Register  string
+	Operation *Operation
+	Operand   Operand
+	Condition *Condition
+
+	Line, Col int // where the statement starts
+}
+
+func (s *Statement) String() string {
+	ret := fmt.Sprintf("%s %s %s", s.Register, s.Operation.Name, s.Operand)
+	if s.Condition != nil {
+		ret += " if " + s.Condition.String()
+	}
+	return ret
+}
+
+// parser - reads statements from a token list
+type parser struct {
+	tokens   []Token
+	pos      int
+	registry *Registry
+}
+
+func (p *parser) peek() Token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() Token {
+	t := p.tokens[p.pos]
+	if t.Kind != EOF {
+		p.pos++
+	}
+	return t
+}
+
+func errorAt(t Token, format string, args ...interface{}) error {
+	return &Error{Line: t.Line, Col: t.Col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// expect - the next token, which must be of kind +kind+; +what+ describes it
+// for the error message
+func (p *parser) expect(kind TokenKind, what string) (Token, error) {
+	t := p.next()
+	if t.Kind != kind {
+		return t, errorAt(t, "expected %s, got %s", what, t)
+	}
+	return t, nil
+}
+
+// operand - a register name or a number
+func (p *parser) operand(after string) (Operand, error) {
+	t := p.next()
+	switch t.Kind {
+	case Number:
+		v, err := strconv.Atoi(t.Text)
+		if err != nil {
+			return Operand{}, errorAt(t, "%s is out of range", t)
+		}
+		return Operand{Value: v}, nil
+	case Ident:
+		if t.Text == "if" {
+			break
+		}
+		return Operand{Register: t.Text}, nil
+	}
+	return Operand{}, errorAt(t, "expected a number or register after %s, got %s", after, t)
+}
+
+// statement - register operation operand [if operand comparison operand]
+func (p *parser) statement() (*Statement, error) {
+	target, err := p.expect(Ident, "a register name")
+	if err != nil {
+		return nil, err
+	}
+	if target.Text == "if" {
+		return nil, errorAt(target, "expected a register name, got %s", target)
+	}
+	s := &Statement{Register: target.Text, Line: target.Line, Col: target.Col}
+
+	opToken, err := p.expect(Ident, "an operation")
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.registry.Operation(opToken.Text)
+	if !ok {
+		return nil, errorAt(opToken, "unknown operation %s (expected one of %s)", opToken,
+			strings.Join(p.registry.operationNames(), ", "))
+	}
+	s.Operation = op
+	if s.Operand, err = p.operand(fmt.Sprintf("%q", op.Name)); err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.Kind == Ident && t.Text == "if" {
+		p.next()
+		c := &Condition{}
+		if c.Left, err = p.operand(`"if"`); err != nil {
+			return nil, err
+		}
+		symbol, err := p.expect(Comparison, "a comparison")
+		if err != nil {
+			return nil, err
+		}
+		if c.Compare, ok = p.registry.Comparison(symbol.Text); !ok {
+			return nil, errorAt(symbol, "unknown comparison %s (expected one of %s)", symbol,
+				strings.Join(p.registry.comparisonNames(), " "))
+		}
+		c.Symbol = symbol.Text
+		if c.Right, err = p.operand(symbol.String()); err != nil {
+			return nil, err
+		}
+		s.Condition = c
+	}
+
+	if t := p.next(); t.Kind != Newline && t.Kind != EOF {
+		return nil, errorAt(t, "expected end of line after %q, got %s", s, t)
+	}
+	return s, nil
+}
+
+// Parse - parse +text+ into statements using the operations and comparisons
+// in +registry+. Blank lines and comments are skipped.
+func Parse(text string, registry *Registry) ([]*Statement, error) {
+	tokens, err := Lex(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, registry: registry}
+	statements := make([]*Statement, 0)
+	for p.peek().Kind != EOF {
+		if p.peek().Kind == Newline {
+			p.next()
+			continue
+		}
+		s, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, s)
+	}
+	return statements, nil
+}
@@ -0,0 +1,66 @@
+package reglang
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	statements, err := Parse("# the example\nb inc 5 if a > 1\n\na inc 1 if b < 5\nc dec -10\nc set x if 1 != d\n", NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		text      string
+		line, col int
+	}{
+		{"b inc 5 if a > 1", 2, 1},
+		{"a inc 1 if b < 5", 4, 1},
+		{"c dec -10", 5, 1},
+		{"c set x if 1 != d", 6, 1},
+	}
+	if len(statements) != len(want) {
+		t.Fatalf("got %d statements, want %d: %v", len(statements), len(want), statements)
+	}
+	for i, s := range statements {
+		if s.String() != want[i].text || s.Line != want[i].line || s.Col != want[i].col {
+			t.Errorf("statement %d: got %q at %d:%d, want %q at %d:%d", i, s, s.Line, s.Col, want[i].text, want[i].line, want[i].col)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		text      string
+		line, col int
+		msg       string
+	}{
+		{"a foo 1", 1, 3, `unknown operation "foo" (expected one of dec, inc, jmp, mul, set)`},
+		{"a inc 1 if b <> 2", 1, 14, `unknown comparison "<>" (expected one of != < <= == > >=)`},
+		{"\n\nif inc 1", 3, 1, `expected a register name, got "if"`},
+		{"5 inc 1", 1, 1, `expected a register name, got "5"`},
+		{"a inc if b > 1", 1, 7, `expected a number or register after "inc", got "if"`},
+		{"a inc 99999999999999999999", 1, 7, `"99999999999999999999" is out of range`},
+		{"a inc 1 2", 1, 9, `expected end of line after "a inc 1", got "2"`},
+		{"a inc 1 if b > 1 else", 1, 18, `expected end of line after "a inc 1 if b > 1", got "else"`},
+		// cut short
+		{"a", 1, 2, `expected an operation, got end of input`},
+		{"a inc", 1, 6, `expected a number or register after "inc", got end of input`},
+		{"a inc 1 if", 1, 11, `expected a number or register after "if", got end of input`},
+		{"a inc 1 if b", 1, 13, `expected a comparison, got end of input`},
+		{"a inc 1 if b >", 1, 15, `expected a number or register after ">", got end of input`},
+		{"a inc 1\nb dec\nc inc 2", 2, 6, `expected a number or register after "dec", got end of line`},
+		{"a inc 1 if b >= \nc inc 2", 1, 17, `expected a number or register after ">=", got end of line`},
+		// the lexer's errors come through as they are
+		{"a inc 1 if b > 2;", 1, 17, `unexpected character ';'`},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.text, NewRegistry())
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Errorf("%q: got %v, want an Error", test.text, err)
+		} else if e.Line != test.line || e.Col != test.col || e.Msg != test.msg {
+			t.Errorf("%q: got %s, want line %d, column %d: %s", test.text, err, test.line, test.col, test.msg)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package reglang
+
+import (
+	"sort"
+)
+
+// Operation - something a statement can do to its register
+type Operation struct {
+	Name string
+	// Apply - the register's new value given its current value and the
+	// operand. Unused if Jump is set.
+	Apply func(current, operand int) int
+	// Jump - if set, the operation leaves the register alone and moves the
+	// instruction pointer by the returned offset instead (1 carries on to the
+	// next statement)
+	Jump func(current, operand int) int
+}
+
+// Compare - a comparison usable in an "if" clause
+type Compare func(a, b int) bool
+
+// Registry - the operations and comparisons a program may use
+type Registry struct {
+	operations  map[string]*Operation
+	comparisons map[string]Compare
+}
+
+// Operations beyond the original inc and dec
+var (
+	// Mul - multiply the register by the operand
+	Mul = &Operation{Name: "mul", Apply: func(current, operand int) int { return current * operand }}
+	// Set - set the register to the operand
+	Set = &Operation{Name: "set", Apply: func(current, operand int) int { return operand }}
+	// Jmp - jump by the operand if the register isn't zero
+	Jmp = &Operation{Name: "jmp", Jump: func(current, operand int) int {
+		if current != 0 {
+			return operand
+		}
+		return 1
+	}}
+)
+
+// NewRegistry - a registry with inc and dec, mul, set and jmp, and the six
+// comparisons from the puzzle
+func NewRegistry() *Registry {
+	r := &Registry{
+		operations:  make(map[string]*Operation),
+		comparisons: make(map[string]Compare),
+	}
+	r.Register(&Operation{Name: "inc", Apply: func(current, operand int) int { return current + operand }})
+	r.Register(&Operation{Name: "dec", Apply: func(current, operand int) int { return current - operand }})
+	r.Register(Mul)
+	r.Register(Set)
+	r.Register(Jmp)
+
+	r.RegisterComparison(">", func(a, b int) bool { return a > b })
+	r.RegisterComparison("<", func(a, b int) bool { return a < b })
+	r.RegisterComparison(">=", func(a, b int) bool { return a >= b })
+	r.RegisterComparison("<=", func(a, b int) bool { return a <= b })
+	r.RegisterComparison("==", func(a, b int) bool { return a == b })
+	r.RegisterComparison("!=", func(a, b int) bool { return a != b })
+	return r
+}
+
+// Register - add +op+, replacing any operation with the same name
+func (r *Registry) Register(op *Operation) {
+	r.operations[op.Name] = op
+}
+
+// RegisterComparison - add a comparison written as +symbol+, which must be
+// made of < > = and ! to get through the lexer
+func (r *Registry) RegisterComparison(symbol string, compare Compare) {
+	r.comparisons[symbol] = compare
+}
+
+// Operation - look up an operation by name
+func (r *Registry) Operation(name string) (*Operation, bool) {
+	op, ok := r.operations[name]
+	return op, ok
+}
+
+// Comparison - look up a comparison by symbol
+func (r *Registry) Comparison(symbol string) (Compare, bool) {
+	compare, ok := r.comparisons[symbol]
+	return compare, ok
+}
+
+// operationNames - every operation name, sorted, for error messages
+func (r *Registry) operationNames() []string {
+	ret := make([]string, 0, len(r.operations))
+	for name := range r.operations {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// comparisonNames - every comparison symbol, sorted, for error messages
+func (r *Registry) comparisonNames() []string {
+	ret := make([]string, 0, len(r.comparisons))
+	for symbol := range r.comparisons {
+		ret = append(ret, symbol)
+	}
+	sort.Strings(ret)
+	return ret
+}
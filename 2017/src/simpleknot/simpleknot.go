@@ -1,3 +1,6 @@
+// Package simpleknot - byte slice knot hash.
+//
+// Deprecated: use knothash, which day10 and day14 now share.
 package simpleknot
 
 import (
@@ -0,0 +1,257 @@
+// Package spiral - the spiral memory from 2017 day 3.
+//
+// Squares are numbered from 1 at the origin, then 2 to the right and on
+// round anticlockwise, with Y increasing upwards:
+//
+//	17  16  15  14  13
+//	18   5   4   3  12
+//	19   6   1   2  11
+//	20   7   8   9  10
+//	21  22  23---> ...
+//
+// Ring k (the squares at Chebyshev distance k) ends with (2k+1)^2 at (k, -k),
+// so both directions of the index <-> coordinate conversion are O(1).
+package spiral
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Point - a square's coordinates
+type Point struct {
+	X, Y int
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%d, %d)", p.X, p.Y)
+}
+
+// Distance - Manhattan distance from the origin (square 1)
+func (p Point) Distance() int {
+	return abs(p.X) + abs(p.Y)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// isqrt - floor(sqrt(n)) for n >= 0, exact for any int
+func isqrt(n int) int {
+	if n < 2 {
+		return n
+	}
+	// Newton's method from above
+	x := n
+	y := (x + 1) / 2
+	for y < x {
+		x = y
+		y = (x + n/x) / 2
+	}
+	return x
+}
+
+// Ring - which ring square +n+ is on (1 is ring 0)
+func Ring(n int) int {
+	// the smallest k with (2k+1)^2 >= n
+	root := isqrt(n - 1)
+	return (root + 1) / 2
+}
+
+// Coord - where square +n+ (>= 1) is. The whole of its ring has to fit in
+// an int, which only rules out the last few billion values.
+func Coord(n int) Point {
+	k := Ring(n)
+	if k == 0 {
+		return Point{}
+	}
+	side := 2 * k
+	last := (side + 1) * (side + 1) // at (k, -k)
+	switch {
+	case n >= last-side: // bottom, running right to (k, -k)
+		return Point{X: k - (last - n), Y: -k}
+	case n >= last-2*side: // left, running down to (-k, -k)
+		return Point{X: -k, Y: -k + (last - side - n)}
+	case n >= last-3*side: // top, running left to (-k, k)
+		return Point{X: -k + (last - 2*side - n), Y: k}
+	default: // right, running up to (k, k)
+		return Point{X: k, Y: k - (last - 3*side - n)}
+	}
+}
+
+// Index - the number of the square at +p+
+func Index(p Point) int {
+	k := abs(p.X)
+	if abs(p.Y) > k {
+		k = abs(p.Y)
+	}
+	if k == 0 {
+		return 1
+	}
+	side := 2 * k
+	last := (side + 1) * (side + 1)
+	switch {
+	case p.Y == -k:
+		return last - (k - p.X)
+	case p.X == -k:
+		return last - side - (p.Y + k)
+	case p.Y == k:
+		return last - 2*side - (p.X + k)
+	default:
+		return last - 3*side - (k - p.Y)
+	}
+}
+
+// Iterator - walks the squares in order, starting at 1
+type Iterator struct {
+	n int
+	p Point
+}
+
+// NewIterator - an iterator positioned before square 1
+func NewIterator() *Iterator {
+	return &Iterator{}
+}
+
+// Next - move to the next square. Always true; the spiral doesn't end.
+func (it *Iterator) Next() bool {
+	it.n++
+	if it.n == 1 {
+		return true
+	}
+	x, y := it.p.X, it.p.Y
+	k := abs(x)
+	if abs(y) > k {
+		k = abs(y)
+	}
+	switch {
+	case x == k && y == -k: // end of a ring (or the origin): step out
+		it.p.X++
+	case x == k && y < k: // right side, going up
+		it.p.Y++
+	case y == k && x > -k: // top, going left
+		it.p.X--
+	case x == -k && y > -k: // left side, going down
+		it.p.Y--
+	default: // bottom, going right
+		it.p.X++
+	}
+	return true
+}
+
+// Index - the current square's number
+func (it *Iterator) Index() int {
+	return it.n
+}
+
+// Point - the current square's coordinates
+func (it *Iterator) Point() Point {
+	return it.p
+}
+
+// Neighbours - the eight squares around +p+
+func (p Point) Neighbours() [8]Point {
+	var ret [8]Point
+	i := 0
+	for dy := 1; dy >= -1; dy-- {
+		for dx := -1; dx <= 1; dx++ {
+			if dx != 0 || dy != 0 {
+				ret[i] = Point{X: p.X + dx, Y: p.Y + dy}
+				i++
+			}
+		}
+	}
+	return ret
+}
+
+// Rule - the value to store in square +n+ at +p+, given +filled+ to look up
+// squares that already have one
+type Rule func(n int, p Point, filled func(Point) (int, bool)) int
+
+// Numbered - each square holds its own number (part A)
+func Numbered(n int, p Point, filled func(Point) (int, bool)) int {
+	return n
+}
+
+// NeighbourSum - square 1 holds 1, every other square the sum of its filled
+// neighbours (part B)
+func NeighbourSum(n int, p Point, filled func(Point) (int, bool)) int {
+	if n == 1 {
+		return 1
+	}
+	sum := 0
+	for _, neighbour := range p.Neighbours() {
+		if v, ok := filled(neighbour); ok {
+			sum += v
+		}
+	}
+	return sum
+}
+
+// Fill - fill squares in order with +rule+ until +stop+ says to, after the
+// square it's given. Returns the values, indexed by square number (so [0] is
+// unused).
+func Fill(rule Rule, stop func(n int, p Point, value int) bool) []int {
+	values := []int{0}
+	filled := func(p Point) (int, bool) {
+		if n := Index(p); n < len(values) {
+			return values[n], true
+		}
+		return 0, false
+	}
+	for it := NewIterator(); it.Next(); {
+		v := rule(it.Index(), it.Point(), filled)
+		values = append(values, v)
+		if stop(it.Index(), it.Point(), v) {
+			break
+		}
+	}
+	return values
+}
+
+// Table - the squares from (+minX+, +minY+) to (+maxX+, +maxY+) as a table
+// with Y running down the left and X along the bottom, as drawn in the
+// puzzle. +cell+ gives the text for each square.
+func Table(minX, minY, maxX, maxY int, cell func(n int, p Point) string) string {
+	rows := make([][]string, 0, maxY-minY+1)
+	width := 0
+	for y := maxY; y >= minY; y-- {
+		row := make([]string, 0, maxX-minX+1)
+		for x := minX; x <= maxX; x++ {
+			p := Point{X: x, Y: y}
+			text := cell(Index(p), p)
+			if len(text) > width {
+				width = len(text)
+			}
+			row = append(row, text)
+		}
+		rows = append(rows, row)
+	}
+	for x := minX; x <= maxX; x++ {
+		if len(fmt.Sprint(x)) > width {
+			width = len(fmt.Sprint(x))
+		}
+	}
+	label := len(fmt.Sprint(minY))
+	if l := len(fmt.Sprint(maxY)); l > label {
+		label = l
+	}
+
+	var out strings.Builder
+	for i, row := range rows {
+		fmt.Fprintf(&out, "(Y=%*d)", label, maxY-i)
+		for _, text := range row {
+			fmt.Fprintf(&out, " %*s", width, text)
+		}
+		out.WriteString("\n")
+	}
+	fmt.Fprintf(&out, "%-*s", label+4, "X=")
+	for x := minX; x <= maxX; x++ {
+		fmt.Fprintf(&out, " %*d", width, x)
+	}
+	out.WriteString("\n")
+	return out.String()
+}
@@ -0,0 +1,95 @@
+package spiral
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	it := NewIterator()
+	var prev Point
+	for n := 1; n <= 5000; n++ {
+		it.Next()
+		p := it.Point()
+		if it.Index() != n {
+			t.Fatalf("step %d: iterator is at %d", n, it.Index())
+		}
+		if got := Coord(n); got != p {
+			t.Errorf("%d: Coord gives %s, the iterator %s", n, got, p)
+		}
+		if got := Index(p); got != n {
+			t.Errorf("%s: Index gives %d, want %d", p, got, n)
+		}
+		if n > 1 && abs(p.X-prev.X)+abs(p.Y-prev.Y) != 1 {
+			t.Errorf("%d: stepped from %s to %s", n, prev, p)
+		}
+		prev = p
+	}
+}
+
+func TestInverse(t *testing.T) {
+	for x := -40; x <= 40; x++ {
+		for y := -40; y <= 40; y++ {
+			p := Point{X: x, Y: y}
+			if got := Coord(Index(p)); got != p {
+				t.Errorf("%s is square %d, which is at %s", p, Index(p), got)
+			}
+		}
+	}
+	// far enough out that anything going through floating point would slip
+	for _, n := range []int{1 << 40, 1<<52 + 1, 1<<60 - 1, 999999999999999999} {
+		if got := Index(Coord(n)); got != n {
+			t.Errorf("%d is at %s, which is square %d", n, Coord(n), got)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// the part A examples
+	tests := []struct {
+		n, want int
+	}{
+		{1, 0},
+		{12, 3},
+		{23, 2},
+		{1024, 31},
+	}
+	for _, test := range tests {
+		if got := Coord(test.n).Distance(); got != test.want {
+			t.Errorf("%d: got %d, want %d", test.n, got, test.want)
+		}
+	}
+}
+
+func TestRing(t *testing.T) {
+	tests := []struct {
+		n, want int
+	}{
+		{1, 0}, {2, 1}, {9, 1}, {10, 2}, {25, 2}, {26, 3}, {49, 3}, {50, 4},
+	}
+	for _, test := range tests {
+		if got := Ring(test.n); got != test.want {
+			t.Errorf("%d: ring %d, want %d", test.n, got, test.want)
+		}
+	}
+}
+
+func TestFill(t *testing.T) {
+	// the part B example's first squares
+	want := []int{0, 1, 1, 2, 4, 5, 10, 11, 23, 25, 26, 54, 57, 59, 122, 133, 142, 147, 304, 330, 351, 362, 747, 806}
+	got := Fill(NeighbourSum, func(n int, p Point, value int) bool {
+		return value > 800
+	})
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	numbered := Fill(Numbered, func(n int, p Point, value int) bool {
+		return n == 100
+	})
+	for n, v := range numbered[1:] {
+		if v != n+1 {
+			t.Errorf("square %d holds %d", n+1, v)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Package unionfind - a disjoint-set forest over int ids, with path
+// compression and union by rank. Ids are added on first use, so they don't
+// need to be dense.
+package unionfind
+
+import (
+	"sort"
+)
+
+// Set - a collection of disjoint groups
+type Set struct {
+	parent map[int]int
+	rank   map[int]int
+	size   map[int]int // group size, kept up to date for roots only
+	groups int
+}
+
+// New - an empty set
+func New() *Set {
+	return &Set{
+		parent: make(map[int]int),
+		rank:   make(map[int]int),
+		size:   make(map[int]int),
+	}
+}
+
+// Add - put +x+ in a group of its own if it isn't in one already
+func (s *Set) Add(x int) {
+	if _, ok := s.parent[x]; ok {
+		return
+	}
+	s.parent[x] = x
+	s.size[x] = 1
+	s.groups++
+}
+
+// Find - the representative of +x+'s group, adding +x+ if it's new. Every
+// id on the way up is pointed straight at the root.
+func (s *Set) Find(x int) int {
+	s.Add(x)
+	root := x
+	for s.parent[root] != root {
+		root = s.parent[root]
+	}
+	for s.parent[x] != root {
+		s.parent[x], x = root, s.parent[x]
+	}
+	return root
+}
+
+// Union - join the groups of +a+ and +b+. False if they were already one.
+func (s *Set) Union(a, b int) bool {
+	ra, rb := s.Find(a), s.Find(b)
+	if ra == rb {
+		return false
+	}
+	// hang the shallower tree off the deeper one
+	if s.rank[ra] < s.rank[rb] {
+		ra, rb = rb, ra
+	}
+	s.parent[rb] = ra
+	if s.rank[ra] == s.rank[rb] {
+		s.rank[ra]++
+	}
+	s.size[ra] += s.size[rb]
+	delete(s.size, rb)
+	delete(s.rank, rb)
+	s.groups--
+	return true
+}
+
+// Connected - are +a+ and +b+ in the same group?
+func (s *Set) Connected(a, b int) bool {
+	return s.Find(a) == s.Find(b)
+}
+
+// Len - how many ids there are
+func (s *Set) Len() int {
+	return len(s.parent)
+}
+
+// Count - how many groups there are
+func (s *Set) Count() int {
+	return s.groups
+}
+
+// Size - how many ids are in +x+'s group
+func (s *Set) Size(x int) int {
+	return s.size[s.Find(x)]
+}
+
+// Groups - every group's members in ascending order, the groups ordered by
+// their smallest member
+func (s *Set) Groups() [][]int {
+	ids := make([]int, 0, len(s.parent))
+	for x := range s.parent {
+		ids = append(ids, x)
+	}
+	sort.Ints(ids)
+	index := make(map[int]int) // root -> position in ret
+	ret := make([][]int, 0, s.groups)
+	for _, x := range ids {
+		root := s.Find(x)
+		i, ok := index[root]
+		if !ok {
+			i = len(ret)
+			index[root] = i
+			ret = append(ret, make([]int, 0, s.size[root]))
+		}
+		ret[i] = append(ret[i], x)
+	}
+	return ret
+}
@@ -0,0 +1,96 @@
+package unionfind
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnion(t *testing.T) {
+	s := New()
+	for _, x := range []int{10, 20, 30, 40, 50} {
+		s.Add(x)
+	}
+	s.Add(10) // already there
+	if s.Len() != 5 || s.Count() != 5 {
+		t.Fatalf("5 ids on their own: %d ids in %d groups", s.Len(), s.Count())
+	}
+
+	steps := []struct {
+		a, b   int
+		joined bool
+		count  int
+		size   int // of a's group afterwards
+	}{
+		{10, 20, true, 4, 2},
+		{20, 10, false, 4, 2},
+		{30, 40, true, 3, 2},
+		{40, 20, true, 2, 4},
+		{10, 30, false, 2, 4},
+		{60, 70, true, 3, 2}, // both new
+		{50, 50, false, 3, 1},
+		{70, 10, true, 2, 6},
+	}
+	for _, step := range steps {
+		if got := s.Union(step.a, step.b); got != step.joined {
+			t.Errorf("union %d %d: got %t, want %t", step.a, step.b, got, step.joined)
+		}
+		if s.Count() != step.count {
+			t.Errorf("after %d %d: %d groups, want %d", step.a, step.b, s.Count(), step.count)
+		}
+		if s.Size(step.a) != step.size || s.Size(step.b) != s.Size(step.a) {
+			t.Errorf("after %d %d: sizes %d and %d, want %d", step.a, step.b, s.Size(step.a), s.Size(step.b), step.size)
+		}
+		if !s.Connected(step.a, step.b) {
+			t.Errorf("after %d %d: not connected", step.a, step.b)
+		}
+	}
+	if s.Len() != 7 {
+		t.Errorf("%d ids, want 7", s.Len())
+	}
+	if s.Connected(50, 10) {
+		t.Errorf("50 was never joined to anything, but is connected to 10")
+	}
+	if got, want := s.Groups(), [][]int{{10, 20, 30, 40, 60, 70}, {50}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("groups %v, want %v", got, want)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	// the day 12 example
+	pipes := map[int][]int{
+		0: {2},
+		1: {1},
+		2: {0, 3, 4},
+		3: {2, 4},
+		4: {2, 3, 6},
+		5: {6},
+		6: {4, 5},
+	}
+	s := New()
+	for a, bs := range pipes {
+		for _, b := range bs {
+			s.Union(a, b)
+		}
+	}
+	if got, want := s.Groups(), [][]int{{0, 2, 3, 4, 5, 6}, {1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("groups %v, want %v", got, want)
+	}
+	if s.Size(0) != 6 || s.Count() != 2 {
+		t.Errorf("0 is in a group of %d out of %d groups, want 6 out of 2", s.Size(0), s.Count())
+	}
+	if len(New().Groups()) != 0 {
+		t.Errorf("an empty set has groups")
+	}
+}
+
+func TestLongChain(t *testing.T) {
+	// joining each to the next would be a long path without union by rank
+	s := New()
+	const n = 100000
+	for i := 1; i < n; i++ {
+		s.Union(i-1, i)
+	}
+	if s.Count() != 1 || s.Size(n/2) != n || !s.Connected(0, n-1) {
+		t.Errorf("%d groups, the middle's is %d big", s.Count(), s.Size(n/2))
+	}
+}
@@ -1,11 +1,11 @@
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
 	"os"
-	"strconv"
+
+	"aoc2018/day01"
 )
 
 var (
@@ -24,39 +24,19 @@ func main() {
 		fmt.Printf("Couldn't open %s: %v\n", *inputFile, err)
 		os.Exit(1)
 	}
-	calibration := 0
-	lineReader := bufio.NewScanner(input)
-	freqs := make([]int, 0)
-	for lineReader.Scan() {
-		line := lineReader.Text()
-
-		number, err := strconv.Atoi(line)
-		if err != nil {
-			fmt.Printf("Couldn't parse %s: %e\n", line, err)
-			os.Exit(1)
-		}
-		freqs = append(freqs, number)
-
+	defer input.Close()
+	freqs, err := day01.Parse(input)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		os.Exit(1)
 	}
-	if !*partB {
-		for _, i := range freqs {
-			calibration += i
-		}
-	} else {
-		// seen this freq yet?
-		seen := make(map[int]bool)
-		done := false
-		for !done {
-			// go through freqs til the end and we need to wrap, or, just bail out if we have a dupe
-			for i := 0; !done && i < len(freqs); i++ {
-				calibration += freqs[i]
-				if seen[calibration] {
-					done = true
-				}
-				seen[calibration] = true
-			}
+	calibration := day01.Sum(freqs)
+	if *partB {
+		var ok bool
+		if calibration, ok = day01.FirstRepeat(freqs); !ok {
+			fmt.Printf("No frequency is ever reached twice\n")
+			os.Exit(1)
 		}
-
 	}
 
 	fmt.Printf("Final calibration: %d\n", calibration)
@@ -0,0 +1,21 @@
+// Package aoc2018 - every 2018 day. Import it for its side effects to
+// register them all with the aoc package.
+package aoc2018
+
+import (
+	_ "aoc2018/day01"
+	_ "aoc2018/day02"
+	_ "aoc2018/day03"
+	_ "aoc2018/day04"
+	_ "aoc2018/day05"
+	_ "aoc2018/day06"
+	_ "aoc2018/day07"
+	_ "aoc2018/day08"
+	_ "aoc2018/day09"
+	_ "aoc2018/day10"
+	_ "aoc2018/day11"
+	_ "aoc2018/day12"
+	_ "aoc2018/day13"
+	_ "aoc2018/day14"
+	_ "aoc2018/day16"
+)
@@ -0,0 +1,13 @@
+package aoc2018
+
+import (
+	"testing"
+
+	"aoc/aoctest"
+)
+
+// TestExamples - every answer file in 2018/inputs, the same as aoc verify
+// -year 2018
+func TestExamples(t *testing.T) {
+	aoctest.VerifyTest(t, 2018)
+}
@@ -0,0 +1,104 @@
+// Package day01 - 2018 day 1: calibrate the device from a list of frequency
+// changes
+package day01
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2018, 1, PartA, PartB))
+}
+
+// Parse - the frequency changes in +r+, one per line
+func Parse(r io.Reader) ([]int, error) {
+	freqs := make([]int, 0)
+	lineReader := bufio.NewScanner(r)
+	for lineReader.Scan() {
+		line := strings.TrimSpace(lineReader.Text())
+		if line == "" {
+			continue
+		}
+		number, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %w", line, err)
+		}
+		freqs = append(freqs, number)
+	}
+	return freqs, lineReader.Err()
+}
+
+// Sum - the frequency after every change
+func Sum(freqs []int) int {
+	calibration := 0
+	for _, i := range freqs {
+		calibration += i
+	}
+	return calibration
+}
+
+// FirstRepeat - the first frequency reached twice, going round the changes
+// as many times as it takes. False if no frequency ever repeats.
+func FirstRepeat(freqs []int) (int, bool) {
+	if Sum(freqs) == 0 {
+		// every pass ends where it started, so that start repeats at the latest
+		return firstRepeatIn(freqs, 1)
+	}
+	// the running totals drift by the sum each pass, so after the spread of
+	// the first pass's totals every new frequency is out of reach
+	low, high, calibration := 0, 0, 0
+	for _, f := range freqs {
+		calibration += f
+		low, high = min(low, calibration), max(high, calibration)
+	}
+	drift := Sum(freqs)
+	if drift < 0 {
+		drift = -drift
+	}
+	return firstRepeatIn(freqs, (high-low)/drift+2)
+}
+
+// firstRepeatIn - the first repeated frequency in +passes+ passes
+func firstRepeatIn(freqs []int, passes int) (int, bool) {
+	// seen this freq yet?
+	seen := map[int]bool{0: true}
+	calibration := 0
+	for pass := 0; pass < passes; pass++ {
+		for _, f := range freqs {
+			calibration += f
+			if seen[calibration] {
+				return calibration, true
+			}
+			seen[calibration] = true
+		}
+	}
+	return 0, false
+}
+
+// PartA - the frequency after every change
+func PartA(r io.Reader) (string, error) {
+	freqs, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(Sum(freqs)), nil
+}
+
+// PartB - the first frequency reached twice
+func PartB(r io.Reader) (string, error) {
+	freqs, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	calibration, ok := FirstRepeat(freqs)
+	if !ok {
+		return "", fmt.Errorf("no frequency is ever reached twice")
+	}
+	return strconv.Itoa(calibration), nil
+}
@@ -0,0 +1,125 @@
+// Package day02 - 2018 day 2: checksum the box ids and find the two boxes
+// whose ids differ by one letter
+package day02
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2018, 2, PartA, PartB))
+}
+
+// Debug - print the working
+var Debug = false
+
+// Parse - the box ids in +r+, one per line, lower cased
+func Parse(r io.Reader) ([]string, error) {
+	lineReader := bufio.NewScanner(r)
+	inputRows := make([]string, 0)
+	for lineReader.Scan() {
+		if line := strings.TrimSpace(lineReader.Text()); line != "" {
+			inputRows = append(inputRows, strings.ToLower(line))
+		}
+	}
+	return inputRows, lineReader.Err()
+}
+
+// Checksum - the number of ids with a letter exactly twice times the number
+// with a letter exactly three times
+func Checksum(inputRows []string) int {
+	check2 := 0
+	check3 := 0
+	for _, line := range inputRows {
+		letters := make(map[rune]int)
+		had2 := false
+		had3 := false
+
+		for _, r := range line {
+			letters[r]++
+		}
+		for _, c := range letters {
+			if c == 2 && !had2 {
+				check2++
+				had2 = true
+			}
+			if c == 3 && !had3 {
+				check3++
+				had3 = true
+			}
+		}
+		if Debug {
+			fmt.Printf(" Line: %s, Letters: %d\n", line, letters)
+			fmt.Printf(" Check2: %d, Check3: %d\n", check2, check3)
+		}
+	}
+	return check2 * check3
+}
+
+// CommonLetters - the letters two ids that differ in exactly one place have
+// in common. False if no two ids do.
+func CommonLetters(inputRows []string) (string, bool) {
+	// Compare each box to the others in search of one with a single id letter
+	// difference. Every letter in the right side must match the left side
+	// except for one, so remember the first difference and bail out at a
+	// second.
+	for left := 0; left < len(inputRows)-1; left++ {
+		if Debug {
+			fmt.Printf("Comparing %s\n", inputRows[left])
+		}
+	rightBox:
+		for right := left + 1; right < len(inputRows); right++ {
+			if len(inputRows[right]) != len(inputRows[left]) {
+				continue
+			}
+			differentLetter := -1
+			for i := 0; i < len(inputRows[left]); i++ {
+				if inputRows[left][i] != inputRows[right][i] {
+					if differentLetter >= 0 {
+						// uh oh, we have a second difference. try the next box
+						continue rightBox
+					}
+					differentLetter = i
+				}
+			}
+			if differentLetter < 0 {
+				continue // the same id twice
+			}
+			if Debug {
+				fmt.Printf("I claim that %s and %s are the boxes! The only different letter is at %d\n", inputRows[left], inputRows[right], differentLetter)
+			}
+			// Now strip out the difference
+			return inputRows[left][:differentLetter] + inputRows[left][differentLetter+1:], true
+		}
+	}
+	return "", false
+}
+
+// PartA - the checksum of the box ids
+func PartA(r io.Reader) (string, error) {
+	ids, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(Checksum(ids)), nil
+}
+
+// PartB - the letters the two prototype boxes' ids have in common
+func PartB(r io.Reader) (string, error) {
+	ids, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	common, ok := CommonLetters(ids)
+	if !ok {
+		return "", errors.New("no two box ids differ by exactly one letter")
+	}
+	return common, nil
+}
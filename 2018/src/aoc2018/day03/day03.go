@@ -0,0 +1,211 @@
+// Package day03 - 2018 day 3: overlapping claims on the fabric
+package day03
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2018, 3, PartA, PartB))
+}
+
+var (
+	// Debug - print the overlapping points and claims
+	Debug = false
+	// Debug2 - print every comparison too
+	Debug2 = false
+
+	picker = regexp.MustCompile(`^#(\d.*) @ (\d{1,}),(\d{1,}): (\d{1,})x(\d{1,})$`)
+)
+
+// Point - an (x,y) point on a plane.
+type Point struct {
+	X int
+	Y int
+}
+
+// NewPoint - Create a pointer to a Point
+func NewPoint(x, y int) *Point {
+	return &Point{
+		X: x,
+		Y: y,
+	}
+}
+
+// Claim - #123 @ 3,2: 5x4
+// Note: LeftX is inches from the left _BEFORE_ our Claim,
+// and TopY is the inches from the top _BEFORE_ our Claim.
+// Thus, we really exist within these bounds:
+// (LeftX+1,TopY+1), (LeftX+1+Width,TopY+1), (LeftX+1,TopY+1+Height), (LeftX+1+Width,TopY+1+Height)
+// Or: X plane [LeftX+1,LeftX+1+Width] & Y Plane [TopY+1,TopY+1+Height]
+type Claim struct {
+	ID       int      // #123
+	Height   int      // 4
+	Width    int      // 5
+	LeftX    int      // 3
+	TopY     int      // 2
+	Points   []*Point // (X,Y) coords this Claim has
+	Overlaps int      // how many other points does this overlap with?
+}
+
+// NewClaim - make a new claim on the fabric
+func newClaim(id, leftx, topy, width, height int) *Claim {
+	return &Claim{
+		ID:       id,
+		LeftX:    leftx,
+		TopY:     topy,
+		Width:    width,
+		Height:   height,
+		Overlaps: 0,
+		Points:   make([](*Point), 0),
+	}
+}
+
+// ParseClaim - parse the Claim from the raw input
+func ParseClaim(rawLine string) (*Claim, error) {
+	match := picker.FindStringSubmatch(rawLine)
+	if match == nil {
+		return nil, fmt.Errorf("couldn't parse from %s", rawLine)
+	}
+	var fields [5]int
+	for i := range fields {
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse from %s: %w", rawLine, err)
+		}
+		fields[i] = n
+	}
+	claim := newClaim(fields[0], fields[1], fields[2], fields[3], fields[4])
+	for x := claim.LeftX + 1; x <= claim.LeftX+claim.Width; x++ {
+		for y := claim.TopY + 1; y <= claim.TopY+claim.Height; y++ {
+			claim.Points = append(claim.Points, NewPoint(x, y))
+		}
+	}
+	return claim, nil
+}
+
+// FindOverlapSize - Returns the total area of overlap between my Claim and the other Claim.
+// If there is no overlap the return will be 0.
+// Claims overlap if any region of the other Claim is within the bounds of our own Claim.
+// FIXME: This is insufficient because (2,5) doesn't overlap (2,7). This check will count it as an overlap.
+// Brute force method is to check each cell against each one another.
+func (c *Claim) FindOverlapSize(other *Claim) []*Point {
+
+	overlap := make([]*Point, 0)
+
+	// Go through all our points, compare to their points.
+	for _, p := range c.Points {
+		//p is a *Point
+		for _, o := range other.Points {
+			if p.X != o.X || p.Y != o.Y {
+				continue
+			}
+			if Debug2 {
+				fmt.Printf("FindOverlapSize: My Point: (%d,%d) -> Their Point (%d,%d)\n", p.X, p.Y, o.X, o.Y)
+			}
+
+			if p.X == o.X && p.Y == o.Y {
+				c.Overlaps++
+				other.Overlaps++
+				overlap = append(overlap, p)
+			}
+		}
+	}
+
+	if Debug2 {
+		fmt.Printf("Overlaps %v\n", overlap)
+	}
+	return overlap
+
+}
+
+// Parse - the claims in +r+, one per line
+func Parse(r io.Reader) ([]*Claim, error) {
+	allClaims := make([]*Claim, 0)
+	lineReader := bufio.NewScanner(r)
+	for lineReader.Scan() {
+		line := strings.TrimSpace(lineReader.Text())
+		if line == "" {
+			continue
+		}
+		claim, err := ParseClaim(line)
+		if err != nil {
+			return nil, err
+		}
+		if Debug2 {
+			fmt.Printf("Added a Claim: index=%d, %+v\n", len(allClaims), claim)
+		}
+		allClaims = append(allClaims, claim)
+	}
+	return allClaims, lineReader.Err()
+}
+
+// Overlaps - every point more than one claim covers. Counts each claim's
+// Overlaps as it goes.
+func Overlaps(allClaims []*Claim) map[Point]bool {
+	uniqueOverlaps := make(map[Point]bool)
+	// Loop through all the Claims and compare them to one another. If we've
+	// compared ID 1 to 2 we don't need to compare 2 to 1.
+	for left := 0; left < len(allClaims)-1; left++ {
+		if Debug2 {
+			fmt.Printf("Left: %+v\n", allClaims[left])
+		}
+		for right := left + 1; right < len(allClaims); right++ {
+			if Debug2 {
+				fmt.Printf("left=%d, right=%d\n", left, right)
+				fmt.Printf("  Right: %+v\n", allClaims[right])
+			}
+			for _, overlappingPoint := range allClaims[left].FindOverlapSize(allClaims[right]) {
+				// Deduplicate.
+				uniqueOverlaps[*overlappingPoint] = true
+			}
+		}
+	}
+	if Debug {
+		fmt.Printf("All overlapping points: %+v\n", uniqueOverlaps)
+	}
+	return uniqueOverlaps
+}
+
+// Intact - the claims that overlap no other, once Overlaps has counted them
+func Intact(allClaims []*Claim) []*Claim {
+	ret := make([]*Claim, 0)
+	for _, c := range allClaims {
+		if c.Overlaps == 0 {
+			ret = append(ret, c)
+		} else if Debug {
+			fmt.Printf("Claim %d has %d overlaps (rip)\n", c.ID, c.Overlaps)
+		}
+	}
+	return ret
+}
+
+// PartA - how many square inches are in two or more claims
+func PartA(r io.Reader) (string, error) {
+	allClaims, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(len(Overlaps(allClaims))), nil
+}
+
+// PartB - the id of the only claim that doesn't overlap
+func PartB(r io.Reader) (string, error) {
+	allClaims, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	Overlaps(allClaims)
+	intact := Intact(allClaims)
+	if len(intact) != 1 {
+		return "", fmt.Errorf("expected one claim without overlaps, found %d", len(intact))
+	}
+	return strconv.Itoa(intact[0].ID), nil
+}
@@ -0,0 +1,324 @@
+// Package day04 - 2018 day 4: find the sleepiest guard, and the minute
+// they're most likely to be asleep
+package day04
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2018, 4, PartA, PartB))
+}
+
+var (
+	// Debug - print every shift and how the answer is worked out
+	Debug = false
+	// Debug2 - print the log once every action has a guard
+	Debug2 = false
+	// Debug3 - print the log as it was parsed
+	Debug3 = false
+
+	// Match groups: 1-5: yyyy-mm-dd hh:mm. 6: action identifier part 1. 7: action identifier part 2
+	// action identifier part 1 could be in [Guard, falls, wakes]. If part 1 is
+	// Guard, then part 2 is that Guard's identifier, otherwise, it is discarded
+	// Note, group 7 doesn't actually include the `#` before the guard ID, although
+	// it is an optional part of the matchgroup (eg, this token may be [#\d{1,},
+	// falls, wakes])
+	datePicker = regexp.MustCompile(`\[(\d{4})-(\d{2})-(\d{2}) (\d{2}):(\d{2})\] (\w+) #?([a-z0-9]+)`)
+)
+
+// What is the guard doing?
+type guardState int
+
+const (
+	beginShift guardState = iota
+	beginSleep
+	wakesUp
+)
+
+// GuardAction - What's the guard doing?
+// PreviousAction and NextAction have to do with the next in the log file, not this guard's actions
+type GuardAction struct {
+	GuardID   int
+	Action    guardState
+	Timestamp int64
+}
+
+// Guard - just this guard, you know?
+type Guard struct {
+	GuardID      int
+	SleepTime    int   // total amount of time asleep
+	SleepMinutes []int // on which minute do they fall asleep?
+}
+
+// AddSleepTime - +g+ having slept +t+ more minutes
+func (g Guard) AddSleepTime(t int) Guard {
+	g.SleepTime += t
+	return g
+}
+
+// AddSleepMinutes - add a single minute
+func (g Guard) AddSleepMinutes(t int) Guard {
+	g.SleepMinutes = append(g.SleepMinutes, t)
+	return g
+}
+
+// Parse - the log in +r+, sorted by time. Only the beginShift actions have
+// a GuardID; Tally fills in the rest.
+func Parse(r io.Reader) ([]GuardAction, error) {
+	lineReader := bufio.NewScanner(r)
+	guardActions := make([]GuardAction, 0)
+	// Parse the log file:
+	// Pick out the date and guard info.
+	for lineReader.Scan() {
+		line := lineReader.Text()
+		if line == "" {
+			continue
+		}
+		lineParts := datePicker.FindStringSubmatch(line)
+		if lineParts == nil {
+			return nil, fmt.Errorf("couldn't parse %q", line)
+		}
+		// the pattern only matches digits here, so these can't fail
+		var fields [5]int
+		for i := range fields {
+			fields[i], _ = strconv.Atoi(lineParts[i+1])
+		}
+		year, month, day, hour, minute := fields[0], fields[1], fields[2], fields[3], fields[4]
+
+		actionTime := time.Date(year, time.Month(month), day, hour, minute, 0, 0, time.UTC).Unix()
+
+		var guard GuardAction
+
+		guard.Timestamp = actionTime
+
+		// parse what it's doing
+		switch lineParts[6] {
+		case "Guard":
+			guardID, err := strconv.Atoi(lineParts[7])
+			if err != nil {
+				return nil, fmt.Errorf("got a Guard, but couldn't parse its ID: %w", err)
+			}
+			guard.GuardID = guardID
+			guard.Action = beginShift
+		case "falls":
+			//guard fell asleep
+			guard.Action = beginSleep
+		case "wakes":
+			//guard woke up
+			guard.Action = wakesUp
+		default:
+			// wut?
+			return nil, fmt.Errorf("couldn't figure out WTF this guard was doing: %s", lineParts[6])
+		}
+		guardActions = append(guardActions, guard)
+	}
+	if err := lineReader.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(guardActions, func(i, j int) bool { return guardActions[i].Timestamp < guardActions[j].Timestamp })
+
+	if Debug3 {
+		fmt.Printf("Some are missing guard IDs\n")
+		for index, ga := range guardActions {
+			fmt.Printf("Index %d -> (Timestamp: [%d-%02d-%02d %02d:%02d]) = %+v\n", index,
+				time.Unix(ga.Timestamp, 0).Year(), time.Unix(ga.Timestamp, 0).Month(),
+				time.Unix(ga.Timestamp, 0).Day(), time.Unix(ga.Timestamp, 0).Hour(),
+				time.Unix(ga.Timestamp, 0).Minute(),
+				ga)
+		}
+	}
+
+	return guardActions, nil
+}
+
+// Tally - every guard's sleep, from the log +guardActions+, and the guard
+// who slept the most
+func Tally(guardActions []GuardAction) (allGuards map[int]Guard, mostSleptID int) {
+	var currentGuard int
+
+	// Summary
+	var sleepBeginsAt time.Time
+	// guard id -> sleepcount
+	allGuards = make(map[int]Guard)
+	mostSleptID = -1
+	mostSleptMinutes := -1
+	for index, guard := range guardActions {
+		timestamp := time.Unix(guard.Timestamp, 0)
+		switch guard.Action {
+		case beginShift:
+			currentGuard = guard.GuardID
+			if Debug {
+				fmt.Printf("[%d-%02d-%02d %02d:%02d] Guard %d began shift\n",
+					timestamp.Year(), timestamp.Month(), timestamp.Day(), timestamp.Hour(), timestamp.Minute(), currentGuard)
+			}
+			if _, ok := allGuards[currentGuard]; !ok {
+				if Debug {
+					fmt.Printf("init guard %d\n", currentGuard)
+				}
+				allGuards[currentGuard] = Guard{
+					GuardID:      currentGuard,
+					SleepTime:    0,
+					SleepMinutes: make([]int, 0),
+				}
+			}
+		case beginSleep:
+			guardActions[index].GuardID = currentGuard
+			if Debug {
+				fmt.Printf("[%d-%02d-%02d %02d:%02d] Guard %d went to sleep\n",
+					timestamp.Year(), timestamp.Month(), timestamp.Day(), timestamp.Hour(), timestamp.Minute(), currentGuard)
+			}
+			sleepBeginsAt = timestamp
+			allGuards[currentGuard] = allGuards[currentGuard].AddSleepMinutes(timestamp.Minute())
+		case wakesUp:
+			guardActions[index].GuardID = currentGuard
+			if Debug {
+				fmt.Printf("[%d-%02d-%02d %02d:%02d] Guard %d woke up\n",
+					timestamp.Year(), timestamp.Month(), timestamp.Day(), timestamp.Hour(), timestamp.Minute(), currentGuard)
+			}
+			// tally how long were they asleep?
+			sleepDuration := int(timestamp.Sub(sleepBeginsAt).Minutes())
+			allGuards[currentGuard] = allGuards[currentGuard].AddSleepTime(sleepDuration)
+
+			// add all the minutes they were asleep to the list.
+			// take each minute in the range (now - start] and add to the list
+			for i := sleepBeginsAt.Unix(); i < timestamp.Unix(); i += 60 {
+				if Debug {
+					fmt.Printf(" * Adding minute %d\n", time.Unix(i, 0).Minute())
+				}
+				allGuards[currentGuard] = allGuards[currentGuard].AddSleepMinutes(time.Unix(i, 0).Minute())
+			}
+
+			if allGuards[currentGuard].SleepTime > mostSleptMinutes {
+				mostSleptMinutes = allGuards[currentGuard].SleepTime
+				mostSleptID = currentGuard
+			}
+		}
+
+	}
+
+	if Debug2 {
+		fmt.Printf("Should now all have guardIDs that make sense\n")
+		for index, ga := range guardActions {
+			fmt.Printf("Index %d -> (Timestamp: [%d-%02d-%02d %02d:%02d]) = %+v\n", index,
+				time.Unix(ga.Timestamp, 0).Year(), time.Unix(ga.Timestamp, 0).Month(),
+				time.Unix(ga.Timestamp, 0).Day(), time.Unix(ga.Timestamp, 0).Hour(),
+				time.Unix(ga.Timestamp, 0).Minute(),
+				ga)
+		}
+	}
+	if Debug {
+		fmt.Printf("Most sleepy guard: %d. Their actions: %+v\n", mostSleptID, allGuards[mostSleptID])
+	}
+	return allGuards, mostSleptID
+}
+
+// MostCommonMinute - strategy 1: the minute the sleepiest guard
+// +mostSleptID+ was most often asleep
+func MostCommonMinute(allGuards map[int]Guard, mostSleptID int) int {
+	// histogram: minute -> count
+	sleepMinuteHistogram := make(map[int]int)
+	for _, minute := range allGuards[mostSleptID].SleepMinutes {
+		if Debug {
+			fmt.Printf("Incrementing minute %d count to %d\n", minute, sleepMinuteHistogram[minute]+1)
+		}
+		sleepMinuteHistogram[minute]++
+	}
+	if Debug {
+		fmt.Printf("Init most common minute to %d\n", allGuards[mostSleptID].SleepMinutes[0])
+	}
+	mostCommonMinute := allGuards[mostSleptID].SleepMinutes[0]
+	for minute, count := range sleepMinuteHistogram {
+		if count > sleepMinuteHistogram[mostCommonMinute] {
+			if Debug {
+				fmt.Printf("new high score: %d minutes at %d\n", minute, count)
+			}
+			mostCommonMinute = minute
+		}
+	}
+	return mostCommonMinute
+}
+
+// MostFrequentlyAsleep - strategy 2: the guard most frequently asleep on the
+// same minute, and that minute
+func MostFrequentlyAsleep(allGuards map[int]Guard) (commonGuardID, mostCommonMinute int) {
+	// For every minute figure out which guard asleep on it the most, and which minute that was
+	// initialize these for later
+	mostCommonMinute = -1
+	mostCommonMinuteFreq := -1
+	for _, guard := range allGuards {
+		histogram := make(map[int]int)
+		locallyCommonMinute := -1
+		locallyCommonMinuteFreq := -1
+
+		for _, minute := range guard.SleepMinutes {
+			if Debug {
+				fmt.Printf("Guard %d increasing histo minute %d->%d\n", guard.GuardID, minute, histogram[minute]+1)
+			}
+			histogram[minute]++
+		}
+		if Debug {
+			fmt.Printf("Histogram: %+v\n", histogram)
+		}
+		for minute, count := range histogram {
+			if count > histogram[locallyCommonMinute] {
+				if Debug {
+					fmt.Printf("New local high score! %d replaces %d (%d > %d)\n", minute, locallyCommonMinute, count, histogram[locallyCommonMinute])
+				}
+				locallyCommonMinute = minute
+				locallyCommonMinuteFreq = count
+			}
+		}
+		if locallyCommonMinuteFreq > mostCommonMinuteFreq {
+			if Debug {
+				fmt.Printf("New overall high score! Guard %d replaces Guard %d's score of %d with %d (%d > %d)\n", guard.GuardID, commonGuardID, mostCommonMinute, locallyCommonMinute, locallyCommonMinuteFreq, mostCommonMinuteFreq)
+			}
+			mostCommonMinute = locallyCommonMinute
+			mostCommonMinuteFreq = locallyCommonMinuteFreq
+			commonGuardID = guard.GuardID
+		}
+	}
+
+	return commonGuardID, mostCommonMinute
+}
+
+func tally(r io.Reader) (map[int]Guard, int, error) {
+	guardActions, err := Parse(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	allGuards, mostSleptID := Tally(guardActions)
+	if mostSleptID < 0 {
+		return nil, 0, errors.New("no guard ever fell asleep")
+	}
+	return allGuards, mostSleptID, nil
+}
+
+// PartA - the sleepiest guard's id times their most common minute
+func PartA(r io.Reader) (string, error) {
+	allGuards, mostSleptID, err := tally(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(mostSleptID * MostCommonMinute(allGuards, mostSleptID)), nil
+}
+
+// PartB - the id of the guard most frequently asleep on the same minute
+// times that minute
+func PartB(r io.Reader) (string, error) {
+	allGuards, _, err := tally(r)
+	if err != nil {
+		return "", err
+	}
+	commonGuardID, mostCommonMinute := MostFrequentlyAsleep(allGuards)
+	return strconv.Itoa(commonGuardID * mostCommonMinute), nil
+}
@@ -0,0 +1,90 @@
+// Package day05 - 2018 day 5: react a polymer, removing adjacent units of
+// the same type and opposite polarity until none are left
+package day05
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2018, 5, PartA, PartB))
+}
+
+var (
+	// Debug2 - print the polymer after every reaction
+	Debug2 = false
+
+	parta     = regexp.MustCompile(`aA|Aa|bB|Bb|cC|Cc|dD|Dd|eE|Ee|fF|Ff|gG|Gg|hH|Hh|iI|Ii|jJ|Jj|kK|Kk|lL|Ll|mM|Mm|nN|Nn|oO|Oo|pP|Pp|qQ|Qq|rR|Rr|sS|Ss|tT|Tt|uU|Uu|vV|Vv|wW|Ww|xX|Xx|yY|Yy|zZ|Zz`)
+	partBList = []string{`a`, `b`, `c`, `d`, `e`, `f`, `g`, `h`, `i`, `j`, `k`, `l`, `m`, `n`, `o`, `p`, `q`, `r`, `s`, `t`, `u`, `v`, `w`, `x`, `y`, `z`}
+)
+
+// React - Do the reaction.
+func React(inputPolymer *string) {
+	for {
+
+		match := parta.FindAllStringIndex(*inputPolymer, 1)
+		if len(match) == 0 {
+			break
+		}
+		if Debug2 {
+			fmt.Printf("Removed %s at [%d:%d] -> ", (*inputPolymer)[match[0][0]:match[0][1]], match[0][0], match[0][1])
+		}
+		*inputPolymer = strings.Replace(*inputPolymer, (*inputPolymer)[match[0][0]:match[0][1]], "", 1)
+		if Debug2 {
+			fmt.Printf("New string is %s\n", *inputPolymer)
+		}
+	}
+	if Debug2 {
+		fmt.Printf("Final string is %s\n", *inputPolymer)
+	}
+}
+
+// Shortest - the length of the shortest polymer you can get by removing one
+// unit type (both polarities) from +polymer+ and reacting what's left
+func Shortest(polymer string) int {
+	shortestReduction := len(polymer)
+	for _, letterToCut := range partBList {
+		candidate := strings.Replace(polymer, letterToCut, "", -1)
+		candidate = strings.Replace(candidate, strings.ToUpper(letterToCut), "", -1)
+		React(&candidate)
+		if len(candidate) < shortestReduction {
+			shortestReduction = len(candidate)
+		}
+	}
+	return shortestReduction
+}
+
+// read - the polymer in +r+, reacted
+func read(r io.Reader) (string, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	polymer := strings.TrimSpace(string(text))
+	React(&polymer)
+	return polymer, nil
+}
+
+// PartA - how many units are left after the polymer reacts
+func PartA(r io.Reader) (string, error) {
+	polymer, err := read(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(len(polymer)), nil
+}
+
+// PartB - the shortest polymer left after removing one unit type
+func PartB(r io.Reader) (string, error) {
+	polymer, err := read(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(Shortest(polymer)), nil
+}
@@ -0,0 +1,222 @@
+// Package day08 - 2018 day 8: summing the navigation system's licence tree
+package day08
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2018, 8, PartA, PartB))
+}
+
+// Debug - print the working
+var Debug = false
+
+const (
+	parseChildren int = iota
+	metadataCount
+	metadataRead
+)
+
+// Node - a node of the licence tree
+type Node struct {
+	Children       []*Node
+	MetadataCount  int // number of metadata entries in total
+	Metadata       []int
+	Parent         *Node
+	MetadataToRead int // number of metadata entries left to read
+}
+
+// NewNode - an empty node
+func NewNode() *Node {
+	return &Node{
+		Children:       make([]*Node, 0),
+		Metadata:       make([]int, 0),
+		Parent:         nil,
+		MetadataCount:  0,
+		MetadataToRead: 0,
+	}
+}
+
+// SumTreeMetadata - sum up my metadata and my childrens'
+func (n *Node) SumTreeMetadata() int {
+	s := n.MetadataSum()
+	for _, c := range n.Children {
+		s += c.SumTreeMetadata()
+	}
+	return s
+}
+
+// MetadataSum - sum up all my metadata
+func (n *Node) MetadataSum() int {
+	s := 0
+	for _, v := range n.Metadata {
+		s += v
+	}
+	return s
+}
+
+// PartBSum - compute the part B sum for day 8
+func (n *Node) PartBSum() int {
+	if Debug {
+		fmt.Printf("Part B sum: %+v I have %d children ", n, len(n.Children))
+	}
+	s := 0
+	if len(n.Children) == 0 {
+		if Debug {
+			fmt.Printf(" and that means my value is my metadata sum %d\n", n.MetadataSum())
+		}
+		s = n.MetadataSum()
+	} else {
+		if Debug {
+			fmt.Printf("And that means my sum is the sum of my children at my indexes: %d\n", n.Metadata)
+		}
+		for _, i := range n.Metadata {
+			if Debug {
+				fmt.Printf("  metadata value: %d\n", i)
+			}
+			// okay to use len() and i here like this since they're both 1-based
+			// however, in indexing into our Children slice we need to (i-1)
+			if len(n.Children) >= i {
+				s += n.Children[i-1].PartBSum()
+			}
+		}
+	}
+	return s
+}
+
+// recursively parse the input
+// *i needs to start as -1
+func parseInputIntoNodes(root *Node, i *int, dataSlice []int) {
+	if Debug {
+		fmt.Printf("data %d\n", dataSlice)
+	}
+	state := parseChildren
+	for *i+1 < len(dataSlice) {
+		// first thing's first, advance the counter
+		*i++
+		if Debug {
+			fmt.Println()
+			fmt.Printf("[%5d/%5d], d=%d, state=%d", *i, len(dataSlice)-1, dataSlice[*i], state)
+		}
+
+		switch state {
+		case parseChildren:
+			// Allocate room for my children
+			if Debug {
+				fmt.Printf(" parseChildren: Allocating %d Children for (%p) root %+v\n", dataSlice[*i], root, root)
+			}
+			root.Children = make([]*Node, dataSlice[*i])
+			// and now, add "stubs" for my children
+			for c := range root.Children {
+				n := NewNode()
+				n.Parent = root
+				root.Children[c] = n
+			}
+			state = metadataCount
+		case metadataCount:
+			// I have this many metadata entries in total
+			if Debug {
+				fmt.Printf(" metadataCount: I have to read %d entries\n", dataSlice[*i])
+			}
+			root.MetadataCount = dataSlice[*i]
+			root.MetadataToRead = dataSlice[*i]
+			// Allocate room for my metadata entries
+			root.Metadata = make([]int, dataSlice[*i])
+
+			// What should I do next? If I have children I need to read them in before I
+			// read my own metadata
+			if len(root.Children) > 0 {
+				// I should loop through my kids to read them in
+				if Debug {
+					fmt.Printf("   metadataCount: %d children to read, so looping...\n", len(root.Children))
+				}
+				for _, child := range root.Children {
+					// before passing control to the next iteration, need to advance to the next
+					// item in the dataSlice
+					if Debug {
+						fmt.Printf("   metadataCount: About to pass control for (%p) child %+v\n", child, child)
+					}
+					parseInputIntoNodes(child, i, dataSlice)
+					if Debug {
+						fmt.Printf("   metadataCount: Finished processing (%p) child %+v\n", child, child)
+					}
+					// the final child will fall through the select statement and advance *i
+					// afterwards.
+				}
+			}
+			// At this point one of two things is true:
+			// 1) I have finished reading all of my children and now need to read my own metadata
+			// 2) I never had any children and now need to read my own metadata
+			state = metadataRead
+		case metadataRead:
+			if Debug {
+				fmt.Printf(" metadataRead: Reading metadata value %d\n", dataSlice[*i])
+			}
+			// read metadata for myself
+			root.Metadata[root.MetadataCount-root.MetadataToRead] = dataSlice[*i]
+			root.MetadataToRead--
+			// Do I have more metadata?
+			if Debug {
+				fmt.Printf("   metadataRead: More to go if MetadataToRead > 0 (%d > 0)\n", root.MetadataToRead)
+			}
+			if root.MetadataToRead > 0 {
+				// More metadata to read
+				// (this state "change" isn't strictly necessary but it will help readability.)
+				state = metadataRead
+			} else {
+				// no more metadata for myself, so I need to break out of the for loop.
+				return
+			}
+		}
+	}
+}
+
+// Parse - the tree of numbers in +r+
+func Parse(r io.Reader) (*Node, error) {
+	lineReader := bufio.NewScanner(r)
+	lineReader.Split(bufio.ScanWords)
+
+	dataset := make([]int, 0)
+	for lineReader.Scan() {
+		d, err := strconv.Atoi(lineReader.Text())
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse a number: %w", err)
+		}
+		dataset = append(dataset, d)
+	}
+	if err := lineReader.Err(); err != nil {
+		return nil, err
+	}
+	// must be -1 due to pre-increment
+	i := -1
+	root := NewNode()
+	parseInputIntoNodes(root, &i, dataset)
+	if i != len(dataset)-1 || root.MetadataToRead > 0 {
+		return nil, fmt.Errorf("the tree ends after %d of %d numbers", i+1, len(dataset))
+	}
+	return root, nil
+}
+
+// PartA - the sum of every node's metadata
+func PartA(r io.Reader) (string, error) {
+	root, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(root.SumTreeMetadata()), nil
+}
+
+// PartB - the root node's value
+func PartB(r io.Reader) (string, error) {
+	root, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(root.PartBSum()), nil
+}
@@ -0,0 +1,195 @@
+// Package day09 - 2018 day 9: the elves' marble game
+package day09
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2018, 9, PartA, PartB))
+}
+
+// InitCircle - Start the loop
+func InitCircle() *Marble {
+	r := NewMarble(0)
+	r.Left = r
+	r.Right = r
+	return r
+}
+
+// NewMarble - make a new Marble
+func NewMarble(v int) *Marble {
+	return &Marble{
+		Value: v,
+		Left:  nil,
+		Right: nil,
+	}
+}
+
+// Marble - the Marble object; a doubly linked list.
+type Marble struct {
+	Right *Marble // clockwise
+	Left  *Marble // counterclockwise
+	Value int     // value of this marble
+}
+
+// Move - Traverse +offset+ Marbles.
+// If +offset+ is positive, move that many to the right (clockwise)
+// If +offset+ is negative, move that many to the left (counter-clockwise)
+func (m *Marble) Move(offset int) *Marble {
+	ret := m
+	if offset == 0 {
+		return ret
+	}
+	for i := 0; i < int(math.Abs(float64(offset))); i++ {
+		if offset < 0 {
+			ret = ret.Left
+			//fmt.Printf("Moving left -> %+v\n", ret)
+		} else {
+			ret = ret.Right
+			//fmt.Printf("Moving right -> %+v\n", ret)
+		}
+	}
+	return ret
+}
+
+// Delete - Delete the current Marble; its Value is returned. Left and Right associations are carried over
+func (m *Marble) Delete() int {
+	ret := m.Value
+	l := m.Left
+	r := m.Right
+	r.Left = l
+	l.Right = r
+	return ret
+}
+
+// InsertRight - insert a Marble with value +v+ to the Right (clockwise) of myself. Return the new Marble
+// new Marble is to my right
+func (m *Marble) InsertRight(v int) *Marble {
+	n := NewMarble(v)
+
+	// new marble and old "right of me"
+	m.Right.Left = n
+	n.Right = m.Right
+
+	n.Left = m
+	m.Right = n
+
+	return n
+}
+
+// InsertLeft - insert a Marble with value +v+ to the Left (counterclockwise) of myself. Return the new Marble.
+func (m *Marble) InsertLeft(v int) *Marble {
+	n := NewMarble(v)
+
+	m.Left.Right = n
+	n.Left = m.Left
+
+	m.Left = n
+	n.Right = m
+
+	return n
+}
+
+// PrintCircle - Print a representation of the Marble circle
+func PrintCircle(current *Marble) {
+	currentIntVal := current.Value
+	currentPtrVal := current
+
+	for {
+		current = current.Right
+		if current.Value == currentIntVal {
+			fmt.Printf("(%d)\n", current.Value)
+			break
+		} else {
+			fmt.Printf("%d ", current.Value)
+		}
+	}
+	current = currentPtrVal
+}
+
+// Play - play the game with +players+ until +lastMarbleValue+ is placed and
+// return the scores
+func Play(players, lastMarbleValue int) map[int]int {
+	current := InitCircle()
+
+	// player -> score
+	score := make(map[int]int)
+
+	var del *Marble
+	for v := 1; v <= lastMarbleValue; v++ {
+		if v%23 == 0 {
+			del = current.Move(-7)
+			current = del.Right
+			score[v%players] += v + del.Delete()
+		} else {
+			current = current.Right.InsertRight(v)
+		}
+	}
+	return score
+}
+
+// HighScore - the winning score in +score+
+func HighScore(score map[int]int) int {
+	highScore := -1
+	for i := range score {
+		if score[i] > highScore {
+			highScore = score[i]
+		}
+	}
+	return highScore
+}
+
+// Parse - the player count and last marble value from
+// "9 players; last marble is worth 25 points"
+func Parse(r io.Reader) (players, lastMarbleValue int, err error) {
+	lineReader := bufio.NewScanner(r)
+	if !lineReader.Scan() {
+		if err := lineReader.Err(); err != nil {
+			return 0, 0, err
+		}
+		return 0, 0, errors.New("empty input")
+	}
+	words := strings.Fields(lineReader.Text())
+	if len(words) < 7 {
+		return 0, 0, fmt.Errorf("couldn't parse %q", lineReader.Text())
+	}
+	players, err = strconv.Atoi(words[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("couldn't parse the number of players: %w", err)
+	}
+	lastMarbleValue, err = strconv.Atoi(words[6])
+	if err != nil {
+		return 0, 0, fmt.Errorf("couldn't parse last marble score: %w", err)
+	}
+	if players < 1 {
+		return 0, 0, fmt.Errorf("need at least one player, not %d", players)
+	}
+	return players, lastMarbleValue, nil
+}
+
+// PartA - the winning score
+func PartA(r io.Reader) (string, error) {
+	players, lastMarbleValue, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(HighScore(Play(players, lastMarbleValue))), nil
+}
+
+// PartB - the winning score when the last marble is 100 times larger
+func PartB(r io.Reader) (string, error) {
+	players, lastMarbleValue, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(HighScore(Play(players, lastMarbleValue*100))), nil
+}
@@ -0,0 +1,275 @@
+// Package day10 - 2018 day 10: the message the moving points of light spell
+package day10
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2018, 10, PartA, PartB))
+}
+
+// Iterations - how many seconds Candidates looks through
+const Iterations = 12000
+
+var (
+	// Debug - print the working
+	Debug = false
+
+	lineParser = regexp.MustCompile(`position=<\s?(-?\d+),\s{1,}(-?\d+)> velocity=<\s?(-?\d+),\s{1,}(-?\d+)>.*`)
+)
+
+// Point - has current (X,Y) position and a X and Y component velocity
+type Point struct {
+	X, Y                 int
+	XVelocity, YVelocity int
+}
+
+// Copy - a copy of +p+
+func (p *Point) Copy() *Point {
+	return &Point{
+		X: p.X, Y: p.Y,
+		XVelocity: p.XVelocity, YVelocity: p.YVelocity,
+	}
+}
+
+// EqualTo - equality test, based on (X,Y) comparison
+func (p *Point) EqualTo(o *Point) bool {
+	return ((p.X == o.X) && (p.Y == o.Y))
+}
+
+// Field - the field containing all the +Point+s
+type Field struct {
+	Points []*Point
+}
+
+// Copy - a deep copy of +f+
+func (f *Field) Copy() *Field {
+	ret := &Field{
+		Points: make([]*Point, len(f.Points)),
+	}
+	for i := 0; i < len(f.Points); i++ {
+		ret.Points[i] = f.Points[i].Copy()
+	}
+	return ret
+}
+
+// AddPoint to the Field
+func (f *Field) AddPoint(x, y, xvel, yvel int) {
+	f.Points = append(f.Points, &Point{
+		X:         x,
+		Y:         y,
+		XVelocity: xvel,
+		YVelocity: yvel,
+	})
+}
+
+// Advance - move forward one tick
+func (f *Field) Advance() {
+	for _, point := range f.Points {
+		point.X += point.XVelocity
+		point.Y += point.YVelocity
+	}
+}
+
+// CountOverlaps - how many Points overlap? Compare each point to each other. This could be used to find a relative minimum
+func (f *Field) CountOverlaps() int {
+	overlaps := 0
+	for i, op := range f.Points {
+		for j, ip := range f.Points {
+			if i == j {
+				continue
+			} else {
+				if op.EqualTo(ip) {
+					overlaps++
+				}
+			}
+		}
+	}
+	return overlaps
+}
+
+// Draw - Render the Points to an image based on the iteration
+// returns (filename, error)
+func (f *Field) Draw(i int) (string, error) {
+	filename := fmt.Sprintf("day10a-iteration-%d.png", i)
+	minX, minY, maxX, maxY := f.Bounds()
+	if Debug {
+		fmt.Printf("i=%d Image ranges: (%d,%d) -> (%d,%d)\n", i, minX-40, minY-40, 2*maxX, 2*maxY)
+	}
+
+	img := image.NewRGBA(image.Rect(minX-40, minY-40, 2*maxX, 2*maxY))
+
+	for _, point := range f.Points {
+		if Debug {
+			fmt.Printf("  i=%d: Plotting (%d,%d)\n", i, point.X, point.Y)
+		}
+		img.Set(point.X, point.Y, color.RGBA{0, 0, 0, 255})
+	}
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		return "", err
+	}
+	return filename, nil
+
+}
+
+// NewField - an empty Field
+func NewField() *Field {
+	return &Field{
+		Points: make([]*Point, 0),
+	}
+}
+
+// Bounds - the smallest rectangle holding every Point
+func (f *Field) Bounds() (minX, minY, maxX, maxY int) {
+	minX, minY = f.Points[0].X, f.Points[0].Y
+	maxX, maxY = minX, minY
+	for _, point := range f.Points {
+		minX = min(minX, point.X)
+		minY = min(minY, point.Y)
+		maxX = max(maxX, point.X)
+		maxY = max(maxY, point.Y)
+	}
+	return minX, minY, maxX, maxY
+}
+
+// area - the size of the Bounds, which shrinks as the points come together
+func (f *Field) area() int {
+	minX, minY, maxX, maxY := f.Bounds()
+	return (maxX - minX + 1) * (maxY - minY + 1)
+}
+
+// String - the Points as rows of # (a point) and . (no point)
+func (f *Field) String() string {
+	minX, minY, maxX, maxY := f.Bounds()
+	lit := make(map[[2]int]bool, len(f.Points))
+	for _, point := range f.Points {
+		lit[[2]int{point.X, point.Y}] = true
+	}
+	var b strings.Builder
+	for y := minY; y <= maxY; y++ {
+		if y > minY {
+			b.WriteByte('\n')
+		}
+		for x := minX; x <= maxX; x++ {
+			if lit[[2]int{x, y}] {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+	}
+	return b.String()
+}
+
+// Candidates - advance +f+ +iterations+ times, keeping a copy of it every
+// time more Points overlap than ever before. The message is one of them.
+func Candidates(f *Field, iterations int) map[int]*Field {
+	fields := make(map[int]*Field)
+	highscore := f.CountOverlaps()
+	if Debug {
+		fmt.Printf("0 ")
+	}
+	for i := 1; i < iterations; i++ {
+		f.Advance()
+		overlaps := f.CountOverlaps()
+
+		if overlaps > highscore {
+			highscore = overlaps
+			if Debug {
+				fmt.Printf("new highscore: %d -> %d\n", i, highscore)
+			}
+			fields[i] = f.Copy()
+		}
+		if Debug {
+			fmt.Printf(".")
+			if i%200 == 0 {
+				fmt.Printf("\n%d ", i)
+			}
+		}
+	}
+	return fields
+}
+
+// Converge - advance +f+ until its Points are closest together, which is when
+// they spell the message. Returns how many seconds that took.
+func Converge(f *Field) int {
+	seconds := 0
+	for area := f.area(); ; seconds++ {
+		next := f.Copy()
+		next.Advance()
+		nextArea := next.area()
+		if nextArea >= area {
+			return seconds
+		}
+		*f, area = *next, nextArea
+	}
+}
+
+// Parse - the Points in +r+
+func Parse(r io.Reader) (*Field, error) {
+	lineReader := bufio.NewScanner(r)
+	field := NewField()
+	for lineReader.Scan() {
+		line := lineReader.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		matches := lineParser.FindStringSubmatch(line)
+		if matches == nil {
+			return nil, fmt.Errorf("couldn't parse %q", line)
+		}
+		var v [4]int
+		for i := range v {
+			n, err := strconv.Atoi(matches[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %q: %w", line, err)
+			}
+			v[i] = n
+		}
+		field.AddPoint(v[0], v[1], v[2], v[3])
+	}
+	if err := lineReader.Err(); err != nil {
+		return nil, err
+	}
+	if len(field.Points) == 0 {
+		return nil, errors.New("no points in the input")
+	}
+	return field, nil
+}
+
+// PartA - the message, drawn with # and .
+func PartA(r io.Reader) (string, error) {
+	field, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	Converge(field)
+	return field.String(), nil
+}
+
+// PartB - how many seconds until the message appears
+func PartB(r io.Reader) (string, error) {
+	field, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(Converge(field)), nil
+}
@@ -0,0 +1,189 @@
+// Package day11 - 2018 day 11: the most powerful square of fuel cells
+package day11
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.WithInput(2018, 11, "1308", PartA, PartB))
+}
+
+// Size - the grid is Size cells square
+const Size = 300
+
+// FuelCell - the SizexSize grid of Cells
+type FuelCell struct {
+	Cells map[int]map[int]*Cell
+	// sums[x][y] - the power of every Cell up to and including (x,y); row and
+	// column 0 are 0 so PowerNFromXY doesn't need to special case the edges
+	sums [Size + 1][Size + 1]int
+}
+
+// FindBestPowerBlock - the top left Cell and size of the square with the most
+// power, of any size
+func (fc *FuelCell) FindBestPowerBlock() (*Cell, int) {
+	type BestBlock struct {
+		cell  *Cell // Cell ID
+		score int   // power of this range
+		size  int   // Size of square out (n=0 is just the cell)
+	}
+
+	best := BestBlock{
+		cell:  fc.GetCellByXY(1, 1),
+		score: fc.GetCellByXY(1, 1).Power,
+		size:  0,
+	}
+	for x := 1; x <= Size; x++ {
+		for y := 1; y <= Size; y++ {
+			for n := 0; ; n++ {
+				if x+n > Size || y+n > Size {
+					break
+				}
+				nBlockScore, err := fc.PowerNFromXY(x, y, n)
+
+				if err != nil {
+					// the proposed block out of the bounds? then w should move to the next x,y pair
+					// and try again
+					break
+				}
+
+				if nBlockScore > best.score {
+					best.cell = fc.GetCellByXY(x, y)
+					best.score = nBlockScore
+					best.size = n
+				}
+
+			}
+		}
+	}
+	return best.cell, best.size
+}
+
+// PowerNFromXY - Compute the power level n units out in each direction (eg a
+// square) from (x,y). If n will exceed the bounds of the FuelCell (eg SizexSize)
+// a meaningless power level will be returned as well as an error.
+// n can be 0, and if it is, it's the power of (x,y)
+// n>0 means go to the right (increase x) and go down (increase y) by the same amount
+func (fc *FuelCell) PowerNFromXY(x, y, n int) (int, error) {
+	power := 0
+	if n == 0 {
+		return fc.GetCellByXY(x, y).Power, nil
+	} else if n < 0 {
+		return 0, errors.New("n must be >0")
+	} else if x+n > Size || y+n > Size {
+		return 0, errors.New("out of bounds")
+	}
+
+	// the square's corners in the summed area table: everything up to the far
+	// corner, less what's above and to the left of the square
+	power = fc.sums[x+n-1][y+n-1] - fc.sums[x-1][y+n-1] - fc.sums[x+n-1][y-1] + fc.sums[x-1][y-1]
+	return power, nil
+}
+
+// GetCellByXY - the Cell at (+x+,+y+)
+func (fc *FuelCell) GetCellByXY(x, y int) *Cell {
+	return fc.Cells[x][y]
+}
+
+// InitFuelCell - the grid for grid serial number +serial+
+func InitFuelCell(serial int) *FuelCell {
+	f := make(map[int]map[int]*Cell)
+	for x := 1; x <= Size; x++ {
+		f[x] = make(map[int]*Cell)
+		for y := 1; y <= Size; y++ {
+			f[x][y] = NewCell(x, y, serial)
+		}
+	}
+	fc := &FuelCell{
+		Cells: f,
+	}
+	for x := 1; x <= Size; x++ {
+		for y := 1; y <= Size; y++ {
+			fc.sums[x][y] = f[x][y].Power + fc.sums[x-1][y] + fc.sums[x][y-1] - fc.sums[x-1][y-1]
+		}
+	}
+	return fc
+}
+
+// Cell - one fuel cell
+type Cell struct {
+	X, Y   int
+	Serial int // the grid serial number
+	Power  int
+}
+
+// NewCell - the Cell at (+x+,+y+) in the grid with serial number +serial+
+func NewCell(x, y, serial int) *Cell {
+	c := &Cell{
+		X:      x,
+		Y:      y,
+		Serial: serial,
+	}
+	c.Power = c.PowerLevel()
+	return c
+}
+
+// RackID - the ID of the rack the cell is in
+func (c *Cell) RackID() int {
+	return (c.X + 10)
+}
+
+// PowerLevel - get this cell's power level if we don't have it already
+func (c *Cell) PowerLevel() int {
+	return (((((c.RackID() * c.Y) + c.Serial) * c.RackID() / 100) % 10) - 5)
+}
+
+// BestSquare - the top left Cell of the +n+ by +n+ square with the most
+// power, and its power
+func (fc *FuelCell) BestSquare(n int) (*Cell, int) {
+	highScoreCell := fc.GetCellByXY(1, 1)
+	highScore := highScoreCell.Power
+	for x := 1; x <= Size; x++ {
+		for y := 1; y <= Size; y++ {
+			power, err := fc.PowerNFromXY(x, y, n)
+			if err != nil {
+				break
+			}
+			if power > highScore {
+				highScoreCell = fc.GetCellByXY(x, y)
+				highScore = power
+			}
+		}
+	}
+	return highScoreCell, highScore
+}
+
+// Parse - the grid serial number in +r+
+func Parse(r io.Reader) (int, error) {
+	var serial int
+	if _, err := fmt.Fscan(r, &serial); err != nil {
+		return 0, fmt.Errorf("couldn't read the grid serial number: %w", err)
+	}
+	return serial, nil
+}
+
+// PartA - "x,y" of the most powerful 3x3 square
+func PartA(r io.Reader) (string, error) {
+	serial, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	cell, _ := InitFuelCell(serial).BestSquare(3)
+	return fmt.Sprintf("%d,%d", cell.X, cell.Y), nil
+}
+
+// PartB - "x,y,size" of the most powerful square of any size
+func PartB(r io.Reader) (string, error) {
+	serial, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	cell, size := InitFuelCell(serial).FindBestPowerBlock()
+	// size 0 is the lone cell, which is the 1x1 square
+	return fmt.Sprintf("%d,%d,%d", cell.X, cell.Y, max(size, 1)), nil
+}
@@ -0,0 +1,341 @@
+// Package day12 - 2018 day 12: the sum of the pots with plants in after the
+// plants spread
+package day12
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+
+	"aoc"
+	"cycle"
+)
+
+func init() {
+	aoc.Register(aoc.New(2018, 12, PartA, PartB))
+}
+
+const (
+	// GenerationsA - how many generations part A grows
+	GenerationsA = 20
+	// GenerationsB - how many generations part B grows
+	GenerationsB = 50000000000
+)
+
+var (
+	// Debug - print the working
+	Debug = false
+
+	inputMatcher = regexp.MustCompile(`(?m:^(initial state: (.*))|^(.*) => (.*)$)`)
+)
+
+// Rule matches: five states, each of which may be t/f
+// result is t for flower result, f for no flower
+type Rule struct {
+	pattern []bool // pattern to match: LLCRR
+	result  bool   // result of pattern match
+}
+
+// String - the rule as it appears in the input
+func (r *Rule) String() string {
+	ret := ""
+
+	for _, v := range r.pattern {
+		if v {
+			ret += "#"
+		} else {
+			ret += "."
+		}
+	}
+	ret += " => "
+	if r.result {
+		ret += "#"
+	} else {
+		ret += "."
+	}
+	return ret
+}
+
+// Apply - apply this rule to the given pot with its neighbours
+// +potWithNeighbours+ is the Current pot (`C`) with two pots on either side
+// `LL` to the left, `RR` to the right, thus, LLCRR
+// -1 is false, 0 is the given pot didn't match this rule (eg pass), 1 is true
+func (r *Rule) Apply(potWithNeighbours []bool) int {
+	matches := true
+	for i := 0; i <= 4 && matches; i++ {
+		matches = matches && (r.pattern[i] == potWithNeighbours[i])
+	}
+
+	if matches {
+		switch r.result {
+		case true:
+			return 1
+		case false:
+			return -1
+		}
+	}
+	return 0
+}
+
+// NewRule - return a rule
+func NewRule(r []bool, result bool) *Rule {
+	return &Rule{
+		pattern: r,
+		result:  result,
+	}
+}
+
+// getMinMaxPot - the lowest and highest pot numbers in +pots+
+func getMinMaxPot(pots *map[int]bool) (int, int) {
+	var min, max int
+
+	for p := range *pots {
+		if p > max {
+			max = p
+		}
+		if p < min {
+			min = p
+		}
+	}
+	return min, max
+}
+
+// Plants - the pots with plants in, trimmed so the first and last pots have
+// one
+type Plants struct {
+	Offset int    // pot number of Pots[0]
+	Pots   []bool // has a plant?
+}
+
+// NewPlants - plants from a map of pot number => has a plant
+func NewPlants(state map[int]bool) Plants {
+	min, max := getMinMaxPot(&state)
+	pots := make([]bool, max-min+1)
+	for p, plant := range state {
+		pots[p-min] = plant
+	}
+	return Plants{Offset: min, Pots: pots}.trim()
+}
+
+// trim - drop empty pots from both ends
+func (p Plants) trim() Plants {
+	start, end := 0, len(p.Pots)
+	for start < end && !p.Pots[start] {
+		start++
+	}
+	for end > start && !p.Pots[end-1] {
+		end--
+	}
+	return Plants{Offset: p.Offset + start, Pots: p.Pots[start:end]}
+}
+
+// has - is there a plant in pot number +pot+?
+func (p Plants) has(pot int) bool {
+	i := pot - p.Offset
+	return i >= 0 && i < len(p.Pots) && p.Pots[i]
+}
+
+// Next - the following generation under +rules+, as a new Plants
+func (p Plants) Next(rules []*Rule) Plants {
+	// a pot more than two away from any plant stays empty
+	next := Plants{Offset: p.Offset - 2, Pots: make([]bool, len(p.Pots)+4)}
+	neighbourPots := make([]bool, 5)
+	for i := range next.Pots {
+		pot := next.Offset + i
+		for j := range neighbourPots {
+			neighbourPots[j] = p.has(pot + j - 2)
+		}
+		for _, rule := range rules {
+			if result := rule.Apply(neighbourPots); result != 0 {
+				next.Pots[i] = result == 1
+				break
+			}
+		}
+	}
+	return next.trim()
+}
+
+// Sum - the pot numbers with plants, added up
+func (p Plants) Sum() int {
+	sum := 0
+	for i, plant := range p.Pots {
+		if plant {
+			sum += p.Offset + i
+		}
+	}
+	return sum
+}
+
+// samePattern - do +a+ and +b+ have the same plants, wherever they are?
+func samePattern(a, b Plants) bool {
+	if len(a.Pots) != len(b.Pots) {
+		return false
+	}
+	for i := range a.Pots {
+		if a.Pots[i] != b.Pots[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse - the initial state (pot number => has a plant?) and the rules in +r+
+func Parse(r io.Reader) (map[int]bool, []*Rule, error) {
+	inputBuffer, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// pot number => has a flower?
+	state := make(map[int]bool)
+	rules := make([]*Rule, 0)
+
+	matches := inputMatcher.FindAllStringSubmatch(string(inputBuffer), -1)
+	if len(matches) == 0 || matches[0][1] == "" {
+		return nil, nil, errors.New("no initial state in the input")
+	}
+	for i := 0; i < len(matches[0][2]); i++ {
+		switch matches[0][2][i] {
+		case '#':
+			state[i] = true
+		case '.':
+			// no need to set false, it is the default
+		default:
+			return nil, nil, fmt.Errorf("no idea what %s is in the initial state", string(matches[0][2][i]))
+		}
+	}
+
+	for r := 1; r < len(matches); r++ {
+		if len(matches[r][3]) != 5 || len(matches[r][4]) != 1 {
+			return nil, nil, fmt.Errorf("couldn't parse rule %d: %q", r-1, matches[r][0])
+		}
+		rule := make([]bool, 5)
+		var ruleResult bool
+		for i := 0; i < 5; i++ {
+			switch matches[r][3][i] {
+			case '#':
+				rule[i] = true
+			case '.':
+				rule[i] = false
+			default:
+				return nil, nil, fmt.Errorf("no idea what %s is in rule %d", string(matches[r][3][i]), r-1)
+			}
+		}
+		switch matches[r][4][0] {
+		case '#':
+			ruleResult = true
+		case '.':
+			ruleResult = false
+		default:
+			return nil, nil, fmt.Errorf("no idea what %s means in rule result for rule %d", string(matches[r][4][0]), r-1)
+		}
+		rules = append(rules, NewRule(rule, ruleResult))
+	}
+	return state, rules, nil
+}
+
+// Grow - the state after +generationCount+ generations under +rules+,
+// starting from +state+
+func Grow(state map[int]bool, rules []*Rule, generationCount int) map[int]bool {
+	minPotID, maxPotID := getMinMaxPot(&state)
+
+	// this wants to create the next generation based on the old generation.
+	// loop through current, apply rules to pots [n-2..n+2] and the result of that becomes the next gen
+	// will need to maintain the pot ID of the extrema to avoid searching the entire keyspace
+	for g := 0; g < generationCount; g++ {
+
+		nextGenState := make(map[int]bool)
+		stop := maxPotID + 2
+		for p := minPotID - 2; p < stop; p++ {
+			var hasPot int
+			neighbourPots := make([]bool, 5)
+
+			neighbourPots[2] = state[p]
+			neighbourPots[1] = state[p-1]
+			neighbourPots[0] = state[p-2]
+			neighbourPots[3] = state[p+1]
+			neighbourPots[4] = state[p+2]
+			for _, rule := range rules {
+				hasPot = rule.Apply(neighbourPots)
+
+				switch hasPot {
+				case -1:
+					// next generation state for this is false
+					break
+				case 1:
+					// have a plant
+					nextGenState[p] = true
+					if p < minPotID {
+						minPotID = p
+					}
+					if p > maxPotID {
+						maxPotID = p
+					}
+					break
+				case 0:
+					// didn't match this rule
+					continue
+				}
+			}
+		}
+		state = nextGenState
+
+	}
+	return state
+}
+
+// Sum - the pot numbers in +state+ with plants, added up
+func Sum(state map[int]bool) int {
+	sum := 0
+	for p, plant := range state {
+		if plant {
+			sum += p
+		}
+	}
+	return sum
+}
+
+// After - the plants after +generations+ generations under +rules+, however
+// many generations that is.
+//
+// The pattern settles into a loop that may drift along the row: find the
+// loop, then move the equivalent state in the first pass round it along by
+// however far the skipped passes would have drifted.
+func After(state map[int]bool, rules []*Rule, generations int) Plants {
+	initial := NewPlants(state)
+	next := func(p Plants) Plants { return p.Next(rules) }
+	mu, lambda := cycle.Brent(initial, next, samePattern)
+	loopStart := cycle.Nth(initial, next, mu, lambda, mu)
+	drift := next(loopStart)
+	for i := 1; i < lambda; i++ {
+		drift = next(drift)
+	}
+	plants := cycle.Nth(initial, next, mu, lambda, generations)
+	if generations > mu {
+		plants.Offset += (generations - mu) / lambda * (drift.Offset - loopStart.Offset)
+	}
+	if Debug {
+		fmt.Printf("Pattern repeats from generation %d every %d generations, moving %d pots each time\n", mu, lambda, drift.Offset-loopStart.Offset)
+	}
+	return plants
+}
+
+// PartA - the sum after GenerationsA generations
+func PartA(r io.Reader) (string, error) {
+	state, rules, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(Sum(Grow(state, rules, GenerationsA))), nil
+}
+
+// PartB - the sum after GenerationsB generations
+func PartB(r io.Reader) (string, error) {
+	state, rules, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(After(state, rules, GenerationsB).Sum()), nil
+}
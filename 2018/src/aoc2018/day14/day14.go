@@ -0,0 +1,179 @@
+// Package day14 - 2018 day 14: the elves' hot chocolate recipe scoreboard
+package day14
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.WithInput(2018, 14, "681901", PartA, PartB))
+}
+
+// rubyMod - +d+ mod +m+ with the sign of +m+, like Ruby's %
+func rubyMod(d, m int) int {
+	r := d % m
+	if (r < 0 && m > 0) || (r > 0 && m < 0) {
+		r += m
+	}
+	return r
+}
+
+// ScanForSequence - for the needle in the haystack. If it exists in the
+// haystack, return true.
+func ScanForSequence(needle, haystack *[]uint8, offset int) bool {
+	if len(*haystack) < len(*needle) {
+		return false
+	}
+
+	ret := true
+	j := 0
+	for i := len(*haystack) - len(*needle) - offset; i < len(*haystack) && j < len(*needle); i++ {
+		if i < 0 {
+			continue
+		}
+		ret = ret && (*haystack)[i] == (*needle)[j]
+		j++
+		if !ret {
+			break
+		}
+	}
+	return ret
+
+}
+
+// CountDigits - how many digits are in the given integer?
+func CountDigits(number int) int {
+	if number == 0 {
+		return 1
+	}
+	return int(math.Floor(math.Log10(math.Abs(float64(number)))) + 1)
+}
+
+// SplitDigits - Splits +number+ into its digit components.
+// The return will be in the same order
+// 681901 will be returned [6, 8, 1, 9, 0, 1]
+func SplitDigits(number int) []uint8 {
+	// we have nothing, so add nothing
+	if number == 0 {
+		return []uint8{0}
+	}
+	digitCount := CountDigits(number)
+	ret := make([]uint8, digitCount)
+
+	i := number
+	for d := 0; d < digitCount; d++ {
+		digit := i % 10
+		ret[digitCount-d-1] = uint8(digit)
+		i -= digit
+		i /= 10
+	}
+	return ret
+}
+
+// Scoreboard - the recipe scores and where the two elves are
+type Scoreboard struct {
+	Scores               []uint8
+	elf1Index, elf2Index int
+}
+
+// NewScoreboard - the scoreboard the elves start with
+func NewScoreboard() *Scoreboard {
+	return &Scoreboard{Scores: []uint8{3, 7}, elf1Index: 0, elf2Index: 1}
+}
+
+// Step - make new recipes from the elves' current two and move the elves on.
+// Returns how many recipes were added.
+func (s *Scoreboard) Step() int {
+	scores := s.Scores
+	digits := SplitDigits(int(scores[s.elf1Index] + scores[s.elf2Index]))
+	// append to scores
+	scores = append(scores, digits...)
+	// move forward
+	newElf1Idx := 1 + int(scores[s.elf1Index]) + s.elf1Index
+	newElf2Idx := 1 + int(scores[s.elf2Index]) + s.elf2Index
+	s.elf1Index = rubyMod(newElf1Idx, len(scores))
+	s.elf2Index = rubyMod(newElf2Idx, len(scores))
+	s.Scores = scores
+	return len(digits)
+}
+
+// TenAfter - the scores of the ten recipes after the first +n+
+func TenAfter(n int) string {
+	s := NewScoreboard()
+	for len(s.Scores) < n+10 {
+		s.Step()
+	}
+	var b strings.Builder
+	for _, score := range s.Scores[n : n+10] {
+		b.WriteByte('0' + score)
+	}
+	return b.String()
+}
+
+// RecipesBefore - how many recipes there are before the scores +sequence+
+// (eg "51589") first appear
+func RecipesBefore(sequence string) (int, error) {
+	if sequence == "" {
+		return 0, errors.New("no sequence to look for")
+	}
+	needle := make([]uint8, len(sequence))
+	for i, r := range sequence {
+		n, err := strconv.ParseInt(string(r), 10, 0)
+		if err != nil {
+			return 0, fmt.Errorf("couldn't parse %q: %w", sequence, err)
+		}
+		needle[i] = uint8(n)
+	}
+	s := NewScoreboard()
+	for {
+		added := s.Step()
+		// a new recipe might finish the sequence, so check ending at each one
+		for offset := added - 1; offset >= 0; offset-- {
+			if ScanForSequence(&needle, &s.Scores, offset) {
+				return len(s.Scores) - len(needle) - offset, nil
+			}
+		}
+	}
+}
+
+// read - the puzzle input, trimmed
+func read(r io.Reader) (string, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// PartA - the scores of the ten recipes after the number in the input
+func PartA(r io.Reader) (string, error) {
+	text, err := read(r)
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return "", fmt.Errorf("couldn't parse %q: %w", text, err)
+	}
+	return TenAfter(n), nil
+}
+
+// PartB - how many recipes come before the input's scores
+func PartB(r io.Reader) (string, error) {
+	text, err := read(r)
+	if err != nil {
+		return "", err
+	}
+	n, err := RecipesBefore(text)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(n), nil
+}
@@ -0,0 +1,317 @@
+// Package day16 - 2018 day 16: working out the device's opcode numbers from
+// samples of what they do, then running its test program
+package day16
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aoc"
+	"github.com/logrusorgru/aurora"
+	"opvm"
+)
+
+func init() {
+	aoc.Register(aoc.WithPieces(2018, 16, []string{"day16-detection.txt", "day16-sample.txt"}, PartA, PartB))
+}
+
+var (
+	// Debug - print the working
+	Debug = false
+
+	detectionMatcher = regexp.MustCompile(`(?mU)Before:\s+\[(\d+),\s+(\d+),\s+(\d+),\s+(\d+)\]\n(\d+)\s+(\d+)\s+(\d+)\s+(\d+)\nAfter:\s+\[(\d+),\s+(\d+),\s+(\d+),\s+(\d+)\]`)
+)
+
+// printAndColourize - the name of +opid+, green if +t+ and red if not
+func printAndColourize(opid opvm.OpcodeId, t bool) string {
+	var ret string
+	if t {
+		ret = fmt.Sprintf("%s", aurora.Green(opvm.OpCodeTypeToString(opid)).Bold())
+	} else {
+		ret = fmt.Sprintf("%s", aurora.Red(opvm.OpCodeTypeToString(opid)).Bold())
+	}
+	return ret
+}
+
+// DetectionRecords - all of them
+type DetectionRecords struct {
+	Records []*DetectionRecord
+}
+
+// AddRecord - add a record
+func (dr *DetectionRecords) AddRecord(b1, b2, b3, b4, opcode, a, b, c, a1, a2, a3, a4 int) {
+	dr.Records = append(dr.Records, &DetectionRecord{
+		Before: [4]int{b1, b2, b3, b4},
+		Opcode: opcode,
+		A:      a, B: b, C: c,
+		After: [4]int{a1, a2, a3, a4},
+	})
+}
+
+// UnresolvedError - opcode numbers the records couldn't narrow down to a
+// single meaning
+type UnresolvedError struct {
+	Candidates map[int][]opvm.OpcodeId // what each unresolved number could still be
+}
+
+// Error - which numbers are unresolved and what they could be
+func (e *UnresolvedError) Error() string {
+	numbers := make([]int, 0, len(e.Candidates))
+	for number := range e.Candidates {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	desc := make([]string, len(numbers))
+	for i, number := range numbers {
+		names := make([]string, len(e.Candidates[number]))
+		for j, op := range e.Candidates[number] {
+			names[j] = opvm.OpCodeTypeToString(op)
+		}
+		if len(names) == 0 {
+			names = []string{"nothing (contradictory records)"}
+		}
+		desc[i] = fmt.Sprintf("%d could be %s", number, strings.Join(names, "/"))
+	}
+	return fmt.Sprintf("unresolved opcodes: %s", strings.Join(desc, "; "))
+}
+
+// Candidates - for each opcode number, the opcodes consistent with every
+// record using that number. Numbers with no records could be anything.
+func (dr *DetectionRecords) Candidates() map[int]map[opvm.OpcodeId]bool {
+	candidates := make(map[int]map[opvm.OpcodeId]bool)
+	for number := 0; number < opvm.NumOpcodes; number++ {
+		candidates[number] = make(map[opvm.OpcodeId]bool)
+		for op := opvm.OpcodeId(0); op < opvm.NumOpcodes; op++ {
+			candidates[number][op] = true
+		}
+	}
+	for _, record := range dr.Records {
+		if _, ok := candidates[record.Opcode]; !ok {
+			candidates[record.Opcode] = make(map[opvm.OpcodeId]bool)
+		}
+		for op := range candidates[record.Opcode] {
+			if !record.BehavesLike(op) {
+				delete(candidates[record.Opcode], op)
+			}
+		}
+	}
+	return candidates
+}
+
+// SolveOpcodes - work out which opcode each number means. Starts from
+// Candidates and propagates: a number with one candidate left claims it from
+// every other number, and an opcode which is a candidate for only one number
+// must be that number. Returns an *UnresolvedError if that doesn't settle
+// every number.
+func (dr *DetectionRecords) SolveOpcodes() (map[int]opvm.OpcodeId, error) {
+	candidates := dr.Candidates()
+	mapping := make(map[int]opvm.OpcodeId)
+
+	assign := func(number int, op opvm.OpcodeId) {
+		mapping[number] = op
+		for other := range candidates {
+			delete(candidates[other], op)
+		}
+		delete(candidates, number)
+	}
+
+	for progress := true; progress; {
+		progress = false
+		for number, ops := range candidates {
+			if len(ops) == 1 {
+				for op := range ops {
+					assign(number, op)
+				}
+				progress = true
+			}
+		}
+		// an opcode only one number could be
+		for op := opvm.OpcodeId(0); op < opvm.NumOpcodes; op++ {
+			only := -1
+			count := 0
+			for number, ops := range candidates {
+				if ops[op] {
+					only = number
+					count++
+				}
+			}
+			if count == 1 {
+				assign(only, op)
+				progress = true
+			}
+		}
+	}
+
+	if len(candidates) > 0 {
+		unresolved := &UnresolvedError{Candidates: make(map[int][]opvm.OpcodeId)}
+		for number, ops := range candidates {
+			list := make([]opvm.OpcodeId, 0, len(ops))
+			for op := range ops {
+				list = append(list, op)
+			}
+			sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+			unresolved.Candidates[number] = list
+		}
+		return mapping, unresolved
+	}
+	return mapping, nil
+}
+
+// DetectionRecord - A record of what was read
+// [4]Before{0,1,2,3} registers 0-3.
+// Opcode A B C = Instruction
+type DetectionRecord struct {
+	Before  [4]int // Registers
+	Opcode  int    // Opcode number as it appears in the sample
+	A, B, C int    // Inputs A & B, output C
+	After   [4]int // Registers
+}
+
+// BehavesLike - would running +op+ with this record's A, B and C on the
+// Before registers produce the After registers?
+func (d *DetectionRecord) BehavesLike(op opvm.OpcodeId) bool {
+	aReg, bReg := opvm.RegisterOperands(op)
+	inRange := func(r int) bool { return r >= 0 && r < len(d.Before) }
+	if (aReg && !inRange(d.A)) || (bReg && !inRange(d.B)) || !inRange(d.C) {
+		return false
+	}
+	registers := append([]int(nil), d.Before[:]...)
+	instruction := &opvm.Instruction{Opcode: op, A: d.A, B: d.B, C: d.C}
+	instruction.Execute(registers)
+	for i := range registers {
+		if registers[i] != d.After[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TryAll - does this record behave like 3 or more opcodes?
+func (d *DetectionRecord) TryAll() bool {
+	matches := 0
+	for op := opvm.OpcodeId(0); op < opvm.NumOpcodes; op++ {
+		match := d.BehavesLike(op)
+		if Debug {
+			instruction := &opvm.Instruction{Opcode: op, A: d.A, B: d.B, C: d.C}
+			fmt.Printf("\t%s: %s on %v gives %v?\n", printAndColourize(op, match), instruction, d.Before, d.After)
+		}
+		if match {
+			matches++
+		}
+	}
+	if Debug {
+		fmt.Printf("\tMatches = %d\n", matches)
+	}
+	return matches >= 3
+}
+
+// ParseDetectionRecords - every Before/instruction/After sample in +text+
+func ParseDetectionRecords(text string) (*DetectionRecords, error) {
+	matches := detectionMatcher.FindAllStringSubmatch(text, -1)
+	records := new(DetectionRecords)
+
+	for matchGroupIdx, match := range matches {
+		if Debug {
+			fmt.Printf("Match %d\n", matchGroupIdx)
+		}
+		var v [12]int
+		for i := range v {
+			n, err := strconv.Atoi(match[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse sample %d: %w", matchGroupIdx, err)
+			}
+			v[i] = n
+		}
+		records.AddRecord(v[0], v[1], v[2], v[3], v[4], v[5], v[6], v[7], v[8], v[9], v[10], v[11])
+	}
+	return records, nil
+}
+
+// SplitInput - the puzzle input is the samples followed by the test program:
+// returns the two halves. The program is whatever follows the last sample.
+func SplitInput(text string) (samples, program string) {
+	last := detectionMatcher.FindAllStringIndex(text, -1)
+	if len(last) == 0 {
+		return "", text
+	}
+	end := last[len(last)-1][1]
+	return text[:end], text[end:]
+}
+
+// ParseProgram - the test program in +text+, one "op a b c" per line with
+// the opcode numbers translated by +mapping+
+func ParseProgram(text string, mapping map[int]opvm.OpcodeId, registers int) (*opvm.Program, error) {
+	program := opvm.NewProgram(opvm.Config{Registers: registers, IPRegister: -1})
+	for _, line := range strings.Split(text, "\n") {
+		tokens := strings.Fields(line)
+		if len(tokens) == 0 {
+			continue
+		}
+		if len(tokens) != 4 {
+			return nil, fmt.Errorf("couldn't parse %q: expected op a b c", line)
+		}
+		var v [4]int
+		for i, token := range tokens {
+			n, err := strconv.Atoi(token)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %q: %w", line, err)
+			}
+			v[i] = n
+		}
+		opcode, ok := mapping[v[0]]
+		if !ok {
+			return nil, fmt.Errorf("no opcode %d in the samples", v[0])
+		}
+		program.AddInstruction(opcode, v[1], v[2], v[3])
+	}
+	if err := program.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid program: %w", err)
+	}
+	return program, nil
+}
+
+// PartA - how many samples behave like three or more opcodes
+func PartA(r io.Reader) (string, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	records, err := ParseDetectionRecords(string(text))
+	if err != nil {
+		return "", err
+	}
+	total := 0
+	for _, record := range records.Records {
+		if record.TryAll() {
+			total++
+		}
+	}
+	return strconv.Itoa(total), nil
+}
+
+// PartB - register 0 after running the test program
+func PartB(r io.Reader) (string, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	samples, programText := SplitInput(string(text))
+	records, err := ParseDetectionRecords(samples)
+	if err != nil {
+		return "", err
+	}
+	mapping, err := records.SolveOpcodes()
+	if err != nil {
+		return "", err
+	}
+	program, err := ParseProgram(programText, mapping, opvm.DefaultConfig.Registers)
+	if err != nil {
+		return "", err
+	}
+	program.Execute()
+	return strconv.Itoa(program.Registers[0]), nil
+}
@@ -0,0 +1,154 @@
+package day16
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"opvm"
+)
+
+// addSample - a record of +op+, numbered +number+, run on +before+
+func addSample(records *DetectionRecords, number int, op opvm.OpcodeId, before [4]int, a, b, c int) {
+	after := before
+	(&opvm.Instruction{Opcode: op, A: a, B: b, C: c}).Execute(after[:])
+	records.AddRecord(before[0], before[1], before[2], before[3], number, a, b, c, after[0], after[1], after[2], after[3])
+}
+
+// numberOf - the number the test records give +op+, so that they don't
+// just number the opcodes in order
+func numberOf(op opvm.OpcodeId) int {
+	return opvm.NumOpcodes - 1 - int(op)
+}
+
+// sampleOps - records enough to tell +ops+ apart from each other and from
+// every other opcode
+func sampleOps(records *DetectionRecords, ops ...opvm.OpcodeId) {
+	for _, op := range ops {
+		addSample(records, numberOf(op), op, [4]int{3, 2, 1, 1}, 2, 1, 2)
+		addSample(records, numberOf(op), op, [4]int{5, 7, 2, 9}, 0, 1, 3)
+		addSample(records, numberOf(op), op, [4]int{6, 3, 12, 0}, 2, 1, 0)
+		addSample(records, numberOf(op), op, [4]int{0, 4, 4, 1}, 1, 3, 2)
+		addSample(records, numberOf(op), op, [4]int{0, 1, 5, 5}, 2, 1, 3)
+	}
+}
+
+func TestSolveOpcodes(t *testing.T) {
+	records := new(DetectionRecords)
+	for op := opvm.OpcodeId(0); op < opvm.NumOpcodes; op++ {
+		sampleOps(records, op)
+	}
+	mapping, err := records.SolveOpcodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for op := opvm.OpcodeId(0); op < opvm.NumOpcodes; op++ {
+		if got := mapping[numberOf(op)]; got != op {
+			t.Errorf("%d is %s, want %s", numberOf(op), opvm.OpCodeTypeToString(got), opvm.OpCodeTypeToString(op))
+		}
+	}
+}
+
+func TestSolveOpcodesInput(t *testing.T) {
+	text, err := os.ReadFile("../../../inputs/day16-detection.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := ParseDetectionRecords(string(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapping, err := records.SolveOpcodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[opvm.OpcodeId]bool)
+	for number := 0; number < opvm.NumOpcodes; number++ {
+		op, ok := mapping[number]
+		if !ok {
+			t.Errorf("%d wasn't resolved", number)
+		} else if seen[op] {
+			t.Errorf("%d is %s, which another number already is", number, opvm.OpCodeTypeToString(op))
+		}
+		seen[op] = true
+	}
+	for i, record := range records.Records {
+		if op := mapping[record.Opcode]; !record.BehavesLike(op) {
+			t.Errorf("sample %d doesn't behave like %s, which %d was resolved to", i, opvm.OpCodeTypeToString(op), record.Opcode)
+		}
+	}
+}
+
+func TestSolveOpcodesAmbiguous(t *testing.T) {
+	records := new(DetectionRecords)
+	for op := opvm.OpcodeId(0); op < opvm.Eqri; op++ {
+		sampleOps(records, op)
+	}
+	// eqri and eqrr agree on this, and nothing else does, so the last two
+	// numbers could each be either
+	addSample(records, numberOf(opvm.Eqri), opvm.Eqri, [4]int{3, 0, 0, 3}, 0, 3, 2)
+	addSample(records, numberOf(opvm.Eqrr), opvm.Eqrr, [4]int{3, 0, 0, 3}, 0, 3, 2)
+
+	_, err := records.SolveOpcodes()
+	var unresolved *UnresolvedError
+	if !errors.As(err, &unresolved) {
+		t.Fatalf("got %v, want an UnresolvedError", err)
+	}
+	both := []opvm.OpcodeId{opvm.Eqri, opvm.Eqrr}
+	if len(unresolved.Candidates) != 2 ||
+		!slices.Equal(unresolved.Candidates[numberOf(opvm.Eqri)], both) || !slices.Equal(unresolved.Candidates[numberOf(opvm.Eqrr)], both) {
+		t.Errorf("unresolved %v, want 0 and 1 each eqri or eqrr", unresolved.Candidates)
+	}
+	if want := "unresolved opcodes: 0 could be eqri/eqrr; 1 could be eqri/eqrr"; err.Error() != want {
+		t.Errorf("error %q, want %q", err, want)
+	}
+}
+
+func TestSolveOpcodesContradictory(t *testing.T) {
+	records := new(DetectionRecords)
+	for op := opvm.OpcodeId(0); op < opvm.Eqri; op++ {
+		sampleOps(records, op)
+	}
+	// only eqri fits either number, so whichever claims it leaves the other
+	// with nothing
+	addSample(records, numberOf(opvm.Eqri), opvm.Eqri, [4]int{3, 0, 0, 4}, 0, 3, 2)
+	addSample(records, numberOf(opvm.Eqrr), opvm.Eqri, [4]int{3, 0, 0, 4}, 0, 3, 2)
+
+	mapping, err := records.SolveOpcodes()
+	var unresolved *UnresolvedError
+	if !errors.As(err, &unresolved) {
+		t.Fatalf("got %v, want an UnresolvedError", err)
+	}
+	if len(unresolved.Candidates) != 1 {
+		t.Fatalf("unresolved %v, want one number with no candidates", unresolved.Candidates)
+	}
+	for number, ops := range unresolved.Candidates {
+		if (number != 0 && number != 1) || len(ops) != 0 {
+			t.Errorf("unresolved %d could be %v, want 0 or 1 with no candidates", number, ops)
+		}
+		if other := 1 - number; mapping[other] != opvm.Eqri {
+			t.Errorf("%d is %s, want eqri", other, opvm.OpCodeTypeToString(mapping[other]))
+		}
+	}
+	if !strings.Contains(err.Error(), "could be nothing (contradictory records)") {
+		t.Errorf("error %q doesn't say the records contradict each other", err)
+	}
+}
+
+func TestTryAll(t *testing.T) {
+	records, err := ParseDetectionRecords("Before: [3, 2, 1, 1]\n9 2 1 2\nAfter:  [3, 2, 2, 1]\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records.Records) != 1 || !records.Records[0].TryAll() {
+		t.Errorf("the puzzle's sample should behave like 3 opcodes")
+	}
+
+	// register 7 doesn't exist, so nothing reading it matches; only seti can
+	records.AddRecord(0, 0, 0, 0, 1, 7, 7, 0, 7, 0, 0, 0)
+	if records.Records[1].TryAll() {
+		t.Errorf("a sample reading register 7 behaves like 3 opcodes")
+	}
+}
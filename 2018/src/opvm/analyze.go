@@ -0,0 +1,352 @@
+package opvm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Block - a run of instructions with one way in (the first) and one way out
+// (the last)
+type Block struct {
+	Start, End int   // instruction indexes, End is exclusive
+	Succs      []int // Start of each block control can move to next; len(Instructions) means the program ends
+	Indirect   bool  // the last instruction jumps somewhere that depends on a register
+}
+
+// Loop - a natural loop: a back edge from Latch to Header and every block
+// which can reach Latch without going through Header
+type Loop struct {
+	Header int   // Start of the header block
+	Latch  int   // Start of the block with the back edge
+	Body   []int // Start of every block in the loop, sorted
+}
+
+// Analysis - the control-flow graph of a Program, its loops and any idioms
+// found in it
+type Analysis struct {
+	Program *Program
+	Blocks  []*Block       // sorted by Start
+	ByStart map[int]*Block // Blocks keyed by Start
+	Loops   []*Loop
+	Idioms  []*IdiomMatch
+}
+
+// jump - where control can go after the instruction at +i+. Without an IP
+// register every instruction falls through. +known+ is false if the target
+// depends on a register value that can't be worked out statically.
+func (p *Program) jump(i int) (targets []int, known bool) {
+	n := p.Instructions[i]
+	ip := p.IPRegister
+	if ip < 0 || n.C != ip {
+		return []int{i + 1}, true
+	}
+	aReg, bReg := RegisterOperands(n.Opcode)
+	a, b := n.A, n.B
+	aIsIP := aReg && a == ip
+	bIsIP := bReg && b == ip
+
+	switch n.Opcode {
+	case Seti:
+		return []int{a + 1}, true
+	case Addi:
+		if aIsIP {
+			return []int{i + b + 1}, true
+		}
+	case Muli:
+		if aIsIP {
+			return []int{i*b + 1}, true
+		}
+	case Mulr:
+		if aIsIP && bIsIP {
+			return []int{i*i + 1}, true
+		}
+	case Addr:
+		// ip += rX where rX was just set to 0 or 1 by a comparison: skip
+		// the next instruction or not
+		other := -1
+		if aIsIP && !bIsIP {
+			other = b
+		} else if bIsIP && !aIsIP {
+			other = a
+		}
+		if other >= 0 && p.isBoolean(i-1, other) {
+			return []int{i + 1, i + 2}, true
+		}
+	case Setr:
+		if aIsIP {
+			return []int{i + 1}, true
+		}
+	}
+	return nil, false
+}
+
+// isBoolean - does the instruction at +i+ leave 0 or 1 in register +r+?
+func (p *Program) isBoolean(i, r int) bool {
+	if i < 0 || p.Instructions[i].C != r {
+		return false
+	}
+	switch p.Instructions[i].Opcode {
+	case Gtir, Gtri, Gtrr, Eqir, Eqri, Eqrr:
+		return true
+	}
+	return false
+}
+
+// Analyze - build the control-flow graph for +p+, find its loops and match
+// the known idioms against it
+func Analyze(p *Program) *Analysis {
+	a := &Analysis{
+		Program: p,
+		ByStart: make(map[int]*Block),
+	}
+	count := len(p.Instructions)
+
+	// Leaders: the first instruction, every jump target and every
+	// instruction after a jump
+	leaders := map[int]bool{0: true}
+	for i := range p.Instructions {
+		targets, known := p.jump(i)
+		if known && len(targets) == 1 && targets[0] == i+1 {
+			continue
+		}
+		leaders[i+1] = true
+		for _, t := range targets {
+			leaders[t] = true
+		}
+	}
+	starts := make([]int, 0, len(leaders))
+	for start := range leaders {
+		if start >= 0 && start < count {
+			starts = append(starts, start)
+		}
+	}
+	sort.Ints(starts)
+
+	for k, start := range starts {
+		end := count
+		if k+1 < len(starts) {
+			end = starts[k+1]
+		}
+		block := &Block{Start: start, End: end, Succs: make([]int, 0)}
+		targets, known := p.jump(end - 1)
+		block.Indirect = !known
+		for _, t := range targets {
+			if t < 0 || t >= count {
+				t = count
+			}
+			block.Succs = append(block.Succs, t)
+		}
+		a.Blocks = append(a.Blocks, block)
+		a.ByStart[start] = block
+	}
+
+	a.findLoops()
+	a.Idioms = FindIdioms(p)
+	return a
+}
+
+// findLoops - depth-first search from the entry; an edge to a block still on
+// the DFS stack is a back edge, and each one makes a natural loop
+func (a *Analysis) findLoops() {
+	if len(a.Blocks) == 0 {
+		return
+	}
+	const (
+		unseen = iota
+		onStack
+		finished
+	)
+	state := make(map[int]int)
+	var visit func(start int)
+	visit = func(start int) {
+		state[start] = onStack
+		for _, succ := range a.ByStart[start].Succs {
+			if _, ok := a.ByStart[succ]; !ok {
+				continue
+			}
+			switch state[succ] {
+			case unseen:
+				visit(succ)
+			case onStack:
+				a.Loops = append(a.Loops, a.naturalLoop(succ, start))
+			}
+		}
+		state[start] = finished
+	}
+	// start from the entry, then from anything the entry can't be shown to
+	// reach (indirect jumps hide edges)
+	for _, block := range a.Blocks {
+		if state[block.Start] == unseen {
+			visit(block.Start)
+		}
+	}
+
+	sort.Slice(a.Loops, func(i, j int) bool {
+		if a.Loops[i].Header != a.Loops[j].Header {
+			return a.Loops[i].Header < a.Loops[j].Header
+		}
+		return a.Loops[i].Latch < a.Loops[j].Latch
+	})
+}
+
+// naturalLoop - every block which can reach +latch+ without passing +header+
+func (a *Analysis) naturalLoop(header, latch int) *Loop {
+	preds := make(map[int][]int)
+	for _, block := range a.Blocks {
+		for _, succ := range block.Succs {
+			preds[succ] = append(preds[succ], block.Start)
+		}
+	}
+	body := map[int]bool{header: true}
+	work := []int{latch}
+	for len(work) > 0 {
+		start := work[len(work)-1]
+		work = work[:len(work)-1]
+		if body[start] {
+			continue
+		}
+		body[start] = true
+		work = append(work, preds[start]...)
+	}
+	loop := &Loop{Header: header, Latch: latch, Body: make([]int, 0, len(body))}
+	for start := range body {
+		loop.Body = append(loop.Body, start)
+	}
+	sort.Ints(loop.Body)
+	return loop
+}
+
+// regName - how register +r+ is written in pseudo-code
+func (p *Program) regName(r int) string {
+	if r == p.IPRegister {
+		return "ip"
+	}
+	return fmt.Sprintf("r%d", r)
+}
+
+// Pseudo - one line of pseudo-code for the instruction at +i+
+func (p *Program) Pseudo(i int) string {
+	n := p.Instructions[i]
+	if p.IPRegister >= 0 && n.C == p.IPRegister {
+		targets, known := p.jump(i)
+		switch {
+		case !known:
+			return p.indirect(i)
+		case len(targets) == 2:
+			return fmt.Sprintf("if %s goto %d", p.conditionAt(i-1), targets[1])
+		case targets[0] < 0 || targets[0] >= len(p.Instructions):
+			return "halt"
+		case targets[0] == i+1:
+			return "nop"
+		}
+		return fmt.Sprintf("goto %d", targets[0])
+	}
+	return p.expression(n)
+}
+
+// indirect - pseudo-code for a jump whose target depends on a register
+func (p *Program) indirect(i int) string {
+	n := p.Instructions[i]
+	aReg, bReg := RegisterOperands(n.Opcode)
+	if n.Opcode == Addr && aReg && bReg {
+		if n.A == p.IPRegister {
+			return fmt.Sprintf("goto %d + %s", i+1, p.regName(n.B))
+		}
+		if n.B == p.IPRegister {
+			return fmt.Sprintf("goto %d + %s", i+1, p.regName(n.A))
+		}
+	}
+	expr := p.expression(&Instruction{Opcode: n.Opcode, A: n.A, B: n.B, C: -1})
+	return fmt.Sprintf("goto 1 + (%s)", expr[strings.Index(expr, " = ")+3:])
+}
+
+// expression - "rC = ..." for an instruction
+func (p *Program) expression(n *Instruction) string {
+	r := p.regName
+	aReg, bReg := RegisterOperands(n.Opcode)
+	a, b := fmt.Sprintf("%d", n.A), fmt.Sprintf("%d", n.B)
+	if aReg {
+		a = r(n.A)
+	}
+	if bReg {
+		b = r(n.B)
+	}
+	c := r(n.C)
+
+	// rC = rC op x reads better as rC op= x
+	compound := func(op string) string {
+		if aReg && n.A == n.C {
+			return fmt.Sprintf("%s %s= %s", c, op, b)
+		}
+		if bReg && n.B == n.C {
+			// every compound operator here is commutative
+			return fmt.Sprintf("%s %s= %s", c, op, a)
+		}
+		return fmt.Sprintf("%s = %s %s %s", c, a, op, b)
+	}
+	switch n.Opcode {
+	case Addr, Addi:
+		return compound("+")
+	case Mulr, Muli:
+		return compound("*")
+	case Banr, Bani:
+		return compound("&")
+	case Borr, Bori:
+		return compound("|")
+	case Setr, Seti:
+		return fmt.Sprintf("%s = %s", c, a)
+	case Gtir, Gtri, Gtrr:
+		return fmt.Sprintf("%s = %s > %s", c, a, b)
+	case Eqir, Eqri, Eqrr:
+		return fmt.Sprintf("%s = %s == %s", c, a, b)
+	}
+	return "?"
+}
+
+// conditionAt - the comparison made by the instruction at +i+, eg "r2 > r1"
+func (p *Program) conditionAt(i int) string {
+	expr := p.expression(p.Instructions[i])
+	return expr[strings.Index(expr, " = ")+3:]
+}
+
+// Decompile - pseudo-code for the whole program. Blocks are labelled, loops
+// are marked at their header and any recognised idiom is shown as the single
+// step it amounts to instead of the instructions it replaces.
+func (a *Analysis) Decompile() string {
+	p := a.Program
+	var out strings.Builder
+
+	idiomAt := make(map[int]*IdiomMatch)
+	for _, m := range a.Idioms {
+		idiomAt[m.Start] = m
+	}
+	loopsAt := make(map[int][]*Loop)
+	for _, loop := range a.Loops {
+		loopsAt[loop.Header] = append(loopsAt[loop.Header], loop)
+	}
+
+	if p.IPRegister >= 0 {
+		fmt.Fprintf(&out, "; ip is bound to r%d\n", p.IPRegister)
+	}
+	for i := 0; i < len(p.Instructions); {
+		if block, ok := a.ByStart[i]; ok {
+			fmt.Fprintf(&out, "%04d:", i)
+			for _, loop := range loopsAt[i] {
+				fmt.Fprintf(&out, " ; loop back from %04d over %d blocks", loop.Latch, len(loop.Body))
+			}
+			if block.Indirect {
+				fmt.Fprintf(&out, " ; ends with an indirect jump")
+			}
+			fmt.Fprintln(&out)
+		}
+		if m, ok := idiomAt[i]; ok {
+			fmt.Fprintf(&out, "\t%s ; %s, instructions %04d-%04d\n", m.Describe(), m.Idiom.Name, m.Start, m.End-1)
+			i = m.End
+			continue
+		}
+		fmt.Fprintf(&out, "\t%s\n", p.Pseudo(i))
+		i++
+	}
+	return out.String()
+}
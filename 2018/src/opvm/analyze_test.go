@@ -0,0 +1,52 @@
+package opvm
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeDivisorSum(t *testing.T) {
+	p, err := ParseAssembly(divisorProgram(36), 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := Analyze(p)
+
+	want := []Loop{
+		{Header: 2, Latch: 15, Body: []int{2, 3, 6, 7, 8, 11, 12, 15}},
+		{Header: 3, Latch: 11, Body: []int{3, 6, 7, 8, 11}},
+	}
+	if len(a.Loops) != len(want) {
+		t.Fatalf("found %d loops, want %d", len(a.Loops), len(want))
+	}
+	for i, loop := range a.Loops {
+		if loop.Header != want[i].Header || loop.Latch != want[i].Latch || !slices.Equal(loop.Body, want[i].Body) {
+			t.Errorf("loop %d: got %+v, want %+v", i, *loop, want[i])
+		}
+	}
+
+	decompiled := `; ip is bound to r4
+0000:
+	r3 = 36
+	r0 += sum of divisors of r3 ; sum of divisors, instructions 0001-0015
+`
+	if got := a.Decompile(); got != decompiled {
+		t.Errorf("decompiled:\n%s\nwant:\n%s", got, decompiled)
+	}
+
+	// without the idiom the loops show at their headers
+	a.Idioms = nil
+	plain := a.Decompile()
+	for _, line := range []string{
+		"0002: ; loop back from 0015 over 8 blocks\n",
+		"0003: ; loop back from 0011 over 5 blocks\n",
+		"\tr5 = r1 * r2\n",
+		"\tr0 += r1\n",
+		"\tgoto 3\n",
+	} {
+		if !strings.Contains(plain, line) {
+			t.Errorf("decompiled without idioms has no %q:\n%s", line, plain)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package opvm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// historySize - how many executed instructions the Debugger remembers
+const historySize = 256
+
+const debuggerHelp = `Commands:
+  step [n]             execute n instructions (default 1), ignoring breakpoints
+  continue             run until a breakpoint, a watchpoint or the end
+  run n                run at most n instructions, stopping like continue
+  break i              stop before instruction index i
+  break if rX op v     stop before any instruction when the condition holds
+  break i if rX op v   stop before instruction i when the condition holds
+                       (op is one of == != < <= > >=)
+  delete id            remove a breakpoint
+  watch rX             stop whenever register X changes
+  unwatch rX           stop watching register X
+  info                 list breakpoints and watchpoints
+  regs                 show the registers
+  list [n]             show n instructions either side of IP (default 3)
+  history [n]          show the last n executed instructions (default 10)
+  quit                 leave the debugger
+`
+
+// Condition - a comparison between a register and a value, eg r0 > 5
+type Condition struct {
+	Register int
+	Op       string
+	Value    int
+}
+
+// ParseCondition - parse "rX op value" from +fields+ for a machine with
+// +registers+ registers
+func ParseCondition(fields []string, registers int) (*Condition, error) {
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("condition should look like r0 > 5")
+	}
+	register, err := parseRegister(fields[0], registers)
+	if err != nil {
+		return nil, err
+	}
+	switch fields[1] {
+	case "==", "!=", "<", "<=", ">", ">=":
+	default:
+		return nil, fmt.Errorf("unknown comparison %q", fields[1])
+	}
+	value, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse value %q: %v", fields[2], err)
+	}
+	return &Condition{Register: register, Op: fields[1], Value: value}, nil
+}
+
+// Holds - is the condition true for registers +r+?
+func (c *Condition) Holds(r []int) bool {
+	v := r[c.Register]
+	switch c.Op {
+	case "==":
+		return v == c.Value
+	case "!=":
+		return v != c.Value
+	case "<":
+		return v < c.Value
+	case "<=":
+		return v <= c.Value
+	case ">":
+		return v > c.Value
+	case ">=":
+		return v >= c.Value
+	}
+	return false
+}
+
+func (c *Condition) String() string {
+	return fmt.Sprintf("r%d %s %d", c.Register, c.Op, c.Value)
+}
+
+// Breakpoint - stop before the instruction at Index (any instruction if
+// Index is -1) when Condition holds (always if there is no Condition)
+type Breakpoint struct {
+	ID        int
+	Index     int
+	Condition *Condition
+}
+
+// Hit - should execution stop before running the instruction at +ip+?
+func (b *Breakpoint) Hit(ip int, r []int) bool {
+	if b.Index >= 0 && b.Index != ip {
+		return false
+	}
+	return b.Condition == nil || b.Condition.Holds(r)
+}
+
+func (b *Breakpoint) String() string {
+	desc := fmt.Sprintf("#%d", b.ID)
+	if b.Index >= 0 {
+		desc = fmt.Sprintf("%s at %04d", desc, b.Index)
+	}
+	if b.Condition != nil {
+		desc = fmt.Sprintf("%s if %s", desc, b.Condition)
+	}
+	return desc
+}
+
+// HistoryEntry - one executed instruction
+type HistoryEntry struct {
+	Step          int // how many instructions had run before this one
+	Index         int // where the instruction is in the program
+	Instruction   *Instruction
+	Before, After []int // registers
+}
+
+// Debugger - drive a Program one command at a time. Commands are read a line
+// at a time from an io.Reader so a session can be scripted.
+type Debugger struct {
+	Program *Program
+
+	in          *bufio.Scanner
+	out         io.Writer
+	breakpoints []*Breakpoint
+	nextID      int
+	watches     map[int]bool
+	history     []HistoryEntry // circular, newest at (steps-1) % historySize
+	steps       int            // total instructions executed
+	stoppedAt   int            // steps when a breakpoint last stopped execution, -1 for never
+}
+
+// NewDebugger - a debugger for +p+ reading commands from +in+ and writing
+// everything to +out+
+func NewDebugger(p *Program, in io.Reader, out io.Writer) *Debugger {
+	return &Debugger{
+		Program:     p,
+		in:          bufio.NewScanner(in),
+		out:         out,
+		breakpoints: make([]*Breakpoint, 0),
+		nextID:      1,
+		watches:     make(map[int]bool),
+		history:     make([]HistoryEntry, 0, historySize),
+		stoppedAt:   -1,
+	}
+}
+
+// Run - read and carry out commands until quit or the end of the input
+func (d *Debugger) Run() error {
+	fmt.Fprintf(d.out, "%d instructions loaded, type help for commands\n", len(d.Program.Instructions))
+	for {
+		fmt.Fprintf(d.out, "(%04d) > ", d.Program.IP)
+		if !d.in.Scan() {
+			fmt.Fprintln(d.out)
+			return d.in.Err()
+		}
+		quit, err := d.Command(d.in.Text())
+		if err != nil {
+			fmt.Fprintf(d.out, "error: %s\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// Command - carry out one command. Returns true if the debugger should exit.
+func (d *Debugger) Command(line string) (bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false, nil
+	}
+	args := fields[1:]
+	switch fields[0] {
+	case "help", "h", "?":
+		fmt.Fprint(d.out, debuggerHelp)
+	case "step", "s":
+		n, err := optionalCount(args, 1)
+		if err != nil {
+			return false, err
+		}
+		d.execute(n, false)
+	case "continue", "c":
+		d.execute(-1, true)
+	case "run":
+		if len(args) != 1 {
+			return false, fmt.Errorf("run needs a number of instructions")
+		}
+		n, err := optionalCount(args, 0)
+		if err != nil {
+			return false, err
+		}
+		d.execute(n, true)
+	case "break", "b":
+		bp, err := d.parseBreakpoint(args)
+		if err != nil {
+			return false, err
+		}
+		d.breakpoints = append(d.breakpoints, bp)
+		fmt.Fprintf(d.out, "breakpoint %s\n", bp)
+	case "delete", "d":
+		if len(args) != 1 {
+			return false, fmt.Errorf("delete needs a breakpoint id")
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil {
+			return false, fmt.Errorf("couldn't parse breakpoint id %q", args[0])
+		}
+		for i, bp := range d.breakpoints {
+			if bp.ID == id {
+				d.breakpoints = append(d.breakpoints[:i], d.breakpoints[i+1:]...)
+				return false, nil
+			}
+		}
+		return false, fmt.Errorf("no breakpoint #%d", id)
+	case "watch", "w", "unwatch":
+		if len(args) != 1 {
+			return false, fmt.Errorf("%s needs a register", fields[0])
+		}
+		register, err := parseRegister(args[0], len(d.Program.Registers))
+		if err != nil {
+			return false, err
+		}
+		if fields[0] == "unwatch" {
+			delete(d.watches, register)
+		} else {
+			d.watches[register] = true
+		}
+	case "info", "i":
+		for _, bp := range d.breakpoints {
+			fmt.Fprintf(d.out, "breakpoint %s\n", bp)
+		}
+		for register := range d.Program.Registers {
+			if d.watches[register] {
+				fmt.Fprintf(d.out, "watch r%d\n", register)
+			}
+		}
+	case "regs", "r":
+		fmt.Fprintf(d.out, "registers %d\n", d.Program.Registers)
+	case "list", "l":
+		n, err := optionalCount(args, 3)
+		if err != nil {
+			return false, err
+		}
+		d.list(n)
+	case "history":
+		n, err := optionalCount(args, 10)
+		if err != nil {
+			return false, err
+		}
+		for _, entry := range d.History(n) {
+			fmt.Fprintf(d.out, "#%d [%04d] %-16s %d -> %d\n", entry.Step, entry.Index, entry.Instruction, entry.Before, entry.After)
+		}
+	case "quit", "q", "exit":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command %q, type help for commands", fields[0])
+	}
+	return false, nil
+}
+
+// History - the last +n+ executed instructions, oldest first
+func (d *Debugger) History(n int) []HistoryEntry {
+	if n > len(d.history) {
+		n = len(d.history)
+	}
+	ret := make([]HistoryEntry, n)
+	for i := 0; i < n; i++ {
+		ret[i] = d.history[(d.steps-n+i)%historySize]
+	}
+	return ret
+}
+
+// execute - run up to +limit+ instructions (no limit if negative). If
+// +checked+ stop at breakpoints and watchpoints. Resuming from the
+// breakpoint that last stopped it runs that instruction regardless, so that
+// continuing makes progress.
+func (d *Debugger) execute(limit int, checked bool) {
+	p := d.Program
+	for n := 0; limit < 0 || n < limit; n++ {
+		if p.Done() {
+			fmt.Fprintf(d.out, "program complete after %d instructions, registers %d\n", d.steps, p.Registers)
+			return
+		}
+		if checked && !(n == 0 && d.stoppedAt == d.steps) {
+			for _, bp := range d.breakpoints {
+				if bp.Hit(p.IP, p.Registers) {
+					fmt.Fprintf(d.out, "hit breakpoint %s, registers %d\n", bp, p.Registers)
+					d.stoppedAt = d.steps
+					return
+				}
+			}
+		}
+
+		entry := HistoryEntry{
+			Step:        d.steps,
+			Index:       p.IP,
+			Instruction: p.Instructions[p.IP],
+			Before:      append([]int(nil), p.Registers...),
+		}
+		p.Step()
+		entry.After = append([]int(nil), p.Registers...)
+		d.record(entry)
+
+		changed := false
+		for register := range p.Registers {
+			if d.watches[register] && entry.Before[register] != entry.After[register] {
+				fmt.Fprintf(d.out, "watch r%d: %d -> %d at [%04d] %s\n", register, entry.Before[register], entry.After[register], entry.Index, entry.Instruction)
+				changed = true
+			}
+		}
+		if !checked {
+			fmt.Fprintf(d.out, "[%04d] %-16s %d -> %d\n", entry.Index, entry.Instruction, entry.Before, entry.After)
+		} else if changed {
+			return
+		}
+	}
+	if checked {
+		fmt.Fprintf(d.out, "stopped after %d instructions, registers %d\n", limit, p.Registers)
+	}
+}
+
+// record - add an entry to the history
+func (d *Debugger) record(entry HistoryEntry) {
+	if len(d.history) < historySize {
+		d.history = append(d.history, entry)
+	} else {
+		d.history[d.steps%historySize] = entry
+	}
+	d.steps++
+}
+
+// list - show the instructions around IP
+func (d *Debugger) list(n int) {
+	p := d.Program
+	for i := p.IP - n; i <= p.IP+n; i++ {
+		if i < 0 || i >= len(p.Instructions) {
+			continue
+		}
+		marker := " "
+		if i == p.IP {
+			marker = ">"
+		}
+		fmt.Fprintf(d.out, "%s [%04d] %s\n", marker, i, p.Instructions[i])
+	}
+}
+
+// parseBreakpoint - i, if <condition> or i if <condition>
+func (d *Debugger) parseBreakpoint(args []string) (*Breakpoint, error) {
+	bp := &Breakpoint{Index: -1}
+	if len(args) > 0 && args[0] != "if" {
+		index, err := strconv.Atoi(args[0])
+		if err != nil || index < 0 || index >= len(d.Program.Instructions) {
+			return nil, fmt.Errorf("%q isn't an instruction index", args[0])
+		}
+		bp.Index = index
+		args = args[1:]
+	}
+	if len(args) > 0 {
+		if args[0] != "if" {
+			return nil, fmt.Errorf("expected if, got %q", args[0])
+		}
+		condition, err := ParseCondition(args[1:], len(d.Program.Registers))
+		if err != nil {
+			return nil, err
+		}
+		bp.Condition = condition
+	}
+	if bp.Index < 0 && bp.Condition == nil {
+		return nil, fmt.Errorf("break needs an instruction index or a condition")
+	}
+	bp.ID = d.nextID
+	d.nextID++
+	return bp, nil
+}
+
+// parseRegister - r0, r1, ... into a register number, below +registers+
+func parseRegister(text string, registers int) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(text, "r"))
+	if err != nil || !strings.HasPrefix(text, "r") || n < 0 || n >= registers {
+		return 0, fmt.Errorf("%q isn't a register (r0-r%d)", text, registers-1)
+	}
+	return n, nil
+}
+
+// optionalCount - the first of +args+ as a positive number, or +def+ if
+// there are no args
+func optionalCount(args []string, def int) (int, error) {
+	if len(args) == 0 {
+		return def, nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%q isn't a positive number", args[0])
+	}
+	return n, nil
+}
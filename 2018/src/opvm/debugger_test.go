@@ -0,0 +1,84 @@
+package opvm
+
+import (
+	"strings"
+	"testing"
+)
+
+const debugProgram = `seti 7 0 1
+addi 1 3 2
+mulr 1 2 3
+seti 1 0 0
+`
+
+// session - the transcript of a Debugger on debugProgram running +script+
+func session(t *testing.T, script string) string {
+	t.Helper()
+	p, err := ParseAssembly(debugProgram, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := NewDebugger(p, strings.NewReader(script), &out).Run(); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestDebuggerSessions(t *testing.T) {
+	tests := []struct {
+		name, script, want string
+	}{
+		{
+			"break at the first instruction",
+			"break 0\ncontinue\ncontinue\n",
+			`4 instructions loaded, type help for commands
+(0000) > breakpoint #1 at 0000
+(0000) > hit breakpoint #1 at 0000, registers [0 0 0 0]
+(0000) > program complete after 4 instructions, registers [1 7 10 70]
+(0004) > 
+`,
+		},
+		{
+			"condition true from the start",
+			"break if r0 == 0\ncontinue\nstep\ncontinue\ndelete 1\nwatch r3\nc\nc\ninfo\nhistory 2\nregs\nquit\n",
+			`4 instructions loaded, type help for commands
+(0000) > breakpoint #1 if r0 == 0
+(0000) > hit breakpoint #1 if r0 == 0, registers [0 0 0 0]
+(0000) > [0000] seti 7 0 1       [0 0 0 0] -> [0 7 0 0]
+(0001) > hit breakpoint #1 if r0 == 0, registers [0 7 0 0]
+(0001) > (0001) > (0001) > watch r3: 0 -> 70 at [0002] mulr 1 2 3
+(0003) > program complete after 4 instructions, registers [1 7 10 70]
+(0004) > watch r3
+(0004) > #2 [0002] mulr 1 2 3       [0 7 10 0] -> [0 7 10 70]
+#3 [0003] seti 1 0 0       [0 7 10 70] -> [1 7 10 70]
+(0004) > registers [1 7 10 70]
+(0004) > `,
+		},
+		{
+			"breakpoint after running past it",
+			"break 2\nrun 1\ncontinue\ncontinue\n",
+			`4 instructions loaded, type help for commands
+(0000) > breakpoint #1 at 0002
+(0000) > stopped after 1 instructions, registers [0 7 0 0]
+(0001) > hit breakpoint #1 at 0002, registers [0 7 10 0]
+(0002) > program complete after 4 instructions, registers [1 7 10 70]
+(0004) > 
+`,
+		},
+		{
+			"errors",
+			"break 9\nbreak if r7 > 1\nfly\nquit\n",
+			`4 instructions loaded, type help for commands
+(0000) > error: "9" isn't an instruction index
+(0000) > error: "r7" isn't a register (r0-r3)
+(0000) > error: unknown command "fly", type help for commands
+(0000) > `,
+		},
+	}
+	for _, test := range tests {
+		if got := session(t, test.script); got != test.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package opvm
+
+import (
+	"fmt"
+)
+
+// Operand patterns for idiom templates. Register variables are single
+// letters bound to whichever register the program uses the first time they
+// are seen; P is always the IP register.
+const (
+	anyOperand = "_"  // ignored (eg B of seti)
+	jumpBack   = "@"  // seti to Start+Back (so that ip lands on Start+Back+1)
+	ipVariable = "P"  // the register bound to ip
+	literalOne = "#1" // the value 1
+)
+
+// patternInstruction - one instruction in an idiom template
+type patternInstruction struct {
+	Opcode      OpcodeId
+	A, B, C     string
+	Back        int  // for jumpBack, the offset from the idiom's start
+	Commutative bool // A and B may appear either way round
+}
+
+// Idiom - a sequence of instructions that computes something simple the
+// slow way, and the native Go that replaces it
+type Idiom struct {
+	Name    string
+	Pattern []patternInstruction
+	// Describe - pseudo-code for what the idiom computes, given the bound
+	// register variables
+	Describe func(vars map[string]int) string
+	// Apply - do the computation on the registers, leaving them as running
+	// the instructions would have
+	Apply func(r []int, vars map[string]int)
+}
+
+// IdiomMatch - an idiom found in a program
+type IdiomMatch struct {
+	Idiom      *Idiom
+	Start, End int            // instructions replaced, End is exclusive
+	Vars       map[string]int // register variable bindings
+}
+
+// Describe - pseudo-code for the match
+func (m *IdiomMatch) Describe() string {
+	return m.Idiom.Describe(m.Vars)
+}
+
+// Idioms - every idiom FindIdioms looks for
+var Idioms = []*Idiom{
+	divisorSum,
+}
+
+// divisorSum - for i in 1..n { for j in 1..n { if i*j == n { s += i } } }
+var divisorSum = &Idiom{
+	Name: "sum of divisors",
+	Pattern: []patternInstruction{
+		{Opcode: Seti, A: literalOne, B: anyOperand, C: "i"},
+		{Opcode: Seti, A: literalOne, B: anyOperand, C: "j"},
+		{Opcode: Mulr, A: "i", B: "j", C: "t", Commutative: true},
+		{Opcode: Eqrr, A: "t", B: "n", C: "t", Commutative: true},
+		{Opcode: Addr, A: "t", B: ipVariable, C: ipVariable, Commutative: true},
+		{Opcode: Addi, A: ipVariable, B: literalOne, C: ipVariable},
+		{Opcode: Addr, A: "i", B: "s", C: "s", Commutative: true},
+		{Opcode: Addi, A: "j", B: literalOne, C: "j"},
+		{Opcode: Gtrr, A: "j", B: "n", C: "t"},
+		{Opcode: Addr, A: ipVariable, B: "t", C: ipVariable, Commutative: true},
+		{Opcode: Seti, A: jumpBack, Back: 2, B: anyOperand, C: ipVariable},
+		{Opcode: Addi, A: "i", B: literalOne, C: "i"},
+		{Opcode: Gtrr, A: "i", B: "n", C: "t"},
+		{Opcode: Addr, A: "t", B: ipVariable, C: ipVariable, Commutative: true},
+		{Opcode: Seti, A: jumpBack, Back: 1, B: anyOperand, C: ipVariable},
+	},
+	Describe: func(v map[string]int) string {
+		return fmt.Sprintf("r%d += sum of divisors of r%d", v["s"], v["n"])
+	},
+	Apply: func(r []int, v map[string]int) {
+		n := r[v["n"]]
+		sum := 0
+		for i := 1; i*i <= n; i++ {
+			if n%i == 0 {
+				sum += i
+				if i*i != n {
+					sum += n / i
+				}
+			}
+		}
+		r[v["s"]] += sum
+		// the loop counters finish one past n and the flag register set
+		if n < 1 {
+			n = 1
+		}
+		r[v["i"]] = n + 1
+		r[v["j"]] = n + 1
+		r[v["t"]] = 1
+	},
+}
+
+// bind - match one pattern operand against +value+
+func bind(pattern string, value int, start int, back int, vars map[string]int) bool {
+	switch pattern {
+	case anyOperand:
+		return true
+	case literalOne:
+		return value == 1
+	case jumpBack:
+		return value == start+back-1
+	}
+	if bound, ok := vars[pattern]; ok {
+		return bound == value
+	}
+	// a new variable can't reuse a register another variable has
+	for _, other := range vars {
+		if other == value {
+			return false
+		}
+	}
+	vars[pattern] = value
+	return true
+}
+
+// matchAt - does +idiom+ match the program starting at instruction +start+?
+func (p *Program) matchAt(idiom *Idiom, start int) (map[string]int, bool) {
+	if p.IPRegister < 0 || start+len(idiom.Pattern) > len(p.Instructions) {
+		return nil, false
+	}
+	vars := map[string]int{ipVariable: p.IPRegister}
+	for k, pattern := range idiom.Pattern {
+		n := p.Instructions[start+k]
+		if n.Opcode != pattern.Opcode {
+			return nil, false
+		}
+		// try A/B as written, and swapped if allowed, on a copy of the bindings
+		orders := [][2]int{{n.A, n.B}}
+		if pattern.Commutative {
+			orders = append(orders, [2]int{n.B, n.A})
+		}
+		matched := false
+		for _, ab := range orders {
+			attempt := make(map[string]int)
+			for name, r := range vars {
+				attempt[name] = r
+			}
+			if bind(pattern.A, ab[0], start, pattern.Back, attempt) &&
+				bind(pattern.B, ab[1], start, pattern.Back, attempt) &&
+				bind(pattern.C, n.C, start, pattern.Back, attempt) {
+				vars = attempt
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, false
+		}
+	}
+	return vars, true
+}
+
+// FindIdioms - every place in +p+ where one of Idioms matches
+func FindIdioms(p *Program) []*IdiomMatch {
+	matches := make([]*IdiomMatch, 0)
+	for start := range p.Instructions {
+		for _, idiom := range Idioms {
+			if vars, ok := p.matchAt(idiom, start); ok {
+				matches = append(matches, &IdiomMatch{
+					Idiom: idiom,
+					Start: start,
+					End:   start + len(idiom.Pattern),
+					Vars:  vars,
+				})
+			}
+		}
+	}
+	return matches
+}
+
+// Accelerate - have Step run native Go in place of every idiom found in the
+// program, until another instruction is added. Returns what was found.
+func (p *Program) Accelerate() []*IdiomMatch {
+	matches := FindIdioms(p)
+	p.shortcuts = make([]*IdiomMatch, len(p.Instructions))
+	for _, m := range matches {
+		p.shortcuts[m.Start] = m
+	}
+	return matches
+}
@@ -0,0 +1,105 @@
+package opvm
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// divisorProgram - day 19's shape: set r3 to +n+, then sum its divisors into
+// r0 the slow way
+func divisorProgram(n int) string {
+	return fmt.Sprintf(`#ip 4
+seti %d 0 3
+seti 1 0 1
+seti 1 0 2
+mulr 1 2 5
+eqrr 5 3 5
+addr 5 4 4
+addi 4 1 4
+addr 1 0 0
+addi 2 1 2
+gtrr 2 3 5
+addr 4 5 4
+seti 2 0 4
+addi 1 1 1
+gtrr 1 3 5
+addr 5 4 4
+seti 1 0 4
+`, n)
+}
+
+// hashProgram - day 21's shape: bit twiddling in a loop, with nothing to
+// accelerate
+const hashProgram = `#ip 5
+seti 7 0 1
+seti 0 0 2
+muli 1 65899 1
+bani 1 16777215 1
+addi 2 1 2
+gtri 2 4 4
+addr 4 5 5
+seti 1 0 5
+bori 1 65536 3
+`
+
+// run - +text+ to the end, accelerated or not, returning the registers
+func run(t *testing.T, text string, accelerate bool) (regs []int, matches int) {
+	t.Helper()
+	p, err := ParseAssembly(text, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accelerate {
+		matches = len(p.Accelerate())
+	}
+	p.Execute()
+	return p.Registers, matches
+}
+
+func TestAccelerateMatchesNaive(t *testing.T) {
+	programs := map[string]string{"day21 style": hashProgram}
+	for _, n := range []int{1, 2, 13, 36, 100} {
+		programs[fmt.Sprintf("divisors of %d", n)] = divisorProgram(n)
+	}
+	for name, text := range programs {
+		naive, _ := run(t, text, false)
+		fast, matches := run(t, text, true)
+		if !slices.Equal(naive, fast) {
+			t.Errorf("%s: accelerated registers %d, naive %d", name, fast, naive)
+		}
+		if want := name != "day21 style"; (matches > 0) != want {
+			t.Errorf("%s: %d idioms found", name, matches)
+		}
+	}
+	if regs, _ := run(t, divisorProgram(36), true); regs[0] != 91 {
+		t.Errorf("sum of divisors of 36: got %d, want 91", regs[0])
+	}
+}
+
+func TestAddInstructionAfterAccelerate(t *testing.T) {
+	p, err := ParseAssembly(divisorProgram(12), 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Accelerate()) == 0 {
+		t.Fatal("no idioms found")
+	}
+	// the idiom's exit now lands on a new instruction rather than the end
+	p.AddInstruction(Addi, 0, 1000, 0)
+	p.Execute()
+
+	naive, _ := run(t, divisorProgram(12)+"addi 0 1000 0\n", false)
+	if !slices.Equal(p.Registers, naive) {
+		t.Errorf("registers %d, want %d", p.Registers, naive)
+	}
+
+	// a table from an empty program mustn't be indexed once there's code
+	empty := NewProgram(Config{Registers: 6, IPRegister: 4})
+	empty.Accelerate()
+	empty.AddInstruction(Seti, 5, 0, 0)
+	empty.Execute()
+	if empty.Registers[0] != 5 {
+		t.Errorf("registers %d, want r0 = 5", empty.Registers)
+	}
+}
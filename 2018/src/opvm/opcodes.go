@@ -0,0 +1,207 @@
+// Package opvm - the 16 opcode register machine from 2018 day 16
+package opvm
+
+import (
+	"fmt"
+)
+
+// OpcodeId - numerical ID code for opcodes. The numbers a puzzle input uses
+// for them differ from these; see SolveOpcodes in aoc2018/day16.
+type OpcodeId uint8
+
+const (
+	Addr OpcodeId = iota // add reg A + reg B -> reg C
+	Addi                 // add reg A + val B -> reg C
+	Mulr                 // multiply reg A * reg B -> reg C
+	Muli                 // multiply reg A * val B -> reg C
+	Banr                 // bitwise and reg A & reg B -> reg C
+	Bani                 // bitwise and reg A & val B -> reg C
+	Borr                 // bitwise or reg A | reg B -> reg C
+	Bori                 // bitwise or reg A | val B -> reg C
+	Setr                 // set reg C to reg A (copy contents)
+	Seti                 // set reg C to val A (copy value)
+	Gtir                 // gt if val A > reg B { 1 -> reg C } else { 0 -> reg C }
+	Gtri                 // gt if reg A > val B { 1 -> reg C } else { 0 -> reg C }
+	Gtrr                 // gt if reg A > reg B { 1 -> reg C } else { 0 -> reg C }
+	Eqir                 // eq if val A == reg B { 1 -> reg C } else { 0 -> reg C }
+	Eqri                 // eq if reg A == val B { 1 -> reg C } else { 0 -> reg C }
+	Eqrr                 // eq if reg A == reg B { 1 -> reg C } else { 0 -> reg C }
+
+	NumOpcodes = 16 // how many opcodes there are
+)
+
+// OpCodeTypeToString - mnemonic for an opcode
+func OpCodeTypeToString(t OpcodeId) string {
+	switch t {
+	case Addr:
+		return "addr"
+	case Addi:
+		return "addi"
+	case Mulr:
+		return "mulr"
+	case Muli:
+		return "muli"
+	case Banr:
+		return "banr"
+	case Bani:
+		return "bani"
+	case Borr:
+		return "borr"
+	case Bori:
+		return "bori"
+	case Setr:
+		return "setr"
+	case Seti:
+		return "seti"
+	case Gtir:
+		return "gtir"
+	case Gtri:
+		return "gtri"
+	case Gtrr:
+		return "gtrr"
+	case Eqir:
+		return "eqir"
+	case Eqri:
+		return "eqri"
+	case Eqrr:
+		return "eqrr"
+	}
+	return "Unknown type"
+}
+
+// OpcodeFromString - opcode for a mnemonic, ok is false if there isn't one
+func OpcodeFromString(mnemonic string) (OpcodeId, bool) {
+	for op := OpcodeId(0); op < NumOpcodes; op++ {
+		if OpCodeTypeToString(op) == mnemonic {
+			return op, true
+		}
+	}
+	return 0, false
+}
+
+// RegisterOperands - are A and B register numbers (true) or values (false)
+// for +op+? C is always a register.
+func RegisterOperands(op OpcodeId) (a, b bool) {
+	switch op {
+	case Addr, Mulr, Banr, Borr, Gtrr, Eqrr:
+		return true, true
+	case Addi, Muli, Bani, Bori, Gtri, Eqri:
+		return true, false
+	case Gtir, Eqir:
+		return false, true
+	case Setr:
+		return true, false
+	}
+	// seti
+	return false, false
+}
+
+// Instruction - represents the instruction to perform
+type Instruction struct {
+	Opcode  OpcodeId // the opcode's ID that we'll be doing
+	A, B, C int      //inputs A & B, output C
+}
+
+// String - assembly-style listing of one instruction
+func (n *Instruction) String() string {
+	return fmt.Sprintf("%s %d %d %d", OpCodeTypeToString(n.Opcode), n.A, n.B, n.C)
+}
+
+// Execute the instruction against the registers in r, modifies
+// those registers according to the result of the instruction
+func (n *Instruction) Execute(r []int) string {
+
+	var debugDesc string
+
+	switch n.Opcode {
+	case Addr:
+		debugDesc = fmt.Sprintf("reg A (%d) + reg B (%d) -> reg C (%d) [%d + %d = ", n.A, n.B, n.C, r[n.A], r[n.B])
+		r[n.C] = r[n.A] + r[n.B]
+		debugDesc = fmt.Sprintf("%s%d]\n", debugDesc, r[n.C])
+	case Addi:
+		debugDesc = fmt.Sprintf("reg A (%d) + val B (%d) -> reg C (%d) [%d + %d = ", n.A, n.B, n.C, r[n.A], n.B)
+		r[n.C] = r[n.A] + n.B
+		debugDesc = fmt.Sprintf("%s%d]\n", debugDesc, r[n.C])
+	case Mulr:
+		debugDesc = fmt.Sprintf("reg A (%d) * reg B (%d) -> reg C (%d) [%d * %d = ", n.A, n.B, n.C, r[n.A], r[n.B])
+		r[n.C] = r[n.A] * r[n.B]
+		debugDesc = fmt.Sprintf("%s%d]\n", debugDesc, r[n.C])
+	case Muli:
+		debugDesc = fmt.Sprintf("reg A (%d) * val B (%d) -> reg C (%d) [%d * %d = ", n.A, n.B, n.C, r[n.A], n.B)
+		r[n.C] = r[n.A] * n.B
+		debugDesc = fmt.Sprintf("%s%d]\n", debugDesc, r[n.C])
+	case Banr:
+		debugDesc = fmt.Sprintf("reg A (%d) & reg B (%d) -> reg C (%d) [%04b & %04b = ", n.A, n.B, n.C, r[n.A], r[n.B])
+		r[n.C] = r[n.A] & r[n.B]
+		debugDesc = fmt.Sprintf("%s %04b (%d)]\n", debugDesc, r[n.C], r[n.C])
+	case Bani:
+		debugDesc = fmt.Sprintf("reg A (%d) & val B (%d) -> reg C (%d) [%04b & %04b = ", n.A, n.B, n.C, r[n.A], n.B)
+		r[n.C] = r[n.A] & n.B
+		debugDesc = fmt.Sprintf("%s %04b (%d)]\n", debugDesc, r[n.C], r[n.C])
+	case Borr:
+		debugDesc = fmt.Sprintf("reg A (%d) | reg B (%d) -> reg C (%d) [%04b | %04b =", n.A, n.B, n.C, r[n.A], r[n.B])
+		r[n.C] = r[n.A] | r[n.B]
+		debugDesc = fmt.Sprintf("%s %04b (%d)]\n", debugDesc, r[n.C], r[n.C])
+	case Bori:
+		debugDesc = fmt.Sprintf("reg A (%d) | val B (%d) -> reg C (%d) [%04b | %04b = ", n.A, n.B, n.C, r[n.A], n.B)
+		r[n.C] = r[n.A] | n.B
+		debugDesc = fmt.Sprintf("%s %04b (%d)]\n", debugDesc, r[n.C], r[n.C])
+	case Setr:
+		r[n.C] = r[n.A]
+		debugDesc = fmt.Sprintf("copy reg A (%d) contents to reg C (%d) [%d -> ", n.A, n.C, r[n.A])
+		debugDesc = fmt.Sprintf("%s%d]\n", debugDesc, r[n.C])
+	case Seti:
+		debugDesc = fmt.Sprintf("copy val A (%d) to reg C (%d) [%d -> ", n.A, n.C, n.A)
+		r[n.C] = n.A
+		debugDesc = fmt.Sprintf("%s%d]\n", debugDesc, r[n.C])
+	case Gtir:
+		debugDesc = fmt.Sprintf("if val A (%d) > reg B (%d); reg C %d = 1, else reg C (%d) = 0 [%d > %d -> ", n.A, n.B, n.C, n.C, n.A, r[n.B])
+		if n.A > r[n.B] {
+			r[n.C] = 1
+		} else {
+			r[n.C] = 0
+		}
+		debugDesc = fmt.Sprintf("%s%d]\n", debugDesc, r[n.C])
+	case Gtri:
+		debugDesc = fmt.Sprintf("if reg A (%d) > val B (%d); reg C %d = 1, else reg C (%d) = 0 [%d > %d -> ", n.A, n.B, n.C, n.C, r[n.A], n.B)
+		if r[n.A] > n.B {
+			r[n.C] = 1
+		} else {
+			r[n.C] = 0
+		}
+		debugDesc = fmt.Sprintf("%s%d]\n", debugDesc, r[n.C])
+	case Gtrr:
+		debugDesc = fmt.Sprintf("if reg A (%d) > reg B (%d); reg C %d = 1, else reg C (%d) = 0 [%d > %d -> ", n.A, n.B, n.C, n.C, r[n.A], r[n.B])
+		if r[n.A] > r[n.B] {
+			r[n.C] = 1
+		} else {
+			r[n.C] = 0
+		}
+		debugDesc = fmt.Sprintf("%s%d]\n", debugDesc, r[n.C])
+	case Eqir:
+		debugDesc = fmt.Sprintf("if val A (%d) == reg B (%d); reg C %d = 1, else reg C (%d) = 0 [%d == %d -> ", n.A, n.B, n.C, n.C, n.A, r[n.B])
+		if n.A == r[n.B] {
+			r[n.C] = 1
+		} else {
+			r[n.C] = 0
+		}
+		debugDesc = fmt.Sprintf("%s%d]\n", debugDesc, r[n.C])
+	case Eqri:
+		debugDesc = fmt.Sprintf("if reg A (%d) == val B (%d); reg C %d = 1, else reg C (%d) = 0 [%d == %d -> ", n.A, n.B, n.C, n.C, r[n.A], n.B)
+		if r[n.A] == n.B {
+			r[n.C] = 1
+		} else {
+			r[n.C] = 0
+		}
+		debugDesc = fmt.Sprintf("%s%d]\n", debugDesc, r[n.C])
+	case Eqrr:
+		debugDesc = fmt.Sprintf("if reg A (%d) == reg B (%d); reg C %d = 1, else reg C (%d) = 0 [%d == %d -> ", n.A, n.B, n.C, n.C, r[n.A], r[n.B])
+		if r[n.A] == r[n.B] {
+			r[n.C] = 1
+		} else {
+			r[n.C] = 0
+		}
+		debugDesc = fmt.Sprintf("%s%d]\n", debugDesc, r[n.C])
+	}
+	return debugDesc
+}
@@ -0,0 +1,169 @@
+package opvm
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Config - the shape of the machine a Program runs on
+type Config struct {
+	Registers  int // how many registers there are
+	IPRegister int // register bound to the instruction pointer, -1 for none
+}
+
+// DefaultConfig - the day 16 machine: four registers and no IP binding
+var DefaultConfig = Config{Registers: 4, IPRegister: -1}
+
+// Program - a program to run
+type Program struct {
+	Instructions []*Instruction // list of instructions
+	Registers    []int          // registers for the program
+	IP           int            // index of the next instruction to execute
+	IPRegister   int            // register bound to IP, or -1
+
+	shortcuts []*IdiomMatch // by instruction index, see Accelerate; nil once the program changes
+
+	Trace io.Writer // if set, each executed instruction is described here
+}
+
+// NewProgram - create a new Program structure for a machine shaped like +c+
+func NewProgram(c Config) *Program {
+	return &Program{
+		Instructions: make([]*Instruction, 0),
+		Registers:    make([]int, c.Registers),
+		IPRegister:   c.IPRegister,
+	}
+}
+
+// ParseAssembly - parse a program written with mnemonics, eg "seti 5 0 1",
+// one instruction per line. A "#ip N" line binds the instruction pointer to
+// register N.
+func ParseAssembly(text string, registers int) (*Program, error) {
+	p := NewProgram(Config{Registers: registers, IPRegister: -1})
+	for n, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "#ip" {
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: #ip takes one register", n+1)
+			}
+			register, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: couldn't parse #ip register %q", n+1, fields[1])
+			}
+			p.IPRegister = register
+			continue
+		}
+		op, ok := OpcodeFromString(fields[0])
+		if !ok || len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: expected opcode A B C, got %q", n+1, line)
+		}
+		var abc [3]int
+		for i := range abc {
+			v, err := strconv.Atoi(fields[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: couldn't parse %q", n+1, fields[i+1])
+			}
+			abc[i] = v
+		}
+		p.AddInstruction(op, abc[0], abc[1], abc[2])
+	}
+	return p, p.Validate()
+}
+
+// Validate - check every register referenced (including the IP binding)
+// exists on this machine
+func (p *Program) Validate() error {
+	valid := func(r int) bool { return r >= 0 && r < len(p.Registers) }
+	if p.IPRegister >= len(p.Registers) {
+		return fmt.Errorf("ip bound to r%d but there are only %d registers", p.IPRegister, len(p.Registers))
+	}
+	for i, instruction := range p.Instructions {
+		aReg, bReg := RegisterOperands(instruction.Opcode)
+		if instruction.Opcode >= NumOpcodes ||
+			(aReg && !valid(instruction.A)) || (bReg && !valid(instruction.B)) || !valid(instruction.C) {
+			return fmt.Errorf("instruction %d (%s) is invalid with %d registers", i, instruction, len(p.Registers))
+		}
+	}
+	return nil
+}
+
+// Reset - zero the registers and go back to the first instruction
+func (p *Program) Reset() {
+	for i := range p.Registers {
+		p.Registers[i] = 0
+	}
+	p.IP = 0
+}
+
+// Done - has the instruction pointer run off the end of the program?
+func (p *Program) Done() bool {
+	return p.IP < 0 || p.IP >= len(p.Instructions)
+}
+
+// Step - execute the instruction at IP and move to the next one. If IP is
+// bound to a register, the register is loaded with IP before the instruction
+// runs and IP is read back from it afterwards, so writing to it is a jump.
+// Returns the description of what the instruction did.
+func (p *Program) Step() string {
+	if len(p.shortcuts) == len(p.Instructions) && p.shortcuts[p.IP] != nil {
+		return p.shortcut(p.shortcuts[p.IP])
+	}
+	instruction := p.Instructions[p.IP]
+	if p.IPRegister >= 0 {
+		p.Registers[p.IPRegister] = p.IP
+	}
+	if p.Trace != nil {
+		fmt.Fprintf(p.Trace, "[%04d/%04d] Executing (%02d) %s A=%d, B=%d, C=%d Input registers = %d ", p.IP+1, len(p.Instructions), instruction.Opcode, OpCodeTypeToString(instruction.Opcode), instruction.A, instruction.B, instruction.C, p.Registers)
+	}
+	debugDesc := instruction.Execute(p.Registers)
+	if p.Trace != nil {
+		fmt.Fprintf(p.Trace, "Output registers = %d\n", p.Registers)
+		fmt.Fprintf(p.Trace, "↑ %s", debugDesc)
+	}
+	if p.IPRegister >= 0 {
+		p.IP = p.Registers[p.IPRegister]
+	}
+	p.IP++
+	return debugDesc
+}
+
+// shortcut - run an idiom natively and move past it. Registers end up as
+// running its instructions would have left them.
+func (p *Program) shortcut(m *IdiomMatch) string {
+	if p.Trace != nil {
+		fmt.Fprintf(p.Trace, "[%04d/%04d] Accelerated %s Input registers = %d ", p.IP+1, len(p.Instructions), m.Idiom.Name, p.Registers)
+	}
+	m.Idiom.Apply(p.Registers, m.Vars)
+	if p.IPRegister >= 0 {
+		p.Registers[p.IPRegister] = m.End - 1
+	}
+	p.IP = m.End
+	desc := m.Describe() + "\n"
+	if p.Trace != nil {
+		fmt.Fprintf(p.Trace, "Output registers = %d\n", p.Registers)
+		fmt.Fprintf(p.Trace, "↑ %s", desc)
+	}
+	return desc
+}
+
+// Execute - run the program from IP to the end
+func (p *Program) Execute() {
+	for !p.Done() {
+		p.Step()
+	}
+}
+
+// AddInstruction - add an instruction to the program. Any idioms found by
+// Accelerate are forgotten, as the new instruction may change them.
+func (p *Program) AddInstruction(opcode OpcodeId, a, b, c int) {
+	p.shortcuts = nil
+	p.Instructions = append(p.Instructions, &Instruction{
+		Opcode: opcode,
+		A:      a, B: b, C: c,
+	})
+}
@@ -6,108 +6,85 @@ import (
 	"os"
 	"strconv"
 	"strings"
-)
 
-const (
-	inputText  string = `1,0,0,3,1,1,2,3,1,3,4,3,1,5,0,3,2,9,1,19,1,19,5,23,1,9,23,27,2,27,6,31,1,5,31,35,2,9,35,39,2,6,39,43,2,43,13,47,2,13,47,51,1,10,51,55,1,9,55,59,1,6,59,63,2,63,9,67,1,67,6,71,1,71,13,75,1,6,75,79,1,9,79,83,2,9,83,87,1,87,6,91,1,91,13,95,2,6,95,99,1,10,99,103,2,103,9,107,1,6,107,111,1,10,111,115,2,6,115,119,1,5,119,123,1,123,13,127,1,127,5,131,1,6,131,135,2,135,13,139,1,139,2,143,1,143,10,0,99,2,0,14,0`
-	partBValue int    = 19690720
+	"aoc2019/day02"
+	"intcode"
 )
 
 var (
 	partB       = flag.Bool("partB", false, "Perform part B solution?")
 	inputFile   = flag.String("inputFile", "inputs/day02a.txt", "Input File")
-	inputString = flag.String("input", inputText, "Input string")
+	inputString = flag.String("input", day02.Input, "Input string")
 	debug       = flag.Bool("debug", false, "Debug?")
+	target      = flag.Int("target", day02.PartBValue, "Part B: value to look for at position 0")
+	nounRange   = flag.String("nouns", "0-99", "Part B: inclusive range of nouns to search")
+	verbRange   = flag.String("verbs", "0-99", "Part B: inclusive range of verbs to search")
+	workers     = flag.Int("workers", 0, "Part B: search goroutines (0 = one per CPU)")
+	allMatches  = flag.Bool("all", false, "Part B: report every match instead of stopping at the first?")
 )
 
-func copySlice(i []int) []int {
-	o := make([]int, len(i))
-	for i, v := range i {
-		o[i] = v
-	}
-	return o
-}
-
-// runProgram - runs a program, returns the finished product as output
-func runProgram(program []int) int {
-	if *debug {
-		fmt.Printf("Running program: %d\n", program)
+// parseRange - turn "low-high" into [low, high]
+func parseRange(r string) ([2]int, error) {
+	var ret [2]int
+	bounds := strings.Split(r, "-")
+	if len(bounds) != 2 {
+		return ret, fmt.Errorf("range %q should look like low-high", r)
 	}
-	cursor := 0
-	for {
-		var opcode, left, right, dest int
-		opcode = program[cursor]
-		if opcode == 99 {
-			if *debug {
-				fmt.Printf("Found opcode 99, returning...\n")
-			}
-			return program[0]
-		}
-		left = program[cursor+1]
-		right = program[cursor+2]
-		dest = program[cursor+3]
-
-		var result int
-		switch opcode {
-		case 1:
-			result = program[left] + program[right]
-			if *debug {
-				fmt.Printf("[cursor: %d; [%d, %d, %d, %d]; n: %d]: %d + %d = %d.\n",
-					cursor, opcode, left, right, dest, cursor+4, program[left], program[right], result)
-			}
-		case 2:
-			result = program[left] * program[right]
-			if *debug {
-				fmt.Printf("[cursor: %d; [%d, %d, %d, %d]; n: %d]: %d * %d = %d.\n",
-					cursor, opcode, left, right, dest, cursor+4, program[left], program[right], result)
-			}
+	for i, bound := range bounds {
+		n, err := strconv.Atoi(bound)
+		if err != nil {
+			return ret, fmt.Errorf("couldn't parse %q in range %q: %w", bound, r, err)
 		}
-		program[dest] = result
-		cursor += 4
+		ret[i] = n
 	}
+	return ret, nil
 }
 
 func main() {
 	flag.Parse()
+	day02.Debug = *debug
 
-	program := make([]int, 0)
-
-	for _, digit := range strings.Split(*inputString, ",") {
-		n, err := strconv.Atoi(digit)
-		if err != nil {
-			fmt.Printf("Couldn't parse %s: %e\n", digit, err)
-			os.Exit(1)
-		}
-		program = append(program, n)
+	program, err := intcode.Parse(*inputString)
+	if err != nil {
+		fmt.Printf("Couldn't parse program: %s\n", err)
+		os.Exit(1)
 	}
 
 	if !*partB {
 		// part A
-		// go through program
-
 		// Set for part A
-		program[1] = 12
-		program[2] = 2
-		result := runProgram(program)
+		result, err := day02.RunProgram(program, 12, 2)
+		if err != nil {
+			fmt.Printf("Program failed: %s\n", err)
+			os.Exit(1)
+		}
 		fmt.Printf("Part A value at position 0: %d\n", result)
 
 	} else {
 		// part B
-		for verb := 0; verb < 100; verb++ {
-			for noun := 0; noun < 100; noun++ {
-
-				programCopy := copySlice(program)
-				programCopy[1] = noun
-				programCopy[2] = verb
-				if *debug {
-					fmt.Printf("Verb %d, Noun %d. First 3 bytes: %d\n", verb, noun, programCopy[:3])
-				}
+		search := intcode.NewSearch(program, *target)
+		search.Workers = *workers
+		if search.Nouns, err = parseRange(*nounRange); err != nil {
+			fmt.Printf("Bad -nouns: %s\n", err)
+			os.Exit(1)
+		}
+		if search.Verbs, err = parseRange(*verbRange); err != nil {
+			fmt.Printf("Bad -verbs: %s\n", err)
+			os.Exit(1)
+		}
 
-				if result := runProgram(programCopy); result == partBValue {
-					fmt.Printf("Found %d: Verb %d, noun %d. 100 * noun + verb = %d\n", partBValue, verb, noun, 100*noun+verb)
-					os.Exit(0)
-				}
-			}
+		var matches []intcode.Match
+		if *allMatches {
+			matches = search.All()
+		} else if match := search.First(); match != nil {
+			matches = append(matches, *match)
+		}
+		if len(matches) == 0 {
+			fmt.Printf("Couldn't find %d with nouns %s and verbs %s\n", *target, *nounRange, *verbRange)
+			os.Exit(1)
+		}
+		for _, match := range matches {
+			fmt.Printf("Found %d: Verb %d, noun %d. 100 * noun + verb = %d\n", *target, match.Verb, match.Noun, 100*match.Noun+match.Verb)
 		}
 	}
 
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"intcode"
+)
+
+var (
+	assemble    = flag.Bool("assemble", false, "Assemble the input instead of disassembling it?")
+	inputFile   = flag.String("inputFile", "inputs/day02a.txt", "Input File")
+	inputString = flag.String("input", "", "Input string (takes precedence over -inputFile)")
+)
+
+func main() {
+	flag.Parse()
+
+	text := *inputString
+	if text == "" {
+		buf, err := ioutil.ReadFile(*inputFile)
+		if err != nil {
+			fmt.Printf("Couldn't read %s: %v\n", *inputFile, err)
+			os.Exit(1)
+		}
+		text = string(buf)
+	}
+
+	if !*assemble {
+		program, err := intcode.Parse(text)
+		if err != nil {
+			fmt.Printf("Couldn't parse program: %s\n", err)
+			os.Exit(1)
+		}
+		listing := intcode.Disassemble(program)
+		fmt.Print(listing)
+	} else {
+		program, err := intcode.Assemble(text)
+		if err != nil {
+			fmt.Printf("Couldn't assemble: %s\n", err)
+			os.Exit(1)
+		}
+		digits := make([]string, len(program))
+		for i, n := range program {
+			digits[i] = fmt.Sprintf("%d", n)
+		}
+		fmt.Println(strings.Join(digits, ","))
+	}
+
+	os.Exit(0)
+}
@@ -1,9 +1,30 @@
 #!/bin/bash
+# Start a day: src/aoc2019/dayNN/dayNN.go from template.go, registered with
+# the aoc runner by a blank import in src/aoc2019/aoc2019.go.
 
 DAY=$1
+if [ -z "${DAY}" ]; then
+	echo "usage: $0 day" >&2
+	exit 1
+fi
 paddedday="$(printf "%02d" ${DAY})"
+day="$((10#${paddedday}))"
 
+cd "$(dirname "$0")" || exit 1
+dir="src/aoc2019/day${paddedday}"
+if [ -e "${dir}" ]; then
+	echo "${dir} already exists" >&2
+	exit 1
+fi
+
+mkdir -p "${dir}" &&
 sed \
- -e "s/!DAY!/${paddedday}/g" \
- -e "s/MAIN/main/" \
-template.go > day${paddedday}.go
+ -e "s/DAYNN/day${paddedday}/g" \
+ -e "s/DAYNUMBER/${day}/g" \
+template.go > "${dir}/day${paddedday}.go" || exit 1
+
+# add the import; gofmt puts it in order
+if ! grep -q "\"aoc2019/day${paddedday}\"" src/aoc2019/aoc2019.go; then
+	sed -i -e "/^)/i\\	_ \"aoc2019/day${paddedday}\"" src/aoc2019/aoc2019.go &&
+	gofmt -w src/aoc2019/aoc2019.go
+fi
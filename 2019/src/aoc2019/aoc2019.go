@@ -0,0 +1,10 @@
+// Package aoc2019 - every 2019 day. Import it for its side effects to
+// register them all with the aoc package.
+package aoc2019
+
+import (
+	_ "aoc2019/day01"
+	_ "aoc2019/day02"
+	_ "aoc2019/day03"
+	_ "aoc2019/day04"
+)
@@ -0,0 +1,13 @@
+package aoc2019
+
+import (
+	"testing"
+
+	"aoc/aoctest"
+)
+
+// TestExamples - every answer file in 2019/inputs, the same as aoc verify
+// -year 2019
+func TestExamples(t *testing.T) {
+	aoctest.VerifyTest(t, 2019)
+}
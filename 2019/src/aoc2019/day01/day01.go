@@ -0,0 +1,95 @@
+// Package day01 - 2019 day 1: the fuel the spacecraft's modules need
+package day01
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.New(2019, 1, PartA, PartB))
+}
+
+// Debug - print the working
+var Debug = false
+
+// Fuel - the fuel needed to launch mass +m+
+func Fuel(m int) int {
+	return (int(math.Floor(float64(m)/3)) - 2)
+}
+
+// TotalFuel - the fuel needed to launch mass +m+, and its fuel, and that
+// fuel's fuel...
+func TotalFuel(m int) int {
+	ret := 0
+	newmass := Fuel(m)
+	if Debug {
+		fmt.Printf("Mass %d turns into %d\n", m, newmass)
+	}
+	for {
+		if newmass > 0 {
+			ret += newmass
+			if Debug {
+				fmt.Printf("Computing mass for fuel needed for %d\n", newmass)
+			}
+			newmass = Fuel(newmass)
+		} else {
+			if Debug {
+				fmt.Printf("Wishing really hard for mass %d\n", newmass)
+			}
+			return ret
+		}
+	}
+}
+
+// Parse - the module masses in +r+, one per line
+func Parse(r io.Reader) ([]int, error) {
+	masses := make([]int, 0)
+	lineReader := bufio.NewScanner(r)
+	for lineReader.Scan() {
+		line := strings.TrimSpace(lineReader.Text())
+		if line == "" {
+			continue
+		}
+		number, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %w", line, err)
+		}
+		masses = append(masses, number)
+	}
+	return masses, lineReader.Err()
+}
+
+// Sum - +compute+ for every mass in +masses+, added up
+func Sum(masses []int, compute func(int) int) int {
+	sum := 0
+	for _, m := range masses {
+		sum += compute(m)
+	}
+	return sum
+}
+
+// sumOf - read the masses in +r+ and Sum them with +compute+
+func sumOf(r io.Reader, compute func(int) int) (string, error) {
+	masses, err := Parse(r)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(Sum(masses, compute)), nil
+}
+
+// PartA - the fuel for the modules
+func PartA(r io.Reader) (string, error) {
+	return sumOf(r, Fuel)
+}
+
+// PartB - the fuel for the modules and the fuel
+func PartB(r io.Reader) (string, error) {
+	return sumOf(r, TotalFuel)
+}
@@ -0,0 +1,84 @@
+// Package day02 - 2019 day 2: the gravity assist program, on the intcode
+// computer
+package day02
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"aoc"
+	"intcode"
+)
+
+func init() {
+	aoc.Register(aoc.WithInput(2019, 2, Input, PartA, PartB))
+}
+
+const (
+	// Input - the puzzle input (also in inputs/day02a.txt)
+	Input string = `1,0,0,3,1,1,2,3,1,3,4,3,1,5,0,3,2,9,1,19,1,19,5,23,1,9,23,27,2,27,6,31,1,5,31,35,2,9,35,39,2,6,39,43,2,43,13,47,2,13,47,51,1,10,51,55,1,9,55,59,1,6,59,63,2,63,9,67,1,67,6,71,1,71,13,75,1,6,75,79,1,9,79,83,2,9,83,87,1,87,6,91,1,91,13,95,2,6,95,99,1,10,99,103,2,103,9,107,1,6,107,111,1,10,111,115,2,6,115,119,1,5,119,123,1,123,13,127,1,127,5,131,1,6,131,135,2,135,13,139,1,139,2,143,1,143,10,0,99,2,0,14,0`
+	// PartBValue - the output part B looks for
+	PartBValue int = 19690720
+)
+
+// Debug - print the program and trace it as it runs
+var Debug = false
+
+// RunProgram - runs a program with the given noun and verb, returns the value
+// left at position 0
+func RunProgram(program []int, noun, verb int) (int, error) {
+	if Debug {
+		fmt.Printf("Running program: %d\n", program)
+	}
+	m := intcode.New(program)
+	if Debug {
+		m.Trace = os.Stdout
+	}
+	if err := m.Poke(1, noun); err != nil {
+		return 0, err
+	}
+	if err := m.Poke(2, verb); err != nil {
+		return 0, err
+	}
+	if err := m.Run(); err != nil {
+		return 0, err
+	}
+	return m.Peek(0)
+}
+
+// read - the program in +r+
+func read(r io.Reader) ([]int, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return intcode.Parse(string(text))
+}
+
+// PartA - position 0 after running with noun 12 and verb 2
+func PartA(r io.Reader) (string, error) {
+	program, err := read(r)
+	if err != nil {
+		return "", err
+	}
+	result, err := RunProgram(program, 12, 2)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(result), nil
+}
+
+// PartB - 100 * noun + verb for the noun and verb that produce PartBValue
+func PartB(r io.Reader) (string, error) {
+	program, err := read(r)
+	if err != nil {
+		return "", err
+	}
+	match := intcode.NewSearch(program, PartBValue).First()
+	if match == nil {
+		return "", fmt.Errorf("no noun and verb produce %d", PartBValue)
+	}
+	return strconv.Itoa(100*match.Noun + match.Verb), nil
+}
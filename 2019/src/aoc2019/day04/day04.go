@@ -0,0 +1,126 @@
+// Package day04 - 2019 day 4: counting the passwords the Elf's facts allow
+package day04
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	aoc.Register(aoc.WithInput(2019, 4, "134792-675810", PartA, PartB))
+}
+
+// countDigits - how many digits are in the given integer?
+func countDigits(number int) int {
+	if number == 0 {
+		return 1
+	}
+	return int(math.Floor(math.Log10(math.Abs(float64(number)))) + 1)
+}
+
+// splitDigits - Splits +number+ into its digit components.
+// The return will be in the same order
+// 681901 will be returned [6, 8, 1, 9, 0, 1]
+func splitDigits(number int) []uint8 {
+	// we have nothing, so add nothing
+	if number == 0 {
+		return []uint8{0}
+	}
+	digitCount := countDigits(number)
+	ret := make([]uint8, digitCount)
+
+	i := number
+	for d := 0; d < digitCount; d++ {
+		digit := i % 10
+		ret[digitCount-d-1] = uint8(digit)
+		i -= digit
+		i /= 10
+	}
+	return ret
+}
+
+// ValidPassword - could +p+ be the password? It has six digits that never
+// decrease, and two adjacent digits the same. If +strict+ (part B), some pair
+// of them mustn't be part of a longer run.
+func ValidPassword(p int, strict bool) bool {
+	digits := splitDigits(p)
+	if len(digits) != 6 {
+		return false
+	}
+	lowestDigit := digits[0]
+	lastDigit := digits[0]
+	digitFreq := make(map[uint8]int)
+	for i, digit := range digits {
+		if digit < lastDigit || digit < lowestDigit {
+			// we went down
+			return false
+		}
+		if i > 0 && lastDigit == digit {
+			digitFreq[digit]++
+		}
+		lastDigit = digit
+	}
+	if !strict {
+		return len(digitFreq) != 0
+	}
+	for _, freq := range digitFreq {
+		if freq == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRange - the bounds of "lower-upper"
+func ParseRange(text string) (lower, upper int, err error) {
+	bounds := strings.Split(strings.TrimSpace(text), "-")
+	if len(bounds) != 2 {
+		return 0, 0, fmt.Errorf("range %q should look like lower-upper", text)
+	}
+	if lower, err = strconv.Atoi(bounds[0]); err != nil {
+		return 0, 0, fmt.Errorf("couldn't convert %s: %w", bounds[0], err)
+	}
+	if upper, err = strconv.Atoi(bounds[1]); err != nil {
+		return 0, 0, fmt.Errorf("couldn't convert %s: %w", bounds[1], err)
+	}
+	return lower, upper, nil
+}
+
+// Count - how many passwords from +lower+ to +upper+ are valid
+func Count(lower, upper int, strict bool) int {
+	valid := 0
+	for i := lower; i <= upper; i++ {
+		if ValidPassword(i, strict) {
+			valid++
+		}
+	}
+	return valid
+}
+
+// count - Count the passwords in the range in +r+
+func count(r io.Reader, strict bool) (string, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	lower, upper, err := ParseRange(string(text))
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(Count(lower, upper, strict)), nil
+}
+
+// PartA - how many passwords in the range are valid
+func PartA(r io.Reader) (string, error) {
+	return count(r, false)
+}
+
+// PartB - how many passwords in the range are valid with the stricter rule
+func PartB(r io.Reader) (string, error) {
+	return count(r, true)
+}
@@ -0,0 +1,185 @@
+package intcode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AssemblyError - a problem with one line of assembly
+type AssemblyError struct {
+	Line int // 1-based
+	Text string
+	Msg  string
+}
+
+func (e *AssemblyError) Error() string {
+	return fmt.Sprintf("line %d: %s (%q)", e.Line, e.Msg, e.Text)
+}
+
+// operand - a parsed parameter; either a number or a label resolved later
+type operand struct {
+	mode  Mode
+	value int
+	label string
+}
+
+// asmLine - one instruction or data line, waiting for labels to be resolved
+type asmLine struct {
+	number   int
+	text     string
+	info     *OpcodeInfo // nil for data
+	operands []*operand
+}
+
+// Assemble - turn the text produced by Disassemble (or written by hand in the
+// same syntax) into a program image. Labels are `name:` on their own line and
+// may be used anywhere a number may; `;` starts a comment.
+func Assemble(text string) ([]int, error) {
+	labels := make(map[string]int)
+	lines := make([]*asmLine, 0)
+	addr := 0
+
+	for n, raw := range strings.Split(text, "\n") {
+		line := raw
+		if i := strings.Index(line, ";"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasSuffix(line, ":") {
+			name := strings.TrimSuffix(line, ":")
+			if !isLabel(name) {
+				return nil, &AssemblyError{Line: n + 1, Text: raw, Msg: "bad label name"}
+			}
+			if _, ok := labels[name]; ok {
+				return nil, &AssemblyError{Line: n + 1, Text: raw, Msg: "label defined twice"}
+			}
+			labels[name] = addr
+			continue
+		}
+		parsed, err := parseLine(line)
+		if err != nil {
+			return nil, &AssemblyError{Line: n + 1, Text: raw, Msg: err.Error()}
+		}
+		parsed.number = n + 1
+		parsed.text = raw
+		lines = append(lines, parsed)
+		if parsed.info == nil {
+			addr += len(parsed.operands)
+		} else {
+			addr += 1 + parsed.info.Params
+		}
+	}
+
+	program := make([]int, 0, addr)
+	for _, line := range lines {
+		if line.info != nil {
+			modes := make([]Mode, len(line.operands))
+			for i, op := range line.operands {
+				modes[i] = op.mode
+			}
+			program = append(program, Encode(line.info.Opcode, modes))
+		}
+		for _, op := range line.operands {
+			value := op.value
+			if op.label != "" {
+				target, ok := labels[op.label]
+				if !ok {
+					return nil, &AssemblyError{Line: line.number, Text: line.text, Msg: "undefined label " + op.label}
+				}
+				value = target
+			}
+			program = append(program, value)
+		}
+	}
+	return program, nil
+}
+
+// parseLine - parse an instruction or data line with comments removed
+func parseLine(line string) (*asmLine, error) {
+	fields := strings.Fields(line)
+	mnemonic := fields[0]
+	rest := strings.TrimSpace(strings.TrimPrefix(line, mnemonic))
+
+	if mnemonic == "data" {
+		ret := &asmLine{operands: make([]*operand, 0)}
+		for _, field := range strings.Split(rest, ",") {
+			op, err := parseOperand(strings.TrimSpace(field))
+			if err != nil {
+				return nil, err
+			}
+			if op.mode != ModeImmediate {
+				return nil, fmt.Errorf("data must be numbers or labels")
+			}
+			ret.operands = append(ret.operands, op)
+		}
+		return ret, nil
+	}
+
+	info := LookupMnemonic(mnemonic)
+	if info == nil {
+		return nil, fmt.Errorf("unknown mnemonic %s, expected one of %s or data", mnemonic, strings.Join(Mnemonics(), ", "))
+	}
+	fields = fields[1:]
+	if info.Writes {
+		if len(fields) < 2 || fields[len(fields)-2] != "->" {
+			return nil, fmt.Errorf("%s needs `-> destination` as its last parameter", mnemonic)
+		}
+		fields = append(fields[:len(fields)-2], fields[len(fields)-1])
+	}
+	if len(fields) != info.Params {
+		return nil, fmt.Errorf("%s takes %d parameters, got %d", mnemonic, info.Params, len(fields))
+	}
+	ret := &asmLine{info: info, operands: make([]*operand, len(fields))}
+	for i, field := range fields {
+		op, err := parseOperand(field)
+		if err != nil {
+			return nil, err
+		}
+		if info.Writes && i == len(fields)-1 && op.mode == ModeImmediate {
+			return nil, fmt.Errorf("%s can't write to an immediate value", mnemonic)
+		}
+		ret.operands[i] = op
+	}
+	return ret, nil
+}
+
+// parseOperand - n, label, [n], [label] or [rb+n]
+func parseOperand(field string) (*operand, error) {
+	mode := ModeImmediate
+	if strings.HasPrefix(field, "[") && strings.HasSuffix(field, "]") {
+		field = field[1 : len(field)-1]
+		mode = ModePosition
+		if strings.HasPrefix(field, "rb") {
+			mode = ModeRelative
+			field = strings.TrimPrefix(field, "rb")
+			if field == "" {
+				field = "0"
+			}
+		}
+	}
+	if n, err := strconv.Atoi(field); err == nil {
+		return &operand{mode: mode, value: n}, nil
+	}
+	if mode != ModeRelative && isLabel(field) {
+		return &operand{mode: mode, label: field}, nil
+	}
+	return nil, fmt.Errorf("can't parse parameter %q", field)
+}
+
+// isLabel - labels start with a letter and contain letters, digits and _
+func isLabel(name string) bool {
+	if name == "" || name == "rb" || name == "data" {
+		return false
+	}
+	for i, c := range name {
+		letter := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+		if !letter && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,212 @@
+package intcode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// dataPerLine - how many values go on one `data` line
+const dataPerLine = 8
+
+// Encode - pack an opcode and its parameter modes into an instruction value
+func Encode(op Opcode, modes []Mode) int {
+	instruction := int(op)
+	scale := 100
+	for _, mode := range modes {
+		instruction += int(mode) * scale
+		scale *= 10
+	}
+	return instruction
+}
+
+// decodeAt - decode the instruction at +addr+ if it is one which can be
+// written back out exactly: a known opcode, valid modes, no stray digits and
+// all of its parameters inside the image.
+func decodeAt(program []int, addr int) (*OpcodeInfo, []Mode, bool) {
+	info, modes := Decode(program[addr])
+	if info == nil || addr+info.Params >= len(program) {
+		return nil, nil, false
+	}
+	if Encode(info.Opcode, modes) != program[addr] {
+		return nil, nil, false
+	}
+	for i, mode := range modes {
+		if mode > ModeRelative || (mode == ModeImmediate && info.Writes && i == info.Params-1) {
+			return nil, nil, false
+		}
+	}
+	return info, modes, true
+}
+
+// jumpTarget - where an instruction at +addr+ jumps to, if that's known
+// without running it. The second return is true if the jump is unconditional.
+func jumpTarget(program []int, addr int, info *OpcodeInfo, modes []Mode) (int, bool, bool) {
+	if !info.Jumps || modes[1] != ModeImmediate {
+		return 0, false, false
+	}
+	always := false
+	if modes[0] == ModeImmediate {
+		test := program[addr+1]
+		always = (info.Opcode == OpJumpIfTrue && test != 0) || (info.Opcode == OpJumpIfFalse && test == 0)
+	}
+	return program[addr+2], always, true
+}
+
+// findCode - follow the program from address 0 along fall-through and every
+// jump with an immediate target. Returns the start of every instruction
+// reached and every jump target seen.
+func findCode(program []int) (map[int]bool, map[int]bool) {
+	code := make(map[int]bool)
+	targets := make(map[int]bool)
+	work := []int{0}
+	for len(work) > 0 {
+		addr := work[len(work)-1]
+		work = work[:len(work)-1]
+		for addr >= 0 && addr < len(program) && !code[addr] {
+			info, modes, ok := decodeAt(program, addr)
+			if !ok {
+				break
+			}
+			code[addr] = true
+			if info.Opcode == OpHalt {
+				break
+			}
+			if target, always, ok := jumpTarget(program, addr, info, modes); ok {
+				targets[target] = true
+				work = append(work, target)
+				if always {
+					break
+				}
+			}
+			addr += 1 + info.Params
+		}
+	}
+	return code, targets
+}
+
+// item - one line of disassembly: an instruction or a run of data
+type item struct {
+	addr   int
+	length int
+	info   *OpcodeInfo // nil for data
+	modes  []Mode
+}
+
+// layout - split the program into instructions and data runs. A run of data
+// is broken wherever a jump lands so that the target can be labelled.
+func layout(program []int, code, targets map[int]bool) []*item {
+	items := make([]*item, 0)
+	var data *item
+	for addr := 0; addr < len(program); {
+		if code[addr] {
+			info, modes, _ := decodeAt(program, addr)
+			length := 1 + info.Params
+			overlaps := false
+			for inner := addr + 1; inner < addr+length; inner++ {
+				if code[inner] || targets[inner] {
+					overlaps = true
+				}
+			}
+			if !overlaps {
+				data = nil
+				items = append(items, &item{addr: addr, length: length, info: info, modes: modes})
+				addr += length
+				continue
+			}
+		}
+		if data == nil || targets[addr] || data.length == dataPerLine {
+			data = &item{addr: addr}
+			items = append(items, data)
+		}
+		data.length++
+		addr++
+	}
+	return items
+}
+
+// labelName - the label used for address +addr+
+func labelName(addr int, isCode bool) string {
+	if isCode {
+		return fmt.Sprintf("L%04d", addr)
+	}
+	return fmt.Sprintf("D%04d", addr)
+}
+
+// Disassemble - turn a program image into annotated assembly. Each
+// instruction is written as its mnemonic followed by its parameters, with
+// [n] for position mode, [rb+n] for relative mode and a bare n for immediate
+// mode; written destinations follow a `->`. Anything which isn't reachable
+// code is written as `data`. Jump targets and the data regions are labelled.
+// Assemble turns the output back into the same image.
+func Disassemble(program []int) string {
+	code, targets := findCode(program)
+	items := layout(program, code, targets)
+
+	labels := make(map[int]string)
+	previousData := false
+	for _, it := range items {
+		isData := it.info == nil
+		if targets[it.addr] || (isData && !previousData) {
+			labels[it.addr] = labelName(it.addr, !isData)
+		}
+		previousData = isData
+	}
+
+	var out strings.Builder
+	for _, it := range items {
+		if label, ok := labels[it.addr]; ok {
+			fmt.Fprintf(&out, "%s:\n", label)
+		}
+		raw := make([]string, it.length)
+		for i := range raw {
+			raw[i] = fmt.Sprintf("%d", program[it.addr+i])
+		}
+		var text string
+		if it.info == nil {
+			text = "data " + strings.Join(raw, ", ")
+		} else {
+			text = formatInstruction(program, it, labels)
+		}
+		fmt.Fprintf(&out, "\t%-36s ; %04d: %s\n", text, it.addr, strings.Join(raw, ","))
+	}
+	return out.String()
+}
+
+// formatInstruction - the assembly for one instruction
+func formatInstruction(program []int, it *item, labels map[int]string) string {
+	parts := []string{it.info.Mnemonic}
+	for i, mode := range it.modes {
+		value := program[it.addr+1+i]
+		if it.info.Writes && i == it.info.Params-1 {
+			parts = append(parts, "->")
+		}
+		switch mode {
+		case ModePosition:
+			parts = append(parts, fmt.Sprintf("[%d]", value))
+		case ModeRelative:
+			parts = append(parts, fmt.Sprintf("[rb%+d]", value))
+		case ModeImmediate:
+			if label, ok := labels[value]; ok && it.info.Jumps && i == 1 {
+				parts = append(parts, label)
+			} else {
+				parts = append(parts, fmt.Sprintf("%d", value))
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Mnemonics - every mnemonic, sorted by opcode
+func Mnemonics() []string {
+	ops := make([]int, 0, len(Opcodes))
+	for op := range Opcodes {
+		ops = append(ops, int(op))
+	}
+	sort.Ints(ops)
+	ret := make([]string, len(ops))
+	for i, op := range ops {
+		ret[i] = Opcodes[Opcode(op)].Mnemonic
+	}
+	return ret
+}
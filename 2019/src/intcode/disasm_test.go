@@ -0,0 +1,87 @@
+package intcode
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// roundTripPrograms - the published day 5 and day 9 examples, which between
+// them use every opcode and parameter mode
+var roundTripPrograms = map[string][]int{
+	"day05 equal to 8, position":   {3, 9, 8, 9, 10, 9, 4, 9, 99, -1, 8},
+	"day05 less than 8, immediate": {3, 3, 1107, -1, 8, 3, 4, 3, 99},
+	"day05 jumps, position":        {3, 12, 6, 12, 15, 1, 13, 14, 13, 4, 13, 99, -1, 0, 1, 9},
+	"day05 jumps, immediate":       {3, 3, 1105, -1, 9, 1101, 0, 0, 12, 4, 12, 99, 1},
+	"day05 compare with 8": {3, 21, 1008, 21, 8, 20, 1005, 20, 22, 107, 8, 21, 20, 1006, 20, 31,
+		1106, 0, 36, 98, 0, 0, 1002, 21, 125, 20, 4, 20, 1105, 1, 46, 104,
+		999, 1105, 1, 46, 1101, 1000, 1, 20, 4, 20, 1105, 1, 46, 98, 99},
+	"day09 quine":      {109, 1, 204, -1, 1001, 100, 1, 100, 1008, 100, 16, 101, 1006, 101, 0, 99},
+	"day09 16 digits":  {1102, 34915192, 34915192, 7, 4, 7, 99, 0},
+	"day09 big number": {104, 1125899906842624, 99},
+}
+
+func TestRoundTrip(t *testing.T) {
+	programs := map[string][]int{"day02 input": day02Input(t)}
+	for name, program := range roundTripPrograms {
+		programs[name] = program
+	}
+	for name, program := range programs {
+		listing := Disassemble(program)
+		again, err := Assemble(listing)
+		if err != nil {
+			t.Errorf("%s: %s\n%s", name, err, listing)
+			continue
+		}
+		if !slices.Equal(again, program) {
+			t.Errorf("%s: assembled to %v, want %v\n%s", name, again, program, listing)
+		}
+	}
+}
+
+func TestAssemble(t *testing.T) {
+	tests := []struct {
+		name, text string
+		want       []int
+	}{
+		{"position", "add [9] [10] -> [3]\nmul [3] [11] -> [0]\nhalt", []int{1, 9, 10, 3, 2, 3, 11, 0, 99}},
+		{"immediate", "add 1 2 -> [0]\nhalt", []int{1101, 1, 2, 0, 99}},
+		{"relative", "arb 5\nout [rb-2]\nin -> [rb+3]\nout [rb]\nhalt", []int{109, 5, 204, -2, 203, 3, 204, 0, 99}},
+		{"mixed modes", "lt [rb+1] 8 -> [rb+0]", []int{21207, 1, 8, 0}},
+		{"labels", "loop:\n\tjnz 1 loop ; forever\nend:\n\thalt", []int{1105, 1, 0, 99}},
+		{"label in memory", "jz [flag] end\nend:\nhalt\nflag:\ndata 0", []int{1006, 4, 3, 99, 0}},
+		{"data", "data 1, 2, end\nend:\nhalt", []int{1, 2, 3, 99}},
+	}
+	for _, test := range tests {
+		got, err := Assemble(test.text)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if !slices.Equal(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestAssembleErrors(t *testing.T) {
+	tests := []struct {
+		name, text string
+		line       int
+	}{
+		{"unknown mnemonic", "halt\nfoo 1", 2},
+		{"immediate destination", "add 1 2 -> 3", 1},
+		{"no destination", "in [0]", 1},
+		{"wrong parameter count", "out 1 2", 1},
+		{"label defined twice", "x:\nhalt\nx:", 3},
+		{"relative label", "out [rbx]", 1},
+		{"undefined label", "halt\njnz 1 nowhere", 2},
+	}
+	for _, test := range tests {
+		_, err := Assemble(test.text)
+		var asmErr *AssemblyError
+		if !errors.As(err, &asmErr) {
+			t.Errorf("%s: got %v, want an AssemblyError", test.name, err)
+		} else if asmErr.Line != test.line {
+			t.Errorf("%s: error on line %d, want %d: %s", test.name, asmErr.Line, test.line, err)
+		}
+	}
+}
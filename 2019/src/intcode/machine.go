@@ -0,0 +1,283 @@
+package intcode
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MaxMemory - the largest address a Machine will grow its memory to
+const MaxMemory = 1 << 24
+
+var (
+	// ErrUnknownOpcode - the instruction pointer landed on something that isn't an opcode
+	ErrUnknownOpcode = errors.New("unknown opcode")
+	// ErrInvalidMode - a parameter mode that isn't defined, or immediate mode on a write
+	ErrInvalidMode = errors.New("invalid parameter mode")
+	// ErrAddressOutOfRange - a read or write below 0 or at/above MaxMemory
+	ErrAddressOutOfRange = errors.New("address out of range")
+	// ErrNeedInput - an input instruction ran with nothing queued; queue some and Run again
+	ErrNeedInput = errors.New("no input available")
+	// ErrHalted - tried to step a machine which has already halted
+	ErrHalted = errors.New("machine has halted")
+	// ErrInputClosed - the channel a machine reads its input from was closed
+	ErrInputClosed = errors.New("input channel closed")
+)
+
+// Machine - an Intcode computer
+type Machine struct {
+	memory       []int
+	ip           int // instruction pointer
+	relativeBase int
+	halted       bool
+	input        []int // queued input, consumed from the front
+	output       []int // everything the program has written
+
+	// Where input comes from once the queue is empty, and where output goes
+	// once recorded. nil means queue only, see NewChannelMachine and Pipeline.
+	readInput   func() (int, error)
+	writeOutput func(int) error
+
+	Trace io.Writer // if set, every executed instruction is described here
+}
+
+// Parse - turn a comma-separated program into a slice of ints
+func Parse(text string) ([]int, error) {
+	program := make([]int, 0)
+	for _, digit := range strings.Split(strings.TrimSpace(text), ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(digit))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %q: %w", digit, err)
+		}
+		program = append(program, n)
+	}
+	return program, nil
+}
+
+// New - create a Machine loaded with a copy of +program+
+func New(program []int) *Machine {
+	memory := make([]int, len(program))
+	copy(memory, program)
+	return &Machine{
+		memory: memory,
+		input:  make([]int, 0),
+		output: make([]int, 0),
+	}
+}
+
+// NewChannelMachine - create a Machine which, once its queued input is used
+// up, blocks reading input from +in+ and sends each output value to +out+.
+// Run it on its own goroutine.
+func NewChannelMachine(program []int, in <-chan int, out chan<- int) *Machine {
+	m := New(program)
+	m.readInput = func() (int, error) {
+		value, ok := <-in
+		if !ok {
+			return 0, ErrInputClosed
+		}
+		return value, nil
+	}
+	m.writeOutput = func(value int) error {
+		out <- value
+		return nil
+	}
+	return m
+}
+
+// Halted - has the machine executed a halt instruction?
+func (m *Machine) Halted() bool {
+	return m.halted
+}
+
+// IP - current instruction pointer
+func (m *Machine) IP() int {
+	return m.ip
+}
+
+// RelativeBase - current relative base for relative mode parameters
+func (m *Machine) RelativeBase() int {
+	return m.relativeBase
+}
+
+// Memory - the machine's memory, including anything grown past the program image
+func (m *Machine) Memory() []int {
+	return m.memory
+}
+
+// AddInput - queue values for input instructions to read
+func (m *Machine) AddInput(values ...int) {
+	m.input = append(m.input, values...)
+}
+
+// Output - everything written by output instructions so far
+func (m *Machine) Output() []int {
+	return m.output
+}
+
+// Peek - read the value at +addr+. Addresses past the end of memory read as 0.
+func (m *Machine) Peek(addr int) (int, error) {
+	if addr < 0 || addr >= MaxMemory {
+		return 0, fmt.Errorf("read %d: %w", addr, ErrAddressOutOfRange)
+	}
+	if addr >= len(m.memory) {
+		return 0, nil
+	}
+	return m.memory[addr], nil
+}
+
+// Poke - write +value+ to +addr+, growing memory if required
+func (m *Machine) Poke(addr, value int) error {
+	if addr < 0 || addr >= MaxMemory {
+		return fmt.Errorf("write %d: %w", addr, ErrAddressOutOfRange)
+	}
+	if addr >= len(m.memory) {
+		grown := make([]int, addr+1)
+		copy(grown, m.memory)
+		m.memory = grown
+	}
+	m.memory[addr] = value
+	return nil
+}
+
+// address - resolve parameter +i+ (0-based) of the current instruction to an
+// address, for position and relative modes
+func (m *Machine) address(i int, mode Mode) (int, error) {
+	raw, err := m.Peek(m.ip + 1 + i)
+	if err != nil {
+		return 0, err
+	}
+	switch mode {
+	case ModePosition:
+		return raw, nil
+	case ModeRelative:
+		return m.relativeBase + raw, nil
+	}
+	return 0, fmt.Errorf("parameter %d mode %d at %d: %w", i+1, mode, m.ip, ErrInvalidMode)
+}
+
+// param - value of parameter +i+ (0-based) of the current instruction
+func (m *Machine) param(i int, mode Mode) (int, error) {
+	if mode == ModeImmediate {
+		return m.Peek(m.ip + 1 + i)
+	}
+	addr, err := m.address(i, mode)
+	if err != nil {
+		return 0, err
+	}
+	return m.Peek(addr)
+}
+
+// Step - execute one instruction
+func (m *Machine) Step() error {
+	if m.halted {
+		return ErrHalted
+	}
+	instruction, err := m.Peek(m.ip)
+	if err != nil {
+		return err
+	}
+	info, modes := Decode(instruction)
+	if info == nil {
+		return fmt.Errorf("%d at %d: %w", instruction, m.ip, ErrUnknownOpcode)
+	}
+
+	// Read all of the inputs ahead of time. The destination (if any) is
+	// resolved to an address instead.
+	params := make([]int, info.Params)
+	last := info.Params
+	if info.Writes {
+		last--
+	}
+	for i := 0; i < last; i++ {
+		if params[i], err = m.param(i, modes[i]); err != nil {
+			return err
+		}
+	}
+	dest := 0
+	if info.Writes {
+		if dest, err = m.address(last, modes[last]); err != nil {
+			return err
+		}
+	}
+
+	next := m.ip + 1 + info.Params
+	switch info.Opcode {
+	case OpAdd:
+		err = m.Poke(dest, params[0]+params[1])
+	case OpMultiply:
+		err = m.Poke(dest, params[0]*params[1])
+	case OpInput:
+		var value int
+		if value, err = m.read(); err != nil {
+			return err
+		}
+		err = m.Poke(dest, value)
+	case OpOutput:
+		if m.writeOutput != nil {
+			if err = m.writeOutput(params[0]); err != nil {
+				return err
+			}
+		}
+		m.output = append(m.output, params[0])
+	case OpJumpIfTrue:
+		if params[0] != 0 {
+			next = params[1]
+		}
+	case OpJumpIfFalse:
+		if params[0] == 0 {
+			next = params[1]
+		}
+	case OpLessThan:
+		err = m.Poke(dest, boolToInt(params[0] < params[1]))
+	case OpEquals:
+		err = m.Poke(dest, boolToInt(params[0] == params[1]))
+	case OpAdjustBase:
+		m.relativeBase += params[0]
+	case OpHalt:
+		m.halted = true
+		next = m.ip
+	}
+	if err != nil {
+		return err
+	}
+	if m.Trace != nil {
+		fmt.Fprintf(m.Trace, "[ip: %d; rb: %d] %s %d (dest %d) -> next %d\n",
+			m.ip, m.relativeBase, info.Mnemonic, params[:last], dest, next)
+	}
+	m.ip = next
+	return nil
+}
+
+// Run - step until the program halts or an instruction fails. If the program
+// is waiting on input ErrNeedInput is returned and Run may be called again
+// once some has been queued with AddInput.
+func (m *Machine) Run() error {
+	for !m.halted {
+		if err := m.Step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// read - next input value, from the queue first
+func (m *Machine) read() (int, error) {
+	if len(m.input) > 0 {
+		value := m.input[0]
+		m.input = m.input[1:]
+		return value, nil
+	}
+	if m.readInput != nil {
+		return m.readInput()
+	}
+	return 0, ErrNeedInput
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,114 @@
+package intcode
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+)
+
+// the day 2 examples: the program and its memory once it's halted
+var day02Examples = []struct {
+	program, want []int
+}{
+	{[]int{1, 9, 10, 3, 2, 3, 11, 0, 99, 30, 40, 50}, []int{3500, 9, 10, 70, 2, 3, 11, 0, 99, 30, 40, 50}},
+	{[]int{1, 0, 0, 0, 99}, []int{2, 0, 0, 0, 99}},
+	{[]int{2, 3, 0, 3, 99}, []int{2, 3, 0, 6, 99}},
+	{[]int{2, 4, 4, 5, 99, 0}, []int{2, 4, 4, 5, 99, 9801}},
+	{[]int{1, 1, 1, 4, 99, 5, 6, 0, 99}, []int{30, 1, 1, 4, 2, 5, 6, 0, 99}},
+}
+
+func TestDay02Examples(t *testing.T) {
+	for _, e := range day02Examples {
+		m := New(e.program)
+		if err := m.Run(); err != nil {
+			t.Errorf("%v: %s", e.program, err)
+			continue
+		}
+		if !slices.Equal(m.Memory(), e.want) {
+			t.Errorf("%v: memory %v, want %v", e.program, m.Memory(), e.want)
+		}
+		if !m.Halted() {
+			t.Errorf("%v: not halted", e.program)
+		}
+	}
+}
+
+// day02Input - the 2019 day 2 puzzle input
+func day02Input(t *testing.T) []int {
+	t.Helper()
+	text, err := os.ReadFile("../../inputs/day02a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	program, err := Parse(string(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return program
+}
+
+func TestDay02Input(t *testing.T) {
+	program := day02Input(t)
+
+	m := New(program)
+	if err := m.Poke(1, 12); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Poke(2, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := m.Peek(0); got != 6568671 {
+		t.Errorf("part a: got %d, want 6568671", got)
+	}
+
+	matches := NewSearch(program, 19690720).All()
+	if want := []Match{{39, 51}}; !slices.Equal(matches, want) {
+		t.Errorf("part b: got %v, want %v", matches, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		program []int
+		want    error
+	}{
+		{"unknown opcode", []int{42}, ErrUnknownOpcode},
+		{"read below 0", []int{1, -1, 0, 0, 99}, ErrAddressOutOfRange},
+		{"write past MaxMemory", []int{1, 0, 0, MaxMemory, 99}, ErrAddressOutOfRange},
+		{"immediate write", []int{11101, 1, 1, 0, 99}, ErrInvalidMode},
+		{"undefined mode", []int{301, 0, 0, 0, 99}, ErrInvalidMode},
+		{"no input", []int{3, 0, 99}, ErrNeedInput},
+	}
+	for _, test := range tests {
+		if err := New(test.program).Run(); !errors.Is(err, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, err, test.want)
+		}
+	}
+
+	m := New([]int{99})
+	if err := m.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Step(); !errors.Is(err, ErrHalted) {
+		t.Errorf("step after halting: got %v, want %v", err, ErrHalted)
+	}
+}
+
+func TestNeedInputResumes(t *testing.T) {
+	m := New([]int{3, 5, 4, 5, 99, 0})
+	if err := m.Run(); !errors.Is(err, ErrNeedInput) {
+		t.Fatalf("got %v, want %v", err, ErrNeedInput)
+	}
+	m.AddInput(7)
+	if err := m.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{7}; !slices.Equal(m.Output(), want) {
+		t.Errorf("output %v, want %v", m.Output(), want)
+	}
+}
@@ -0,0 +1,76 @@
+package intcode
+
+// Opcode - numerical ID for an instruction
+type Opcode int
+
+const (
+	OpAdd         Opcode = 1  // add [a] + [b] -> [c]
+	OpMultiply    Opcode = 2  // multiply [a] * [b] -> [c]
+	OpInput       Opcode = 3  // read one value from input -> [a]
+	OpOutput      Opcode = 4  // write [a] to output
+	OpJumpIfTrue  Opcode = 5  // if [a] != 0 { ip = [b] }
+	OpJumpIfFalse Opcode = 6  // if [a] == 0 { ip = [b] }
+	OpLessThan    Opcode = 7  // if [a] < [b] { 1 -> [c] } else { 0 -> [c] }
+	OpEquals      Opcode = 8  // if [a] == [b] { 1 -> [c] } else { 0 -> [c] }
+	OpAdjustBase  Opcode = 9  // relative base += [a]
+	OpHalt        Opcode = 99 // stop
+)
+
+// Mode - parameter mode, encoded in the hundreds digit and up of an instruction
+type Mode int
+
+const (
+	ModePosition  Mode = 0 // parameter is an address
+	ModeImmediate Mode = 1 // parameter is a value
+	ModeRelative  Mode = 2 // parameter is an address offset from the relative base
+)
+
+// OpcodeInfo - static description of an opcode. Both the Machine and the
+// disassembler work from this table so they can't disagree.
+type OpcodeInfo struct {
+	Opcode   Opcode
+	Mnemonic string
+	Params   int  // number of parameters following the opcode
+	Writes   bool // is the last parameter a write destination?
+	Jumps    bool // does the instruction (conditionally) move the instruction pointer?
+}
+
+// Opcodes - every opcode the Machine knows how to execute
+var Opcodes = map[Opcode]*OpcodeInfo{
+	OpAdd:         {Opcode: OpAdd, Mnemonic: "add", Params: 3, Writes: true},
+	OpMultiply:    {Opcode: OpMultiply, Mnemonic: "mul", Params: 3, Writes: true},
+	OpInput:       {Opcode: OpInput, Mnemonic: "in", Params: 1, Writes: true},
+	OpOutput:      {Opcode: OpOutput, Mnemonic: "out", Params: 1},
+	OpJumpIfTrue:  {Opcode: OpJumpIfTrue, Mnemonic: "jnz", Params: 2, Jumps: true},
+	OpJumpIfFalse: {Opcode: OpJumpIfFalse, Mnemonic: "jz", Params: 2, Jumps: true},
+	OpLessThan:    {Opcode: OpLessThan, Mnemonic: "lt", Params: 3, Writes: true},
+	OpEquals:      {Opcode: OpEquals, Mnemonic: "eq", Params: 3, Writes: true},
+	OpAdjustBase:  {Opcode: OpAdjustBase, Mnemonic: "arb", Params: 1},
+	OpHalt:        {Opcode: OpHalt, Mnemonic: "halt", Params: 0},
+}
+
+// LookupMnemonic - find the opcode for a mnemonic, returns nil if there isn't one
+func LookupMnemonic(mnemonic string) *OpcodeInfo {
+	for _, info := range Opcodes {
+		if info.Mnemonic == mnemonic {
+			return info
+		}
+	}
+	return nil
+}
+
+// Decode - split an instruction value into its opcode and the modes for each
+// of its parameters. Unknown opcodes are returned with a nil *OpcodeInfo.
+func Decode(instruction int) (*OpcodeInfo, []Mode) {
+	info, ok := Opcodes[Opcode(instruction%100)]
+	if !ok {
+		return nil, nil
+	}
+	modes := make([]Mode, info.Params)
+	packed := instruction / 100
+	for i := 0; i < info.Params; i++ {
+		modes[i] = Mode(packed % 10)
+		packed /= 10
+	}
+	return info, modes
+}
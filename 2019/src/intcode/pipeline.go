@@ -0,0 +1,191 @@
+package intcode
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// LinkBuffer - how many values may sit between two machines in a Pipeline
+const LinkBuffer = 1024
+
+var (
+	// ErrDeadlock - every machine in a Pipeline is halted or waiting on input that can't arrive
+	ErrDeadlock = errors.New("pipeline deadlocked")
+	// ErrStopped - a machine was interrupted because another in its Pipeline failed
+	ErrStopped = errors.New("pipeline stopped")
+)
+
+// Pipeline - machines run in series, each on its own goroutine, with the
+// output of machine i feeding the input of machine i+1. In feedback mode the
+// output of the last machine also feeds the first.
+type Pipeline struct {
+	machines []*Machine
+	feedback bool
+	links    []chan int      // links[i] carries input to machines[i]
+	halts    []chan struct{} // halts[i] is closed once machines[i] stops
+
+	mu      sync.Mutex
+	halted  []bool // machine has stopped running
+	blocked []bool // machine is waiting on its link
+	pending []int  // values sent to machine i's link and not yet read
+	stop    chan struct{}
+	stopErr error
+}
+
+// NewPipeline - wire +machines+ together. Any input already queued on a
+// machine (phase settings, for instance) is read before its link.
+func NewPipeline(feedback bool, machines ...*Machine) *Pipeline {
+	n := len(machines)
+	p := &Pipeline{
+		machines: machines,
+		feedback: feedback,
+		links:    make([]chan int, n),
+		halts:    make([]chan struct{}, n),
+		halted:   make([]bool, n),
+		blocked:  make([]bool, n),
+		pending:  make([]int, n),
+		stop:     make(chan struct{}),
+	}
+	for i := range machines {
+		p.links[i] = make(chan int, LinkBuffer)
+		p.halts[i] = make(chan struct{})
+	}
+	for i, m := range machines {
+		i := i
+		if i > 0 || feedback {
+			m.readInput = func() (int, error) { return p.receive(i) }
+		} else {
+			m.readInput = nil
+		}
+		if i < n-1 || feedback {
+			m.writeOutput = func(value int) error { return p.send((i+1)%n, value) }
+		} else {
+			m.writeOutput = nil
+		}
+	}
+	return p
+}
+
+// Run - start every machine and wait for them all to stop. Returns everything
+// the last machine wrote, or an error if any machine failed or the machines
+// deadlocked.
+func (p *Pipeline) Run() ([]int, error) {
+	if len(p.machines) == 0 {
+		return nil, nil
+	}
+	var wg sync.WaitGroup
+	for i, m := range p.machines {
+		wg.Add(1)
+		go func(i int, m *Machine) {
+			defer wg.Done()
+			p.finished(i, m.Run())
+		}(i, m)
+	}
+	wg.Wait()
+
+	if p.stopErr != nil {
+		return nil, p.stopErr
+	}
+	return p.machines[len(p.machines)-1].Output(), nil
+}
+
+// finished - record that machine +i+ has stopped, with +err+ if it failed
+func (p *Pipeline) finished(i int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.halted[i] = true
+	p.blocked[i] = false
+	close(p.halts[i])
+	if err != nil && !errors.Is(err, ErrStopped) && !errors.Is(err, ErrDeadlock) {
+		p.halt(fmt.Errorf("machine %d: %w", i, err))
+		return
+	}
+	p.checkDeadlock()
+}
+
+// halt - stop the whole pipeline with +err+. Caller must hold p.mu.
+func (p *Pipeline) halt(err error) {
+	if p.stopErr != nil {
+		return
+	}
+	p.stopErr = err
+	close(p.stop)
+}
+
+// checkDeadlock - if nothing can make progress, stop the pipeline. Caller
+// must hold p.mu.
+func (p *Pipeline) checkDeadlock() {
+	waiting := make([]int, 0)
+	for i := range p.machines {
+		if p.halted[i] {
+			continue
+		}
+		if !p.blocked[i] || p.pending[i] > 0 {
+			// still running, or about to be
+			return
+		}
+		waiting = append(waiting, i)
+	}
+	if len(waiting) > 0 {
+		p.halt(fmt.Errorf("machines %d waiting on input: %w", waiting, ErrDeadlock))
+	}
+}
+
+// send - deliver +value+ to machine +i+. Values for a machine which has
+// stopped are dropped; they're still in the sender's Output().
+func (p *Pipeline) send(i, value int) error {
+	p.mu.Lock()
+	if p.halted[i] {
+		p.mu.Unlock()
+		return nil
+	}
+	p.pending[i]++
+	p.mu.Unlock()
+
+	select {
+	case p.links[i] <- value:
+		return nil
+	case <-p.halts[i]:
+		return nil
+	case <-p.stop:
+		return ErrStopped
+	}
+}
+
+// receive - block until a value arrives for machine +i+
+func (p *Pipeline) receive(i int) (int, error) {
+	p.mu.Lock()
+	p.blocked[i] = true
+	p.checkDeadlock()
+	p.mu.Unlock()
+
+	select {
+	case value := <-p.links[i]:
+		p.mu.Lock()
+		p.blocked[i] = false
+		p.pending[i]--
+		p.mu.Unlock()
+		return value, nil
+	case <-p.stop:
+		if errors.Is(p.stopErr, ErrDeadlock) {
+			return 0, ErrDeadlock
+		}
+		return 0, ErrStopped
+	}
+}
+
+// Chain - run one copy of +program+ per entry in +settings+, each given its
+// setting as its first input, in series or as a feedback loop. +input+ is
+// queued on the first machine after its setting.
+func Chain(program []int, settings []int, feedback bool, input ...int) ([]int, error) {
+	machines := make([]*Machine, len(settings))
+	for i, setting := range settings {
+		machines[i] = New(program)
+		machines[i].AddInput(setting)
+	}
+	if len(machines) > 0 {
+		machines[0].AddInput(input...)
+	}
+	return NewPipeline(feedback, machines...).Run()
+}
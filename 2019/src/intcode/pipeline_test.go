@@ -0,0 +1,87 @@
+package intcode
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// runPipeline - run +p+, failing the test rather than hanging if it doesn't
+// stop
+func runPipeline(t *testing.T, p *Pipeline) ([]int, error) {
+	t.Helper()
+	type result struct {
+		output []int
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		output, err := p.Run()
+		done <- result{output, err}
+	}()
+	select {
+	case r := <-done:
+		return r.output, r.err
+	case <-time.After(5 * time.Second):
+		t.Fatal("pipeline still running after 5s")
+	}
+	return nil, nil
+}
+
+func TestChain(t *testing.T) {
+	tests := []struct {
+		name     string
+		program  []int
+		settings []int
+		feedback bool
+		want     int
+	}{
+		{"series", []int{3, 15, 3, 16, 1002, 16, 10, 16, 1, 16, 15, 15, 4, 15, 99, 0, 0}, []int{4, 3, 2, 1, 0}, false, 43210},
+		{"series, more steps", []int{3, 23, 3, 24, 1002, 24, 10, 24, 1002, 23, -1, 23, 101, 5, 23, 23, 1, 24, 23, 23, 4, 23, 99, 0, 0},
+			[]int{0, 1, 2, 3, 4}, false, 54321},
+		{"feedback", []int{3, 26, 1001, 26, -4, 26, 3, 27, 1002, 27, 2, 27, 1, 27, 26, 27, 4, 27, 1001, 28, -1, 28, 1005, 28, 6, 99, 0, 0, 5},
+			[]int{9, 8, 7, 6, 5}, true, 139629729},
+		{"feedback, more steps", []int{3, 52, 1001, 52, -5, 52, 3, 53, 1, 52, 56, 54, 1007, 54, 5, 55, 1005, 55, 26, 1001, 54,
+			-5, 54, 1105, 1, 12, 1, 53, 54, 53, 1008, 54, 0, 55, 1001, 55, 1, 55, 2, 53, 55, 53, 4,
+			53, 1001, 56, -1, 56, 1005, 56, 6, 99, 0, 0, 0, 0, 10}, []int{9, 7, 8, 5, 6}, true, 18216},
+	}
+	for _, test := range tests {
+		output, err := Chain(test.program, test.settings, test.feedback, 0)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if len(output) == 0 || output[len(output)-1] != test.want {
+			t.Errorf("%s: last machine wrote %v, want it to end with %d", test.name, output, test.want)
+		}
+	}
+}
+
+func TestPipelineDeadlock(t *testing.T) {
+	// both read before they write, so neither ever gets anything
+	echo := []int{3, 0, 4, 0, 99}
+	_, err := runPipeline(t, NewPipeline(true, New(echo), New(echo)))
+	if !errors.Is(err, ErrDeadlock) {
+		t.Errorf("got %v, want %v", err, ErrDeadlock)
+	}
+}
+
+func TestPipelineMachineFails(t *testing.T) {
+	// the first writes, then waits for the second, which reads and then
+	// hits an opcode that doesn't exist
+	first := New([]int{104, 1, 3, 0, 99})
+	second := New([]int{3, 0, 42})
+	_, err := runPipeline(t, NewPipeline(true, first, second))
+	if !errors.Is(err, ErrUnknownOpcode) {
+		t.Errorf("got %v, want %v", err, ErrUnknownOpcode)
+	}
+	if first.Halted() {
+		t.Errorf("the waiting machine halted rather than being stopped")
+	}
+
+	// a series pipeline's first machine has nothing to read from but its queue
+	_, err = runPipeline(t, NewPipeline(false, New([]int{3, 0, 99}), New([]int{3, 0, 99})))
+	if !errors.Is(err, ErrNeedInput) {
+		t.Errorf("first machine without input: got %v, want %v", err, ErrNeedInput)
+	}
+}
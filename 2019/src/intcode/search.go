@@ -0,0 +1,117 @@
+package intcode
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Match - a noun and verb which made a program produce the target
+type Match struct {
+	Noun, Verb int
+}
+
+// Search - look for nouns and verbs (the values at addresses 1 and 2) which
+// leave Target at address 0 once Program halts. Candidates are spread over
+// Workers goroutines.
+type Search struct {
+	Program []int
+	Nouns   [2]int // inclusive range of nouns to try
+	Verbs   [2]int // inclusive range of verbs to try
+	Target  int
+	Workers int // how many goroutines to use; runtime.NumCPU() if < 1
+}
+
+// NewSearch - a Search over nouns and verbs 0-99 inclusive
+func NewSearch(program []int, target int) *Search {
+	return &Search{
+		Program: program,
+		Nouns:   [2]int{0, 99},
+		Verbs:   [2]int{0, 99},
+		Target:  target,
+	}
+}
+
+// All - every match in the search range, sorted by noun then verb
+func (s *Search) All() []Match {
+	return s.run(false)
+}
+
+// First - stop as soon as any worker finds a match. Returns nil if there
+// isn't one. With more than one worker this isn't necessarily the lowest
+// noun/verb pair.
+func (s *Search) First() *Match {
+	matches := s.run(true)
+	if len(matches) == 0 {
+		return nil
+	}
+	return &matches[0]
+}
+
+// Try - run one candidate. Candidates which make the program fail count as
+// a miss.
+func (s *Search) Try(noun, verb int) bool {
+	m := New(s.Program)
+	if m.Poke(1, noun) != nil || m.Poke(2, verb) != nil || m.Run() != nil {
+		return false
+	}
+	result, err := m.Peek(0)
+	return err == nil && result == s.Target
+}
+
+// run - feed every candidate to the workers, collecting matches. If
+// +stopEarly+ no new candidates are handed out once one has matched.
+func (s *Search) run(stopEarly bool) []Match {
+	workers := s.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+
+	candidates := make(chan Match)
+	done := make(chan struct{})
+	var (
+		mu      sync.Mutex
+		once    sync.Once
+		matches = make([]Match, 0)
+		wg      sync.WaitGroup
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range candidates {
+				if !s.Try(c.Noun, c.Verb) {
+					continue
+				}
+				mu.Lock()
+				matches = append(matches, c)
+				mu.Unlock()
+				if stopEarly {
+					once.Do(func() { close(done) })
+				}
+			}
+		}()
+	}
+
+feed:
+	for noun := s.Nouns[0]; noun <= s.Nouns[1]; noun++ {
+		for verb := s.Verbs[0]; verb <= s.Verbs[1]; verb++ {
+			select {
+			case candidates <- Match{Noun: noun, Verb: verb}:
+			case <-done:
+				break feed
+			}
+		}
+	}
+	close(candidates)
+	wg.Wait()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Noun != matches[j].Noun {
+			return matches[i].Noun < matches[j].Noun
+		}
+		return matches[i].Verb < matches[j].Verb
+	})
+	return matches
+}
@@ -1,27 +1,26 @@
-package MAIN
+// Package DAYNN - 2019 day DAYNUMBER
+package DAYNN
 
 import (
-	"flag"
-	"fmt"
-	"os"
-)
+	"errors"
+	"io"
 
-var (
-	partB       = flag.Bool("partB", false, "Perform part B solution?")
-	inputFile   = flag.String("inputFile", "inputs/day!DAY!a.txt", "Input File")
-	inputString = flag.String("input", "", "Input string")
-	debug       = flag.Bool("debug", false, "Debug?")
+	"aoc"
 )
 
-func main() {
-	flag.Parse()
+func init() {
+	aoc.Register(aoc.New(2019, DAYNUMBER, PartA, PartB))
+}
+
+// Debug - print the working
+var Debug = false
 
-	if !*partB {
-		// part A
-		fmt.Printf("Part A")
-	} else {
-		// part B
-	}
+// PartA -
+func PartA(r io.Reader) (string, error) {
+	return "", errors.New("part A isn't solved yet")
+}
 
-	os.Exit(0)
+// PartB -
+func PartB(r io.Reader) (string, error) {
+	return "", errors.New("part B isn't solved yet")
 }
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"aoc"
+)
+
+func init() {
+	addCommand(&command{name: "bench", summary: "time the solutions and compare them with a baseline", run: bench})
+}
+
+// readInput - all of the input OpenInput finds for +part+ of +s+
+func readInput(root string, s aoc.Solver, part string) ([]byte, error) {
+	input, _, err := aoc.OpenInput(root, s, part)
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+	return io.ReadAll(input)
+}
+
+// change - how +is+ compares with +was+, as a percentage
+func change(was, is float64) string {
+	if was == 0 {
+		return "new"
+	}
+	return fmt.Sprintf("%+.0f%%", 100*(is/was-1))
+}
+
+// markdown - +report+ as a Markdown table, with the change in each median
+// since +baseline+ if there is one and the +regressions+ it found in bold
+func markdown(w io.Writer, report, baseline *aoc.BenchReport, regressions []*aoc.Regression, runs int, budget time.Duration) {
+	fmt.Fprintf(w, "# Benchmarks\n\n")
+	fmt.Fprintf(w, "%s, %s on %s: up to %d runs of each part", report.Time.Format(time.DateTime), report.Go, report.Arch, runs)
+	if budget > 0 {
+		fmt.Fprintf(w, ", or as many as fit in %s", budget)
+	}
+	fmt.Fprintf(w, ".\n\n")
+
+	header := "| Puzzle | Part | Runs | Median | Min | Allocs | Allocated | Peak heap |"
+	rule := "|---|---|--:|--:|--:|--:|--:|--:|"
+	if baseline != nil {
+		header += " vs baseline |"
+		rule += "--:|"
+	}
+	fmt.Fprintf(w, "%s\n%s\n", header, rule)
+	regressed := make(map[*aoc.Bench][]*aoc.Regression)
+	for _, r := range regressions {
+		regressed[r.Bench] = append(regressed[r.Bench], r)
+	}
+	for _, b := range report.Benches {
+		fmt.Fprintf(w, "| %d day %d | %s | %d |", b.Year, b.Day, b.Part, b.Runs)
+		if b.Err != "" {
+			fmt.Fprintf(w, " failed: %s | | | | |", strings.ReplaceAll(b.Err, "|", `\|`))
+		} else {
+			fmt.Fprintf(w, " %s | %s | %d | %s | %s |", aoc.FormatDuration(b.Median), aoc.FormatDuration(b.Min),
+				b.Allocs, aoc.FormatBytes(b.AllocBytes), aoc.FormatBytes(b.PeakBytes))
+		}
+		if baseline != nil {
+			vs := ""
+			if old := baseline.Find(b.Year, b.Day, b.Part); old != nil && old.Runs > 0 && b.Runs > 0 {
+				vs = change(float64(old.Median), float64(b.Median))
+			}
+			for _, r := range regressed[b] {
+				if r.Metric == "time" {
+					vs = "**" + vs + "**"
+				} else {
+					vs += fmt.Sprintf(", **%s %s**", r.Metric, change(r.Old, r.New))
+				}
+			}
+			fmt.Fprintf(w, " %s |", vs)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if baseline != nil {
+		fmt.Fprintf(w, "\n## Regressions since %s\n\n", baseline.Time.Format(time.DateTime))
+		if len(regressions) == 0 {
+			fmt.Fprintf(w, "None.\n")
+		}
+		for _, r := range regressions {
+			fmt.Fprintf(w, "- %s\n", r)
+		}
+	}
+}
+
+func bench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	year := fs.Int("year", 0, "only time this year (default all)")
+	day := fs.Int("day", 0, "only time this day (default all)")
+	part := fs.String("part", "", "part to time, a or b (default both)")
+	runs := fs.Int("n", 5, "how many times to run each part")
+	budget := fs.Duration("budget", 30*time.Second, "stop running a part once its runs have taken this long (0 for no limit)")
+	root := fs.String("root", "", "repository root (default: found from the working directory)")
+	mdFile := fs.String("md", "", "write a Markdown report here")
+	jsonFile := fs.String("json", "", "write a JSON report here, which can be a -baseline later")
+	baselineFile := fs.String("baseline", "", "JSON report to compare with")
+	threshold := fs.Float64("threshold", 0.2, "how much worse than the baseline (0.2 for 20%) counts as a regression")
+	fs.Parse(args)
+
+	if *runs < 1 {
+		return errors.New("-n has to be at least 1")
+	}
+	if *threshold < 0 {
+		return errors.New("-threshold can't be negative")
+	}
+	ps, err := parts(*part)
+	if err != nil {
+		return err
+	}
+	var baseline *aoc.BenchReport
+	if *baselineFile != "" {
+		if baseline, err = aoc.LoadBenchReport(*baselineFile); err != nil {
+			return err
+		}
+	}
+
+	report := aoc.NewBenchReport()
+	for _, y := range years(*year) {
+		dir, err := findRoot(*root, y)
+		if err != nil {
+			return err
+		}
+		for _, s := range aoc.Solvers(y) {
+			if *day != 0 && s.Day() != *day {
+				continue
+			}
+			for _, p := range ps {
+				var b *aoc.Bench
+				if input, err := readInput(dir, s, p); err != nil {
+					b = &aoc.Bench{Year: s.Year(), Day: s.Day(), Part: p, Err: err.Error()}
+				} else {
+					b = aoc.Measure(s, p, input, *runs, *budget)
+				}
+				report.Benches = append(report.Benches, b)
+				if b.Err != "" {
+					fmt.Printf("%s: failed: %s\n", b.Name(), b.Err)
+					continue
+				}
+				fmt.Printf("%s: %s median of %d, %s allocated, %s peak heap\n", b.Name(),
+					aoc.FormatDuration(b.Median), b.Runs, aoc.FormatBytes(b.AllocBytes), aoc.FormatBytes(b.PeakBytes))
+			}
+		}
+	}
+
+	var regressions []*aoc.Regression
+	if baseline != nil {
+		regressions = aoc.Compare(baseline, report, *threshold)
+	}
+	if *jsonFile != "" {
+		if err := report.Save(*jsonFile); err != nil {
+			return err
+		}
+	}
+	if *mdFile != "" {
+		var md strings.Builder
+		markdown(&md, report, baseline, regressions, *runs, *budget)
+		if err := os.WriteFile(*mdFile, []byte(md.String()), 0644); err != nil {
+			return err
+		}
+	}
+	if len(regressions) > 0 {
+		for _, r := range regressions {
+			fmt.Printf("regressed: %s\n", r)
+		}
+		return fmt.Errorf("%d regressions beyond %.0f%%", len(regressions), 100**threshold)
+	}
+	return nil
+}
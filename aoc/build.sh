@@ -0,0 +1,10 @@
+#!/bin/bash
+# Build the aoc command. Each year is its own GOPATH tree, so put all three
+# on the GOPATH (ahead of the usual one, which has the third party packages).
+
+root="$(cd "$(dirname "$0")/.." && pwd)"
+gopath="$(go env GOPATH)"
+
+cd "${root}/aoc" &&
+	GOPATH="${root}/2017:${root}/2018:${root}/2019:${gopath}" GO111MODULE=off \
+		go build -o aoc "$@" .
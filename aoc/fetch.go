@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"aoc"
+	"aoc/fake"
+)
+
+func init() {
+	addCommand(&command{name: "fetch", summary: "download a day's input into inputs/, unless it's already there", run: fetch})
+}
+
+// clientFlags - the flags every command that talks to the site has
+type clientFlags struct {
+	config, session *string
+	fake, verbose   *bool
+	interval        *time.Duration
+}
+
+func addClientFlags(fs *flag.FlagSet) *clientFlags {
+	return &clientFlags{
+		config:   fs.String("config", "", "config file with the session cookie (default: aoc/config in the user config directory)"),
+		session:  fs.String("session", "", "session cookie to use instead of the config's"),
+		fake:     fs.Bool("fake", false, "talk to a stand in for the site, running in this process, instead of the real one"),
+		verbose:  fs.Bool("v", false, "describe every request"),
+		interval: fs.Duration("interval", 0, fmt.Sprintf("least time between requests (default %s, or the fake server's limit)", aoc.DefaultInterval)),
+	}
+}
+
+// client - a Client as the flags describe, and (with -fake) the fake server
+// it talks to, which the caller has to Close
+func (f *clientFlags) client() (*aoc.Client, *fake.Server, error) {
+	var client *aoc.Client
+	var server *fake.Server
+	if *f.fake {
+		server = fake.New()
+		server.MinInterval = time.Second
+		client = aoc.NewClient(&aoc.Config{Session: fake.Session, BaseURL: server.URL})
+		client.Interval = server.MinInterval
+		// the fake server's requests don't count against the real site's
+		client.StateFile = ""
+		if *f.session != "" {
+			client.Session = *f.session
+		}
+	} else {
+		config, err := f.loadConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+		client = aoc.NewClient(config)
+	}
+	if *f.interval > 0 {
+		client.Interval = *f.interval
+	}
+	if *f.verbose {
+		client.Trace = os.Stdout
+	}
+	return client, server, nil
+}
+
+// loadConfig - the config file's settings, with -session taking precedence
+func (f *clientFlags) loadConfig() (*aoc.Config, error) {
+	path := *f.config
+	if path == "" {
+		var err error
+		if path, err = aoc.DefaultConfigPath(); err != nil {
+			return nil, err
+		}
+	}
+	config, err := aoc.LoadConfig(path)
+	switch {
+	case err == nil:
+		if *f.session != "" {
+			config.Session = *f.session
+		}
+		return config, nil
+	case errors.Is(err, os.ErrNotExist) && *f.session != "":
+		return &aoc.Config{Session: *f.session}, nil
+	case errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("no session: put \"session = <cookie>\" in %s or use -session", path)
+	}
+	return nil, err
+}
+
+// days - +day+, or if it's 0 every day of +year+ with a solution that
+// reads its input from a file
+func days(year, day int) []int {
+	if day != 0 {
+		return []int{day}
+	}
+	var ret []int
+	for _, s := range aoc.Solvers(year) {
+		if d, ok := s.(aoc.Defaulter); ok && d.DefaultInput() != "" {
+			continue
+		}
+		ret = append(ret, s.Day())
+	}
+	return ret
+}
+
+func fetch(args []string) error {
+	fs := flag.NewFlagSet("fetch", flag.ExitOnError)
+	year := fs.Int("year", 0, "puzzle year")
+	day := fs.Int("day", 0, "puzzle day (default every day with a solution that needs an input)")
+	root := fs.String("root", "", "repository root (default: found from the working directory; a new temporary directory with -fake)")
+	cf := addClientFlags(fs)
+	fs.Parse(args)
+
+	if *year == 0 {
+		return errors.New("fetch needs -year")
+	}
+	client, server, err := cf.client()
+	if err != nil {
+		return err
+	}
+	dir := *root
+	if server != nil {
+		defer server.Close()
+		for d := 1; d <= 25; d++ {
+			server.SetInput(*year, d, fmt.Sprintf("fake input for %d day %d\n", *year, d))
+		}
+		if dir == "" {
+			if dir, err = os.MkdirTemp("", "aoc-fake-"); err != nil {
+				return err
+			}
+			fmt.Printf("fake inputs go in %s\n", dir)
+		}
+	} else if dir, err = findRoot(dir, *year); err != nil {
+		return err
+	}
+
+	for _, d := range days(*year, *day) {
+		path, fetched, err := aoc.FetchInput(client, dir, *year, d)
+		if err != nil {
+			return fmt.Errorf("%d day %d: %w", *year, d, err)
+		}
+		if fetched {
+			fmt.Printf("%d day %d: fetched %s\n", *year, d, path)
+		} else {
+			fmt.Printf("%d day %d: already have %s\n", *year, d, path)
+		}
+	}
+	return nil
+}
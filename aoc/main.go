@@ -0,0 +1,82 @@
+// aoc - run any day's solution from one command.
+//
+//	aoc run -year 2018 -day 9 -part b
+//	aoc list [-year 2018]
+//	aoc fetch -year 2018 -day 9
+//	aoc submit -year 2018 -day 9 -part a
+//	aoc bench -year 2018 -n 10 -json bench.json -md bench.md
+//
+// Every day registers itself with the aoc package (see 2017/src/aoc), and the
+// year packages pull them all in. Build it with build.sh, which puts the three
+// years' trees on the GOPATH.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"aoc"
+	_ "aoc2017"
+	_ "aoc2018"
+	_ "aoc2019"
+)
+
+// command - a subcommand: its name, what it does and how to run it with the
+// arguments after its name
+type command struct {
+	name, summary string
+	run           func(args []string) error
+}
+
+var commands = map[string]*command{}
+
+// addCommand - make +c+ available as "aoc +c.name+"
+func addCommand(c *command) {
+	commands[c.name] = c
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: aoc <command> [flags]\n\ncommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nrun aoc <command> -h for its flags\n")
+}
+
+// findRoot - +root+ if it's set, otherwise the repository root above the
+// working directory
+func findRoot(root string, year int) (string, error) {
+	if root != "" {
+		return root, nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return aoc.FindRoot(wd, year)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	c, ok := commands[os.Args[1]]
+	if !ok {
+		if os.Args[1] != "-h" && os.Args[1] != "help" {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		}
+		usage()
+		os.Exit(2)
+	}
+	if err := c.run(os.Args[2:]); err != nil {
+		fmt.Printf("%s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	addCommand(&command{name: "run", summary: "run a day's solution", run: run})
+	addCommand(&command{name: "list", summary: "list the days there are solutions for", run: list})
+}
+
+// parts - the parts +part+ asks for: "a", "b" or (for "") both
+func parts(part string) ([]string, error) {
+	switch part = strings.ToLower(part); part {
+	case "":
+		return []string{"a", "b"}, nil
+	case "a", "b":
+		return []string{part}, nil
+	}
+	return nil, fmt.Errorf("unexpected part %q, expected a or b", part)
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	year := fs.Int("year", 0, "puzzle year")
+	day := fs.Int("day", 0, "puzzle day")
+	part := fs.String("part", "", "part to run, a or b (default both)")
+	root := fs.String("root", "", "repository root (default: found from the working directory)")
+	inputFile := fs.String("input", "", "input file (default: found under <root>/<year>/inputs)")
+	fs.Parse(args)
+
+	s, ok := aoc.Lookup(*year, *day)
+	if !ok {
+		return fmt.Errorf("no solution for %d day %d (try aoc list)", *year, *day)
+	}
+	ps, err := parts(*part)
+	if err != nil {
+		return err
+	}
+	for _, p := range ps {
+		answer, err := runPart(s, p, *root, *inputFile)
+		if err != nil {
+			return fmt.Errorf("%s part %s: %w", aoc.Name(s), p, err)
+		}
+		if strings.Contains(answer, "\n") {
+			answer = "\n" + answer
+		}
+		fmt.Printf("%s part %s: %s\n", aoc.Name(s), p, answer)
+	}
+	return nil
+}
+
+// runPart - run +part+ of +s+ on +inputFile+, or if that's empty the input
+// OpenInput finds under +root+
+func runPart(s aoc.Solver, part, root, inputFile string) (string, error) {
+	var input io.ReadCloser
+	if inputFile != "" {
+		f, err := os.Open(inputFile)
+		if err != nil {
+			return "", err
+		}
+		input = f
+	} else {
+		dir, err := findRoot(root, s.Year())
+		if err != nil {
+			return "", err
+		}
+		if input, _, err = aoc.OpenInput(dir, s, part); err != nil {
+			return "", err
+		}
+	}
+	defer input.Close()
+	return aoc.Run(s, part, input)
+}
+
+func list(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	year := fs.Int("year", 0, "only list this year (default all)")
+	root := fs.String("root", "", "repository root (default: found from the working directory)")
+	fs.Parse(args)
+
+	for _, s := range aoc.Solvers(*year) {
+		where := "no input"
+		if dir, err := findRoot(*root, s.Year()); err == nil {
+			if input, source, err := aoc.OpenInput(dir, s, "a"); err == nil {
+				input.Close()
+				where = source
+			}
+		}
+		fmt.Printf("%-12s %s\n", aoc.Name(s), where)
+	}
+	return nil
+}
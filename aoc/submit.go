@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"aoc"
+)
+
+func init() {
+	addCommand(&command{name: "submit", summary: "give the site an answer, unless the ledger says it's wrong", run: submit})
+}
+
+// solve - the answer +s+ gives for +part+, which has to be on one line to be
+// given to the site
+func solve(s aoc.Solver, part, root, inputFile string) (string, error) {
+	answer, err := runPart(s, part, root, inputFile)
+	if err != nil {
+		return "", err
+	}
+	if answer = strings.TrimSpace(answer); strings.Contains(answer, "\n") {
+		return "", fmt.Errorf("the answer has more than one line, read it and give it with -answer:\n%s", answer)
+	}
+	return answer, nil
+}
+
+// history - print every attempt in +l+
+func history(l *aoc.Ledger) {
+	if len(l.Attempts) == 0 {
+		fmt.Printf("%d day %d: no answers given\n", l.Year, l.Day)
+	}
+	for _, a := range l.Attempts {
+		fmt.Printf("%s part %s: %-12s %s\n", a.Time.Local().Format(time.DateTime), a.Part, a.Verdict, a.Answer)
+	}
+}
+
+func submit(args []string) error {
+	fs := flag.NewFlagSet("submit", flag.ExitOnError)
+	year := fs.Int("year", 0, "puzzle year")
+	day := fs.Int("day", 0, "puzzle day")
+	part := fs.String("part", "", "part the answer is for, a or b")
+	answer := fs.String("answer", "", "answer to give (default: run the solution, which with -fake is taken to be right)")
+	root := fs.String("root", "", "repository root (default: found from the working directory)")
+	inputFile := fs.String("input", "", "input file for the solution (default: found under <root>/<year>/inputs)")
+	ledgerRoot := fs.String("ledger", "", "directory the ledger is kept under, as <year>/ledger/dayNN.json (default: the repository root; a new temporary directory with -fake)")
+	showHistory := fs.Bool("history", false, "list the answers already given for the day instead")
+	cf := addClientFlags(fs)
+	fs.Parse(args)
+
+	if *year == 0 || *day < 1 || *day > 25 {
+		return errors.New("submit needs -year and a -day from 1 to 25")
+	}
+	dir, err := findRoot(*root, *year)
+	if err != nil {
+		return err
+	}
+	if *ledgerRoot == "" && !*cf.fake {
+		*ledgerRoot = dir
+	} else if *ledgerRoot == "" {
+		if *ledgerRoot, err = os.MkdirTemp("", "aoc-fake-"); err != nil {
+			return err
+		}
+		fmt.Printf("fake ledger goes in %s\n", *ledgerRoot)
+	}
+	ledger, err := aoc.LoadLedger(*ledgerRoot, *year, *day)
+	if err != nil {
+		return err
+	}
+	if *showHistory {
+		history(ledger)
+		return nil
+	}
+
+	if _, err := aoc.Level(*part); err != nil {
+		return err
+	}
+	*part = strings.ToLower(*part)
+	name := fmt.Sprintf("%d day %d part %s", *year, *day, *part)
+	s, solved := aoc.Lookup(*year, *day)
+	solution := ""
+	if *answer == "" || *cf.fake {
+		if !solved {
+			return fmt.Errorf("no solution for %d day %d, give the answer with -answer (and don't use -fake, which needs a solution to check it against)", *year, *day)
+		}
+		if solution, err = solve(s, *part, dir, *inputFile); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if *answer == "" {
+		*answer = solution
+	}
+	*answer = strings.TrimSpace(*answer)
+	if err := ledger.Check(*part, *answer, time.Now()); err != nil {
+		return fmt.Errorf("%s: not giving %s: %w", name, *answer, err)
+	}
+
+	client, server, err := cf.client()
+	if err != nil {
+		return err
+	}
+	if server != nil {
+		defer server.Close()
+		server.AnswerDelay = 5 * time.Second
+		// the stand in knows no better than the solution
+		server.SetAnswer(*year, *day, *part, solution)
+	}
+	r, err := client.Submit(*year, *day, *part, *answer)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	ledger.Record(*part, *answer, r, time.Now())
+	if err := ledger.Save(); err != nil {
+		return err
+	}
+
+	switch r.Verdict {
+	case aoc.Correct:
+		fmt.Printf("%s: %s is correct\n", name, *answer)
+	case aoc.Wait:
+		fmt.Printf("%s: %s wasn't checked, the site wants a wait of %s first\n", name, *answer, r.Wait)
+	case aoc.WrongLevel:
+		fmt.Printf("%s: %s wasn't checked, the part is already solved or not open yet\n", name, *answer)
+	default:
+		fmt.Printf("%s: %s is %s", name, *answer, r.Verdict)
+		if r.Wait > 0 {
+			fmt.Printf(", wait %s before the next answer", r.Wait)
+		}
+		fmt.Println()
+	}
+	if *cf.verbose {
+		fmt.Println(r.Message)
+	}
+	return nil
+}
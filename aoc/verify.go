@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"aoc"
+)
+
+func init() {
+	addCommand(&command{name: "verify", summary: "check the solutions against the known answers in inputs/", run: verify})
+}
+
+// years - the years with solutions, or just +year+ if it's set
+func years(year int) []int {
+	if year != 0 {
+		return []int{year}
+	}
+	var ret []int
+	for _, s := range aoc.Solvers(0) {
+		if len(ret) == 0 || ret[len(ret)-1] != s.Year() {
+			ret = append(ret, s.Year())
+		}
+	}
+	return ret
+}
+
+func verify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	year := fs.Int("year", 0, "only check this year (default all)")
+	day := fs.Int("day", 0, "only check this day (default all)")
+	root := fs.String("root", "", "repository root (default: found from the working directory)")
+	verbose := fs.Bool("v", false, "list the examples that pass too")
+	fs.Parse(args)
+
+	passed, failed := 0, 0
+	for _, y := range years(*year) {
+		dir, err := findRoot(*root, y)
+		if err != nil {
+			return err
+		}
+		outcomes, err := aoc.Verify(dir, y, *day)
+		if err != nil {
+			return err
+		}
+		for _, o := range outcomes {
+			switch {
+			case o.Solver == nil:
+				fmt.Printf("SKIP %d day %d %s: no solution\n", o.Year, o.Day, o.Name)
+				continue
+			case o.Passed():
+				passed++
+				if *verbose {
+					fmt.Printf("ok   %s %s\n", aoc.Name(o.Solver), o.Name)
+				}
+				continue
+			}
+			failed++
+			fmt.Printf("FAIL %s %s\n", aoc.Name(o.Solver), o.Name)
+			for _, m := range o.Mismatches {
+				fmt.Printf("    %s\n", strings.ReplaceAll(strings.TrimRight(m.String(), "\n"), "\n", "\n    "))
+			}
+		}
+	}
+	fmt.Printf("%d passed, %d failed\n", passed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d examples failed", failed, passed+failed)
+	}
+	return nil
+}